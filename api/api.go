@@ -5,6 +5,10 @@ import "errors"
 // BroadcastAddr64 64-bit broadcast address
 const BroadcastAddr64 uint64 = 0x000000000000FFFF
 
+// LocalAddr64 addresses the local XBee rather than a remote node, the address is
+// reserved and never assigned to a device
+const LocalAddr64 uint64 = 0xFFFFFFFFFFFFFFFF
+
 // BroadcastAddr16 16-bit broadcast address
 const BroadcastAddr16 uint16 = 0xFFFE
 
@@ -79,6 +83,30 @@ func APIEscapeMode(mode EscapeMode) func(interface{}) {
 	}
 }
 
+// Protocol defines the XBee firmware protocol family type
+type Protocol byte
+
+// Protocol families, frame types received and transmitted depend on the family
+const (
+	ProtocolZigbee   = Protocol(0)
+	ProtocolDigiMesh = Protocol(1)
+	Protocol802154   = Protocol(2)
+)
+
+// APIProtocolSetter interface for APIProtocol setters
+type APIProtocolSetter interface {
+	SetAPIProtocol(Protocol)
+}
+
+// APIProtocol options helper function for APIProtocolSetter
+func APIProtocol(protocol Protocol) func(interface{}) {
+	return func(i interface{}) {
+		if t, ok := i.(APIProtocolSetter); ok {
+			t.SetAPIProtocol(protocol)
+		}
+	}
+}
+
 // ShouldEscape should this byte be escaped
 func ShouldEscape(c byte) bool {
 	_, ok := escapeSet[c]
@@ -0,0 +1,331 @@
+// Package at catalogs the documented Zigbee, DigiMesh and 802.15.4 XBee AT
+// commands with the type, access and valid range of their parameters on each
+// protocol family, and encodes and decodes parameter values.
+package at
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+var (
+	// ErrType value is not of the command's parameter type
+	ErrType = errors.New("invalid parameter type")
+	// ErrRange value is outside the command's valid range
+	ErrRange = errors.New("parameter out of range")
+	// ErrAccess the command does not permit the access
+	ErrAccess = errors.New("parameter access not permitted")
+	// ErrLength parameter data is not a valid length for the command
+	ErrLength = errors.New("invalid parameter length")
+	// ErrProtocol the command is not supported by the protocol family
+	ErrProtocol = errors.New("command not supported by protocol")
+)
+
+// Type defines AT command parameter types
+type Type byte
+
+// Parameter types, TypeNone commands are executed and take no parameter
+const (
+	TypeNone     = Type(0)
+	TypeUint8    = Type(1)
+	TypeUint16   = Type(2)
+	TypeUint32   = Type(3)
+	TypeUint64   = Type(4)
+	TypeString   = Type(5)
+	TypeEnum     = Type(6)
+	TypeBitfield = Type(7)
+	TypeBytes    = Type(8)
+	TypeInt16    = Type(9)
+)
+
+// Access defines permitted AT command access
+type Access byte
+
+// Command access, a command without Read is write-only
+const (
+	Read      = Access(1 << 0)
+	Write     = Access(1 << 1)
+	Execute   = Access(1 << 2)
+	ReadWrite = Read | Write
+)
+
+// protocols supported protocol families mask
+type protocols byte
+
+const (
+	zb  = protocols(1 << api.ProtocolZigbee)
+	dm  = protocols(1 << api.ProtocolDigiMesh)
+	s1  = protocols(1 << api.Protocol802154)
+	all = zb | dm | s1
+)
+
+// Command an AT command and its parameter
+type Command struct {
+	Name        [2]byte
+	Description string
+	Type        Type
+	Access      Access
+	// Size numeric parameter size in bytes
+	Size int
+	// Min, Max numeric range, string and byte length range, for bitfields Max is
+	// the mask of valid bits, for TypeInt16 the int64 range converted to uint64
+	Min, Max uint64
+	// Values enumerated values and their meaning
+	Values map[uint64]string
+
+	protocols protocols
+	// variants the command on protocols where its type, range or access differ
+	variants map[api.Protocol]*Command
+}
+
+func (c *Command) String() string {
+	return string(c.Name[:])
+}
+
+// Readable can the parameter be read
+func (c *Command) Readable() bool {
+	return c.Access&Read != 0
+}
+
+// Writable can the parameter be written
+func (c *Command) Writable() bool {
+	return c.Access&Write != 0
+}
+
+// Supports is the command supported by the protocol family
+func (c *Command) Supports(protocol api.Protocol) bool {
+	return c.protocols&(1<<protocol) != 0
+}
+
+// For the command with the type, range and access it has on the protocol
+// family, false when the protocol does not support it
+func (c *Command) For(protocol api.Protocol) (*Command, bool) {
+	if !c.Supports(protocol) {
+		return nil, false
+	}
+
+	if v, ok := c.variants[protocol]; ok {
+		return v, true
+	}
+
+	return c, true
+}
+
+// Encode validates v and encodes it as the command's parameter. Numeric types
+// accept any Go integer, TypeString a string and TypeBytes a []byte.
+func (c *Command) Encode(v interface{}) ([]byte, error) {
+	switch c.Type {
+	case TypeNone:
+		if v != nil {
+			return nil, ErrType
+		}
+		return nil, nil
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, ErrType
+		}
+		if uint64(len(s)) < c.Min || uint64(len(s)) > c.Max {
+			return nil, ErrRange
+		}
+		return []byte(s), nil
+	case TypeBytes:
+		p, ok := v.([]byte)
+		if !ok {
+			return nil, ErrType
+		}
+		if uint64(len(p)) < c.Min || uint64(len(p)) > c.Max {
+			return nil, ErrRange
+		}
+		return append([]byte(nil), p...), nil
+	case TypeInt16:
+		n, ok := toInt64(v)
+		if !ok {
+			return nil, ErrType
+		}
+		if n < int64(c.Min) || n > int64(c.Max) {
+			return nil, ErrRange
+		}
+		return []byte{byte(n >> 8), byte(n)}, nil
+	}
+
+	n, ok := toUint64(v)
+	if !ok {
+		return nil, ErrType
+	}
+
+	if err := c.validate(n); err != nil {
+		return nil, err
+	}
+
+	p := make([]byte, 8)
+	binary.BigEndian.PutUint64(p, n)
+
+	return p[8-c.Size:], nil
+}
+
+// validate numeric value n against the command's range
+func (c *Command) validate(n uint64) error {
+	switch c.Type {
+	case TypeEnum:
+		if _, ok := c.Values[n]; !ok {
+			return ErrRange
+		}
+	case TypeBitfield:
+		if n&^c.Max != 0 {
+			return ErrRange
+		}
+	default:
+		if n < c.Min || n > c.Max {
+			return ErrRange
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes the command's parameter, numeric parameters decode to the
+// unsigned integer of the parameter's size, TypeInt16 to an int16, TypeString
+// to a string, TypeBytes to a []byte and TypeNone to nil
+func (c *Command) Decode(p []byte) (interface{}, error) {
+	switch c.Type {
+	case TypeNone:
+		return nil, nil
+	case TypeString:
+		return string(p), nil
+	case TypeBytes:
+		return append([]byte(nil), p...), nil
+	}
+
+	if len(p) == 0 || len(p) > c.Size {
+		return nil, ErrLength
+	}
+
+	var n uint64
+	for _, b := range p {
+		n = n<<8 | uint64(b)
+	}
+
+	if c.Type == TypeInt16 {
+		return int16(n), nil
+	}
+
+	switch c.Size {
+	case 1:
+		return uint8(n), nil
+	case 2:
+		return uint16(n), nil
+	case 4:
+		return uint32(n), nil
+	default:
+		return n, nil
+	}
+}
+
+// Format formats a decoded value of the command's parameter for display
+func (c *Command) Format(v interface{}) string {
+	switch c.Type {
+	case TypeString, TypeInt16:
+		return fmt.Sprint(v)
+	case TypeBytes:
+		return fmt.Sprintf("%X", v)
+	case TypeEnum:
+		n, _ := toUint64(v)
+		if s, ok := c.Values[n]; ok {
+			return fmt.Sprintf("%d (%s)", n, s)
+		}
+	}
+
+	return fmt.Sprintf("%#x", v)
+}
+
+// Parse parses a value of the command's parameter as formatted by Format,
+// numeric parameters parse to a uint64, TypeInt16 to an int64, and accept any
+// Go integer literal
+func (c *Command) Parse(s string) (interface{}, error) {
+	switch c.Type {
+	case TypeNone:
+		return nil, nil
+	case TypeString:
+		return s, nil
+	case TypeBytes:
+		p, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, ErrType
+		}
+		return p, nil
+	case TypeInt16:
+		n, err := strconv.ParseInt(s, 0, 16)
+		if err != nil {
+			return nil, ErrType
+		}
+		return n, nil
+	}
+
+	// enum values are formatted with their name following the number
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		s = s[:i]
+	}
+
+	n, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return nil, ErrType
+	}
+
+	return n, nil
+}
+
+// toInt64 converts Go integers to int64, values above math.MaxInt64 fail
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	}
+
+	n, ok := toUint64(v)
+
+	return int64(n), ok && n <= math.MaxInt64
+}
+
+// toUint64 converts Go integers to uint64, negative values fail
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint8:
+		return uint64(n), true
+	case uint16:
+		return uint64(n), true
+	case uint32:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	case uint:
+		return uint64(n), true
+	case int8:
+		return uint64(n), n >= 0
+	case int16:
+		return uint64(n), n >= 0
+	case int32:
+		return uint64(n), n >= 0
+	case int64:
+		return uint64(n), n >= 0
+	case int:
+		return uint64(n), n >= 0
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,228 @@
+package at
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+type encodeTest struct {
+	name     string
+	cmd      *Command
+	input    interface{}
+	expected []byte
+	err      error
+}
+
+var encodeTests = []encodeTest{
+	{"Uint64", PanID, uint64(0x0123456789ABCDEF), []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, nil},
+	{"Uint64 From int", PanID, 0x1234, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12, 0x34}, nil},
+	{"Uint8", Channel, 0x0B, []byte{0x0B}, nil},
+	{"Uint8 Below Range", Channel, 0x0A, nil, ErrRange},
+	{"Uint8 Above Range", Channel, 0x1B, nil, ErrRange},
+	{"Uint16", PWM0DutyCycle, uint16(0x03FF), []byte{0x03, 0xFF}, nil},
+	{"Uint16 Above Range", PWM0DutyCycle, 0x0400, nil, ErrRange},
+	{"Uint32", DestinationAddrLow, uint32(0x0000FFFF), []byte{0x00, 0x00, 0xFF, 0xFF}, nil},
+	{"Negative", PanID, -1, nil, ErrType},
+	{"Wrong Type", PanID, "1234", nil, ErrType},
+	{"Enum", APIEnable, 2, []byte{0x02}, nil},
+	{"Enum Unknown Value", APIEnable, 3, nil, ErrRange},
+	{"Bitfield", DiscoveryOptions, 0x03, []byte{0x03}, nil},
+	{"Bitfield Invalid Bits", DiscoveryOptions, 0x08, nil, ErrRange},
+	{"String", NodeIdentifier, "PUMP-3", []byte("PUMP-3"), nil},
+	{"String Too Long", NodeIdentifier, "ABCDEFGHIJKLMNOPQRSTU", nil, ErrRange},
+	{"Bytes", LinkKey, []byte{0x01, 0x02}, []byte{0x01, 0x02}, nil},
+	{"Bytes Too Long", LinkKey, make([]byte, 17), nil, ErrRange},
+	{"None", ApplyChanges, nil, nil, nil},
+	{"None With Value", ApplyChanges, 1, nil, ErrType},
+	{"Int16", Temperature, -5, []byte{0xFF, 0xFB}, nil},
+	{"Int16 Above Range", Temperature, 0x8000, nil, ErrRange},
+}
+
+func TestCommand_Encode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Encode Test Suite", func(t *testing.T) {
+		for _, tt := range encodeTests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := tt.cmd.Encode(tt.input)
+				if err != tt.err {
+					t.Fatalf("Expected error=%v, but got %v", tt.err, err)
+				}
+				if !reflect.DeepEqual(actual, tt.expected) {
+					t.Fatalf("Expected %#v, but got %#v", tt.expected, actual)
+				}
+			})
+		}
+	})
+}
+
+type decodeTest struct {
+	name     string
+	cmd      *Command
+	input    []byte
+	expected interface{}
+	err      error
+}
+
+var decodeTests = []decodeTest{
+	{"Uint64", PanID, []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}, uint64(0x0123456789ABCDEF), nil},
+	{"Uint64 Short", PanID, []byte{0x12, 0x34}, uint64(0x1234), nil},
+	{"Uint8", Channel, []byte{0x0B}, uint8(0x0B), nil},
+	{"Uint16", FirmwareVersion, []byte{0x10, 0x0A}, uint16(0x100A), nil},
+	{"Uint32", SerialNumberHigh, []byte{0x00, 0x13, 0xA2, 0x00}, uint32(0x0013A200), nil},
+	{"Too Long", Channel, []byte{0x00, 0x0B}, nil, ErrLength},
+	{"Empty", Channel, nil, nil, ErrLength},
+	{"Enum", APIEnable, []byte{0x01}, uint8(1), nil},
+	{"Bitfield", ScanChannels, []byte{0x7F, 0xFF}, uint16(0x7FFF), nil},
+	{"String", NodeIdentifier, []byte("PUMP-3"), "PUMP-3", nil},
+	{"None", ApplyChanges, nil, nil, nil},
+	{"Int16", Temperature, []byte{0xFF, 0xFB}, int16(-5), nil},
+}
+
+func TestCommand_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Decode Test Suite", func(t *testing.T) {
+		for _, tt := range decodeTests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := tt.cmd.Decode(tt.input)
+				if err != tt.err {
+					t.Fatalf("Expected error=%v, but got %v", tt.err, err)
+				}
+				if !reflect.DeepEqual(actual, tt.expected) {
+					t.Fatalf("Expected %#v, but got %#v", tt.expected, actual)
+				}
+			})
+		}
+	})
+}
+
+func TestCommand_Parse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cmd      *Command
+		input    string
+		expected interface{}
+		err      error
+	}{
+		{"Uint64", PanID, "0x1234", uint64(0x1234), nil},
+		{"Decimal", Channel, "15", uint64(15), nil},
+		{"Enum", CoordinatorEnable, CoordinatorEnable.Format(uint8(1)), uint64(1), nil},
+		{"String", NodeIdentifier, "PUMP-3", "PUMP-3", nil},
+		{"Bytes", LinkKey, "0A0B", []byte{0x0A, 0x0B}, nil},
+		{"Invalid", Channel, "CH", nil, ErrType},
+		{"Int16", Temperature, Temperature.Format(int16(-5)), int64(-5), nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tt.cmd.Parse(tt.input)
+			if err != tt.err {
+				t.Fatalf("Expected error=%v, but got %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("Expected %#v, but got %#v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	c, ok := Lookup("ID")
+	if !ok || c != PanID {
+		t.Fatal("Expected ID to be PanID")
+	}
+
+	if _, ok := Lookup("ZZ"); ok {
+		t.Fatal("Expected ZZ not to be catalogued")
+	}
+
+	commands := Commands()
+	for i := 1; i < len(commands); i++ {
+		if commands[i-1].String() >= commands[i].String() {
+			t.Fatalf("Expected commands ordered by unique name, but got %s before %s", commands[i-1], commands[i])
+		}
+	}
+
+	if LinkKey.Readable() || !LinkKey.Writable() {
+		t.Fatal("Expected KY to be write-only")
+	}
+	if !ParentAddr.Supports(api.ProtocolZigbee) || ParentAddr.Supports(api.Protocol802154) {
+		t.Fatal("Expected MP to be supported by Zigbee only")
+	}
+	if APIEnable.Format(uint8(2)) != "2 (API mode with escaping)" {
+		t.Fatalf("Expected enum format, but got %s", APIEnable.Format(uint8(2)))
+	}
+}
+
+func TestCommand_For(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cmd      *Command
+		protocol api.Protocol
+		input    interface{}
+		err      error
+	}{
+		{"Zigbee 64-bit PAN ID", PanID, api.ProtocolZigbee, uint64(0x0123456789ABCDEF), nil},
+		{"DigiMesh PAN ID", PanID, api.ProtocolDigiMesh, 0x7FFF, nil},
+		{"DigiMesh PAN ID Above Range", PanID, api.ProtocolDigiMesh, 0x8000, ErrRange},
+		{"802.15.4 PAN ID Above Range", PanID, api.Protocol802154, 0x10000, ErrRange},
+		{"802.15.4 Pin Doze", SleepMode, api.Protocol802154, 2, nil},
+		{"Zigbee Pin Doze", SleepMode, api.ProtocolZigbee, 2, ErrRange},
+		{"DigiMesh Synchronous Sleep", SleepMode, api.ProtocolDigiMesh, 8, nil},
+		{"Zigbee Synchronous Sleep", SleepMode, api.ProtocolZigbee, 8, ErrRange},
+		{"Zigbee 16-bit Pull-up", PullUp, api.ProtocolZigbee, 0x3FFF, nil},
+		{"802.15.4 16-bit Pull-up", PullUp, api.Protocol802154, 0x3FFF, ErrRange},
+		{"802.15.4 8-bit Pull-up", PullUp, api.Protocol802154, 0xFF, nil},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, ok := tt.cmd.For(tt.protocol)
+			if !ok {
+				t.Fatalf("Expected %s to be supported", tt.cmd)
+			}
+			if _, err := c.Encode(tt.input); err != tt.err {
+				t.Fatalf("Expected error=%v, but got %v", tt.err, err)
+			}
+		})
+	}
+
+	if c, _ := NetworkAddr.For(api.ProtocolZigbee); c.Writable() {
+		t.Fatal("Expected MY to be read-only on Zigbee")
+	}
+	if c, _ := NetworkAddr.For(api.Protocol802154); !c.Writable() {
+		t.Fatal("Expected MY to be writable on 802.15.4")
+	}
+	if _, ok := ParentAddr.For(api.ProtocolDigiMesh); ok {
+		t.Fatal("Expected MP not to be supported on DigiMesh")
+	}
+	if c, _ := PullUp.For(api.Protocol802154); c.Size != 1 {
+		t.Fatalf("Expected PR to be 1 byte on 802.15.4, but got %d", c.Size)
+	}
+	if InvokeBootloader.Supports(api.Protocol802154) {
+		t.Fatal("Expected %P not to be supported on 802.15.4")
+	}
+}
@@ -0,0 +1,412 @@
+package at
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+var pinModes = map[uint64]string{
+	0: "disabled",
+	1: "special function",
+	2: "analog input or PWM output",
+	3: "digital input",
+	4: "digital output, low",
+	5: "digital output, high",
+	6: "special function",
+	7: "special function",
+}
+
+var enabled = map[uint64]string{
+	0: "disabled",
+	1: "enabled",
+}
+
+// Addressing
+var (
+	DestinationAddrHigh  = numeric("DH", "destination address high", TypeUint32, ReadWrite, 0, math.MaxUint32, all)
+	DestinationAddrLow   = numeric("DL", "destination address low", TypeUint32, ReadWrite, 0, math.MaxUint32, all)
+	NetworkAddr          = numeric("MY", "16-bit network address", TypeUint16, Read, 0, math.MaxUint16, zb|dm).on(s1, access(ReadWrite))
+	ParentAddr           = numeric("MP", "16-bit parent network address", TypeUint16, Read, 0, math.MaxUint16, zb)
+	RemainingChildren    = numeric("NC", "number of remaining children", TypeUint8, Read, 0, math.MaxUint8, zb)
+	SerialNumberHigh     = numeric("SH", "serial number high", TypeUint32, Read, 0, math.MaxUint32, all)
+	SerialNumberLow      = numeric("SL", "serial number low", TypeUint32, Read, 0, math.MaxUint32, all)
+	NodeIdentifier       = text("NI", "node identifier", ReadWrite, 0, 20, all)
+	SourceEndpoint       = numeric("SE", "source endpoint", TypeUint8, ReadWrite, 0, math.MaxUint8, zb|dm)
+	DestinationEndpoint  = numeric("DE", "destination endpoint", TypeUint8, ReadWrite, 0, math.MaxUint8, zb|dm)
+	ClusterID            = numeric("CI", "cluster ID", TypeUint16, ReadWrite, 0, math.MaxUint16, zb|dm)
+	TransmitOptions      = bitfield("TO", "transmit options", 1, ReadWrite, 0xFF, zb|dm)
+	MaxPayload           = numeric("NP", "maximum RF payload bytes", TypeUint16, Read, 0, math.MaxUint16, all)
+	DeviceTypeIdentifier = numeric("DD", "device type identifier", TypeUint32, ReadWrite, 0, math.MaxUint32, all)
+	ConflictReport       = numeric("CR", "PAN conflict threshold", TypeUint8, ReadWrite, 1, 0x3F, zb)
+	DiscoveryOptions     = bitfield("NO", "node discovery options", 1, ReadWrite, 0x07, all)
+	DiscoveryTimeout     = numeric("NT", "node discovery timeout, 100 ms", TypeUint16, ReadWrite, 0x20, 0xFF, zb).
+				on(dm, ranged(TypeUint16, 0x20, 0x2EE0)).
+				on(s1, ranged(TypeUint16, 0x01, 0xFC))
+	NodeDiscover    = text("ND", "node discover", Execute, 0, 20, all)
+	DestinationNode = text("DN", "discover destination node", Execute, 1, 20, all)
+	FindNeighbors   = text("FN", "find neighbors", Execute, 0, 20, dm)
+)
+
+// Networking
+var (
+	PanID = numeric("ID", "PAN ID", TypeUint64, ReadWrite, 0, math.MaxUint64, zb).
+		on(dm, ranged(TypeUint16, 0, 0x7FFF)).
+		on(s1, ranged(TypeUint16, 0, math.MaxUint16))
+	InitialPanID        = numeric("II", "initial 16-bit PAN ID", TypeUint16, ReadWrite, 0, math.MaxUint16, zb)
+	OperatingPanID      = numeric("OP", "operating 64-bit PAN ID", TypeUint64, Read, 0, math.MaxUint64, zb)
+	OperatingPanID16    = numeric("OI", "operating 16-bit PAN ID", TypeUint16, Read, 0, math.MaxUint16, zb)
+	Channel             = numeric("CH", "operating channel", TypeUint8, ReadWrite, 0x0B, 0x1A, all)
+	ScanChannels        = bitfield("SC", "scan channels", 2, ReadWrite, 0xFFFF, zb|s1)
+	ScanDuration        = numeric("SD", "scan duration exponent", TypeUint8, ReadWrite, 0, 0x0F, zb|s1)
+	StackProfile        = numeric("ZS", "Zigbee stack profile", TypeUint8, ReadWrite, 0, 2, zb)
+	JoinTime            = numeric("NJ", "node join time, seconds", TypeUint8, ReadWrite, 0, math.MaxUint8, zb)
+	NetworkWatchdog     = numeric("NW", "network watchdog timeout, minutes", TypeUint16, ReadWrite, 0, 0x64FF, zb)
+	ChannelVerification = enum("JV", "coordinator join verification", ReadWrite, zb, enabled)
+	JoinNotification    = enum("JN", "join notification", ReadWrite, zb, enabled)
+	DisableJoining      = enum("DJ", "disable joining", ReadWrite, zb, map[uint64]string{
+		0: "joining enabled",
+		1: "joining disabled",
+	})
+	CoordinatorEnable = enum("CE", "coordinator enable, DigiMesh routing mode", ReadWrite, zb, map[uint64]string{
+		0: "router",
+		1: "coordinator",
+	}).on(dm, values(map[uint64]string{
+		0: "standard router",
+		1: "indirect messaging coordinator",
+		2: "non-routing module",
+	})).on(s1, values(map[uint64]string{
+		0: "end device",
+		1: "coordinator",
+	}))
+	AssociationIndication = numeric("AI", "association indication", TypeUint8, Read, 0, math.MaxUint8, all)
+	Disassociate          = execute("DA", "force disassociation", zb)
+	ActiveScan            = execute("AS", "active scan", zb|s1)
+	MaxHops               = numeric("NH", "maximum unicast hops", TypeUint8, ReadWrite, 0, math.MaxUint8, zb|dm)
+	BroadcastRadius       = numeric("BH", "broadcast radius", TypeUint8, ReadWrite, 0, 0x20, zb|dm)
+	AggregateRouting      = numeric("AR", "many-to-one route broadcast time, 10 s", TypeUint8, ReadWrite, 0, math.MaxUint8, zb)
+	NetworkReset          = enum("NR", "network reset", Write, zb|dm, map[uint64]string{
+		0: "reset the local node",
+		1: "reset the entire network",
+	})
+	CommissioningButton = numeric("CB", "commissioning pushbutton presses", TypeUint8, Write, 0, 4, zb|dm)
+	RSSI                = numeric("DB", "received signal strength of the last hop, -dBm", TypeUint8, Read, 0, math.MaxUint8, all)
+	ACKFailures         = numeric("EA", "MAC ACK failures", TypeUint16, ReadWrite, 0, math.MaxUint16, all)
+	PowerLevel          = enum("PL", "transmit power level", ReadWrite, all, map[uint64]string{
+		0: "lowest",
+		1: "low",
+		2: "medium",
+		3: "high",
+		4: "highest",
+	})
+	PowerMode = enum("PM", "power mode", ReadWrite, zb, map[uint64]string{
+		0: "boost mode disabled",
+		1: "boost mode enabled",
+	})
+	PeakPower = numeric("PP", "output power at PL4, dBm", TypeUint8, Read, 0, math.MaxUint8, all)
+)
+
+// DigiMesh
+var (
+	MultiTransmit     = numeric("MT", "broadcast multi-transmits", TypeUint8, ReadWrite, 0, 0x0F, dm)
+	UnicastRetries    = numeric("RR", "unicast MAC retries", TypeUint8, ReadWrite, 0, 0x0F, dm|s1)
+	NetworkDelaySlots = numeric("NN", "network delay slots", TypeUint8, ReadWrite, 1, 5, dm)
+	MeshRetries       = numeric("MR", "mesh unicast retries", TypeUint8, ReadWrite, 0, 7, dm)
+	UnicastHopTime    = numeric("%H", "MAC unicast one hop time, ms", TypeUint16, Read, 0, math.MaxUint16, dm)
+	BroadcastHopTime  = numeric("%8", "MAC broadcast one hop time, ms", TypeUint16, Read, 0, math.MaxUint16, dm)
+)
+
+// 802.15.4
+var (
+	EndDeviceAssociation   = bitfield("A1", "end device association", 1, ReadWrite, 0x0F, s1)
+	CoordinatorAssociation = bitfield("A2", "coordinator association", 1, ReadWrite, 0x07, s1)
+	RandomDelaySlots       = numeric("RN", "random delay slots", TypeUint8, ReadWrite, 0, 3, s1)
+	MACMode                = enum("MM", "MAC mode", ReadWrite, s1, map[uint64]string{
+		0: "Digi mode",
+		1: "802.15.4, no ACKs",
+		2: "802.15.4, with ACKs",
+		3: "Digi mode, no ACKs",
+	})
+	SamplesBeforeTX = numeric("IT", "samples before transmit", TypeUint8, ReadWrite, 1, math.MaxUint8, s1)
+	IOOutputEnable  = enum("IU", "IO output enable", ReadWrite, s1, enabled)
+	IOInputAddr     = numeric("IA", "IO input address", TypeUint64, ReadWrite, 0, math.MaxUint64, s1)
+	CCAThreshold    = numeric("CA", "clear channel assessment threshold, -dBm", TypeUint8, ReadWrite, 0, 0x50, s1)
+	CCAFailures     = numeric("EC", "clear channel assessment failures", TypeUint16, ReadWrite, 0, math.MaxUint16, s1)
+	ForcePoll       = execute("FP", "force poll", s1)
+	EnergyDetect    = execute("ED", "energy detect", s1)
+)
+
+// Security
+var (
+	EncryptionEnable  = enum("EE", "encryption enable", ReadWrite, all, enabled)
+	EncryptionOptions = bitfield("EO", "encryption options", 1, ReadWrite, 0xFF, zb|dm)
+	LinkKey           = raw("KY", "encryption link key", Write, 0, 16, all)
+	NetworkKey        = raw("NK", "network encryption key", Write, 0, 16, zb)
+)
+
+// Serial interfacing
+var (
+	BaudRate = enum("BD", "interface data rate", ReadWrite, all, map[uint64]string{
+		0: "1200 b/s",
+		1: "2400 b/s",
+		2: "4800 b/s",
+		3: "9600 b/s",
+		4: "19200 b/s",
+		5: "38400 b/s",
+		6: "57600 b/s",
+		7: "115200 b/s",
+		8: "230400 b/s",
+	})
+	Parity = enum("NB", "parity", ReadWrite, all, map[uint64]string{
+		0: "none",
+		1: "even",
+		2: "odd",
+		3: "mark",
+	})
+	StopBits = enum("SB", "stop bits", ReadWrite, zb|dm, map[uint64]string{
+		0: "one stop bit",
+		1: "two stop bits",
+	})
+	PacketizationTimeout = numeric("RO", "packetization timeout, character times", TypeUint8, ReadWrite, 0, math.MaxUint8, all)
+	APIEnable            = enum("AP", "API enable", ReadWrite, all, map[uint64]string{
+		0: "transparent mode",
+		1: "API mode",
+		2: "API mode with escaping",
+	})
+	APIOptions           = bitfield("AO", "API options", 1, ReadWrite, 0xFF, all)
+	FlowControlThreshold = numeric("FT", "flow control threshold, bytes", TypeUint16, ReadWrite, 0x11, 0x16F, zb|dm)
+)
+
+// Command mode
+var (
+	CommandModeTimeout = numeric("CT", "command mode timeout, 100 ms", TypeUint16, ReadWrite, 2, 0x1770, zb|dm).
+				on(s1, ranged(TypeUint16, 2, math.MaxUint16))
+	GuardTimes          = numeric("GT", "guard times, ms", TypeUint16, ReadWrite, 1, 0x0CE4, zb|dm).on(s1, ranged(TypeUint16, 2, 0x0CE4))
+	CommandSequenceChar = numeric("CC", "command sequence character", TypeUint8, ReadWrite, 0, math.MaxUint8, all)
+	ExitCommandMode     = execute("CN", "exit command mode", all)
+)
+
+// IO
+var (
+	DIO0Config  = enum("D0", "DIO0/AD0 configuration", ReadWrite, all, pinModes)
+	DIO1Config  = enum("D1", "DIO1/AD1 configuration", ReadWrite, all, pinModes)
+	DIO2Config  = enum("D2", "DIO2/AD2 configuration", ReadWrite, all, pinModes)
+	DIO3Config  = enum("D3", "DIO3/AD3 configuration", ReadWrite, all, pinModes)
+	DIO4Config  = enum("D4", "DIO4 configuration", ReadWrite, all, pinModes)
+	DIO5Config  = enum("D5", "DIO5 configuration", ReadWrite, all, pinModes)
+	DIO6Config  = enum("D6", "DIO6 configuration", ReadWrite, all, pinModes)
+	DIO7Config  = enum("D7", "DIO7 configuration", ReadWrite, all, pinModes)
+	DIO8Config  = enum("D8", "DIO8 configuration", ReadWrite, all, pinModes)
+	DIO9Config  = enum("D9", "DIO9 configuration", ReadWrite, zb|dm, pinModes)
+	DIO10Config = enum("P0", "DIO10 configuration", ReadWrite, all, pinModes)
+	DIO11Config = enum("P1", "DIO11 configuration", ReadWrite, all, pinModes)
+	DIO12Config = enum("P2", "DIO12 configuration", ReadWrite, zb|dm, pinModes)
+	DIO13Config = enum("P3", "DIO13/DOUT configuration", ReadWrite, zb|dm, pinModes)
+	DIO14Config = enum("P4", "DIO14/DIN configuration", ReadWrite, zb|dm, pinModes)
+
+	DIO0Timeout = numeric("T0", "D0 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO1Timeout = numeric("T1", "D1 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO2Timeout = numeric("T2", "D2 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO3Timeout = numeric("T3", "D3 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO4Timeout = numeric("T4", "D4 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO5Timeout = numeric("T5", "D5 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO6Timeout = numeric("T6", "D6 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DIO7Timeout = numeric("T7", "D7 output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+
+	PullUp          = bitfield("PR", "pull-up resistor enable", 2, ReadWrite, 0xFFFF, zb|dm).on(s1, bits(1, 0xFF))
+	PullDirection   = bitfield("PD", "pull direction", 2, ReadWrite, 0xFFFF, zb|dm)
+	AssocLEDBlink   = numeric("LT", "associate LED blink time, 10 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, all)
+	RSSIPWMTimer    = numeric("RP", "RSSI PWM timer, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, all)
+	SampleRate      = numeric("IR", "IO sample rate, ms", TypeUint16, ReadWrite, 0, math.MaxUint16, all)
+	ChangeDetect    = bitfield("IC", "digital change detection", 2, ReadWrite, 0xFFFF, all)
+	SupplyThreshold = numeric("V+", "supply voltage high threshold, mV", TypeUint16, ReadWrite, 0, math.MaxUint16, zb|dm)
+	PWM0DutyCycle   = numeric("M0", "PWM0 duty cycle", TypeUint16, ReadWrite, 0, 0x03FF, all)
+	PWM1DutyCycle   = numeric("M1", "PWM1 duty cycle", TypeUint16, ReadWrite, 0, 0x03FF, all)
+	PWMTimeout      = numeric("PT", "PWM output timeout, 100 ms", TypeUint8, ReadWrite, 0, math.MaxUint8, s1)
+	DigitalOutput   = bitfield("IO", "digital output level", 1, Write, 0xFF, s1)
+	ForceSample     = execute("IS", "force sample", all)
+	SupplyVoltage   = numeric("%V", "supply voltage, mV", TypeUint16, Read, 0, math.MaxUint16, all)
+	Temperature     = signed("TP", "module temperature, C", Read, math.MinInt16, math.MaxInt16, all)
+	DeviceOptions   = bitfield("DO", "device options", 1, ReadWrite, 0xFF, zb|dm)
+)
+
+// Diagnostics
+var (
+	FirmwareVersion = numeric("VR", "firmware version", TypeUint16, Read, 0, math.MaxUint16, all)
+	HardwareVersion = numeric("HV", "hardware version", TypeUint16, Read, 0, math.MaxUint16, all)
+	ConfigChecksum  = numeric("CK", "configuration checksum", TypeUint16, Read, 0, math.MaxUint16, zb|dm)
+	VersionLong     = text("VL", "firmware version, verbose", Read, 0, math.MaxUint8, s1)
+	ReceivedErrors  = numeric("ER", "received error count", TypeUint16, ReadWrite, 0, math.MaxUint16, dm)
+	GoodPackets     = numeric("GD", "good packets received", TypeUint16, ReadWrite, 0, math.MaxUint16, dm)
+	TransmitErrors  = numeric("TR", "transmission failure count", TypeUint16, ReadWrite, 0, math.MaxUint16, dm)
+	UnicastAttempts = numeric("UA", "MAC unicast transmission count", TypeUint16, ReadWrite, 0, math.MaxUint16, dm)
+)
+
+// Sleep
+var (
+	SleepMode = enum("SM", "sleep mode", ReadWrite, zb, map[uint64]string{
+		0: "no sleep",
+		1: "pin hibernate",
+		4: "cyclic sleep",
+		5: "cyclic sleep, pin wake",
+	}).on(dm, values(map[uint64]string{
+		0: "no sleep",
+		1: "pin hibernate",
+		4: "cyclic sleep",
+		5: "cyclic sleep, pin wake",
+		7: "sleep support",
+		8: "synchronous cyclic sleep",
+	})).on(s1, values(map[uint64]string{
+		0: "no sleep",
+		1: "pin hibernate",
+		2: "pin doze",
+		4: "cyclic sleep",
+		5: "cyclic sleep, pin wake",
+	}))
+	SleepPeriods    = numeric("SN", "number of sleep periods", TypeUint16, ReadWrite, 1, math.MaxUint16, zb|dm)
+	SleepPeriod     = numeric("SP", "sleep period, 10 ms", TypeUint32, ReadWrite, 0, 0x1440000, all)
+	TimeBeforeSleep = numeric("ST", "time before sleep, ms", TypeUint32, ReadWrite, 1, 0x36EE80, all)
+	SleepOptions    = bitfield("SO", "sleep options", 1, ReadWrite, 0xFF, all)
+	WakeHost        = numeric("WH", "wake host delay, ms", TypeUint16, ReadWrite, 0, math.MaxUint16, zb|dm)
+	PollingRate     = numeric("PO", "end device polling rate, 10 ms", TypeUint16, ReadWrite, 0, 0x3E8, zb)
+	DisassocPeriod  = numeric("DP", "disassociated cyclic sleep period, 10 ms", TypeUint16, ReadWrite, 1, 0x68B0, s1)
+	SleepStatus     = bitfield("SS", "sleep status", 2, Read, 0xFFFF, dm)
+	SleepTime       = numeric("OS", "operating sleep time, 10 ms", TypeUint32, Read, 0, math.MaxUint32, dm)
+	WakeTime        = numeric("OW", "operating wake time, ms", TypeUint32, Read, 0, math.MaxUint32, dm)
+	MissedSyncs     = numeric("MS", "missed sync messages", TypeUint16, Read, 0, math.MaxUint16, dm)
+	MissedSleeps    = numeric("SQ", "missed sleep sync count", TypeUint16, ReadWrite, 0, math.MaxUint16, dm)
+)
+
+// Execution
+var (
+	ApplyChanges     = execute("AC", "apply changes", all)
+	WriteChanges     = execute("WR", "write to non-volatile memory", all)
+	RestoreDefaults  = execute("RE", "restore defaults", all)
+	SoftwareReset    = execute("FR", "software reset", all)
+	SleepImmediately = execute("SI", "sleep immediately", zb|dm)
+	InvokeBootloader = execute("%P", "invoke bootloader, XBee 3 only", zb|dm)
+)
+
+var catalog = make(map[string]*Command)
+
+// Lookup command by name
+func Lookup(name string) (*Command, bool) {
+	c, ok := catalog[name]
+	return c, ok
+}
+
+// Commands every catalogued command, ordered by name
+func Commands() []*Command {
+	commands := make([]*Command, 0, len(catalog))
+	for _, c := range catalog {
+		commands = append(commands, c)
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].String() < commands[j].String()
+	})
+
+	return commands
+}
+
+func register(c *Command) *Command {
+	catalog[c.String()] = c
+	return c
+}
+
+func name(s string) [2]byte {
+	return [2]byte{s[0], s[1]}
+}
+
+// numericSize parameter size in bytes of numeric types
+func numericSize(t Type) int {
+	switch t {
+	case TypeUint16, TypeInt16:
+		return 2
+	case TypeUint32:
+		return 4
+	case TypeUint64:
+		return 8
+	default:
+		return 1
+	}
+}
+
+func numeric(cmd, description string, t Type, access Access, min, max uint64, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: t, Access: access, Size: numericSize(t), Min: min, Max: max, protocols: p})
+}
+
+func signed(cmd, description string, access Access, min, max int64, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeInt16, Access: access, Size: numericSize(TypeInt16), Min: uint64(min), Max: uint64(max), protocols: p})
+}
+
+func bitfield(cmd, description string, size int, access Access, mask uint64, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeBitfield, Access: access, Size: size, Max: mask, protocols: p})
+}
+
+func enum(cmd, description string, access Access, p protocols, values map[uint64]string) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeEnum, Access: access, Size: 1, Values: values, protocols: p})
+}
+
+func text(cmd, description string, access Access, min, max uint64, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeString, Access: access, Min: min, Max: max, protocols: p})
+}
+
+func raw(cmd, description string, access Access, min, max uint64, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeBytes, Access: access, Min: min, Max: max, protocols: p})
+}
+
+func execute(cmd, description string, p protocols) *Command {
+	return register(&Command{Name: name(cmd), Description: description, Type: TypeNone, Access: Execute, protocols: p})
+}
+
+// on declares the command on the protocols p, changed by change from how it
+// was declared
+func (c *Command) on(p protocols, change func(*Command)) *Command {
+	if c.variants == nil {
+		c.variants = make(map[api.Protocol]*Command)
+	}
+
+	for _, protocol := range []api.Protocol{api.ProtocolZigbee, api.ProtocolDigiMesh, api.Protocol802154} {
+		if p&(1<<protocol) == 0 {
+			continue
+		}
+
+		v := *c
+		v.variants = nil
+		change(&v)
+		c.variants[protocol] = &v
+	}
+	c.protocols |= p
+
+	return c
+}
+
+// ranged changes a numeric command's type and range
+func ranged(t Type, min, max uint64) func(*Command) {
+	return func(c *Command) {
+		c.Type, c.Size, c.Min, c.Max = t, numericSize(t), min, max
+	}
+}
+
+// access changes a command's access
+func access(a Access) func(*Command) {
+	return func(c *Command) {
+		c.Access = a
+	}
+}
+
+// bits changes a bitfield command's size and mask
+func bits(size int, mask uint64) func(*Command) {
+	return func(c *Command) {
+		c.Size, c.Max = size, mask
+	}
+}
+
+// values changes an enum command's values
+func values(v map[uint64]string) func(*Command) {
+	return func(c *Command) {
+		c.Values = v
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+// DIO defines a digital IO line
+type DIO byte
+
+// Digital IO lines
+const (
+	DIO0  = DIO(0)
+	DIO1  = DIO(1)
+	DIO2  = DIO(2)
+	DIO3  = DIO(3)
+	DIO4  = DIO(4)
+	DIO5  = DIO(5)
+	DIO6  = DIO(6)
+	DIO7  = DIO(7)
+	DIO8  = DIO(8)
+	DIO9  = DIO(9)
+	DIO10 = DIO(10)
+	DIO11 = DIO(11)
+	DIO12 = DIO(12)
+)
+
+// AD defines an analog input line
+type AD byte
+
+// Analog input lines, SupplyVoltage is sampled when enabled with V+
+const (
+	AD0           = AD(0)
+	AD1           = AD(1)
+	AD2           = AD(2)
+	AD3           = AD(3)
+	AD4           = AD(4)
+	AD5           = AD(5)
+	SupplyVoltage = AD(7)
+)
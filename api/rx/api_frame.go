@@ -23,15 +23,21 @@ func New(options ...func(interface{})) *APIFrame {
 
 // APIFrame defines an RX API frame
 type APIFrame struct {
-	mode  api.EscapeMode
-	state state
-	frame Frame
+	mode     api.EscapeMode
+	protocol api.Protocol
+	state    state
+	frame    Frame
 }
 
 func (f *APIFrame) SetAPIEscapeMode(mode api.EscapeMode) {
 	f.mode = mode
 }
 
+// SetAPIProtocol satisfy APIProtocolSetter interface
+func (f *APIFrame) SetAPIProtocol(protocol api.Protocol) {
+	f.protocol = protocol
+}
+
 // RX receive byte
 func (f *APIFrame) RX(c byte) (Frame, error) {
 	if f.shouldEscapeNext(c) {
@@ -90,7 +96,7 @@ func (f *APIFrame) handleStateFrame(c byte) error {
 
 func (f *APIFrame) handleStateAPIID(c byte) error {
 	var err error
-	f.frame, err = NewFrameForAPIID(c)
+	f.frame, err = NewFrameForProtocolAPIID(f.protocol, c)
 	if err != nil {
 		f.state.state = api.FrameStart
 		return err
@@ -7,12 +7,11 @@ const (
 
 	ioSampleAddr64Offset            = 0
 	ioSampleAddr16Offset            = 8
-	ioSampleOptionsOffset           = 9
-	ioSampleSampleCountOffset       = 10
-	ioSampleDigitalSampleMaskOffset = 11
-	ioSampleAnalogSampleMaskOffset  = 13
-	ioSampleDigitalSamplesOffset    = 14
-	ioSampleAnalogSampleOffset      = 16
+	ioSampleOptionsOffset           = 10
+	ioSampleSampleCountOffset       = 11
+	ioSampleDigitalSampleMaskOffset = 12
+	ioSampleAnalogSampleMaskOffset  = 14
+	ioSampleDigitalSamplesOffset    = 15
 )
 
 type IOSample struct {
@@ -55,10 +54,32 @@ func (f *IOSample) AnalogSampleMask() byte {
 	return f.buffer[ioSampleAnalogSampleMaskOffset]
 }
 
+// DigitalSamples digital line states, 0 when no digital lines are sampled
 func (f *IOSample) DigitalSamples() uint16 {
-	return binary.BigEndian.Uint16(f.buffer[ioSampleDigitalSamplesOffset:ioSampleAnalogSampleOffset])
+	if f.DigitalSampleMask() == 0 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint16(f.buffer[ioSampleDigitalSamplesOffset : ioSampleDigitalSamplesOffset+digitalSamplesLength])
 }
 
+// AnalogSample raw reading of the lowest enabled analog line, 0 when no analog
+// lines are sampled. Use Sample to read every enabled analog line.
 func (f *IOSample) AnalogSample() uint16 {
-	return binary.BigEndian.Uint16(f.buffer[ioSampleAnalogSampleOffset:])
+	offset := ioSampleDigitalSamplesOffset
+	if f.DigitalSampleMask() != 0 {
+		offset += digitalSamplesLength
+	}
+
+	if f.AnalogSampleMask() == 0 || len(f.buffer) < offset+analogSampleLength {
+		return 0
+	}
+
+	return binary.BigEndian.Uint16(f.buffer[offset : offset+analogSampleLength])
+}
+
+// Sample decoded state of every enabled digital line and raw reading of every
+// enabled analog line
+func (f *IOSample) Sample() (Sample, error) {
+	return DecodeSample(f.buffer[ioSampleSampleCountOffset:])
 }
@@ -0,0 +1,56 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	legacyIOSampleCountOffset      = 0
+	legacyIOChannelIndicatorOffset = 1
+	legacyIOChannelIndicatorLength = 2
+	legacyIOSamplesOffset          = 3
+
+	legacyIODigitalMask = 0x01FF
+	legacyIOAnalogShift = 9
+	legacyIOAnalogMask  = 0x3F
+)
+
+// DecodeLegacySample decodes sample data laid out as in 802.15.4 IO frames and
+// 802.15.4 IS command responses: sample count, channel indicator, then the
+// sample sets. Returns the first sample set.
+func DecodeLegacySample(p []byte) (Sample, error) {
+	samples, err := decodeLegacyIO(p)
+	if err != nil {
+		return Sample{}, err
+	}
+	if len(samples) == 0 {
+		return Sample{}, errSampleLength
+	}
+
+	return samples[0], nil
+}
+
+// decodeLegacyIO decodes the sample count, channel indicator and every sample
+// set of an 802.15.4 IO frame's sample data
+func decodeLegacyIO(p []byte) ([]Sample, error) {
+	if len(p) < legacyIOSamplesOffset {
+		return nil, errSampleLength
+	}
+
+	count := int(p[legacyIOSampleCountOffset])
+	indicator := binary.BigEndian.Uint16(p[legacyIOChannelIndicatorOffset : legacyIOChannelIndicatorOffset+legacyIOChannelIndicatorLength])
+	digitalMask := indicator & legacyIODigitalMask
+	analogMask := byte(indicator>>legacyIOAnalogShift) & legacyIOAnalogMask
+
+	samples := make([]Sample, 0, count)
+	p = p[legacyIOSamplesOffset:]
+	for i := 0; i < count; i++ {
+		s, n, err := decodeSample(digitalMask, analogMask, p)
+		if err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, s)
+		p = p[n:]
+	}
+
+	return samples, nil
+}
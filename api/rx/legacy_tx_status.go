@@ -0,0 +1,46 @@
+package rx
+
+const (
+	legacyTXStatusAPIID byte = 0x89
+
+	legacyTXStatusFrameIDOffset = 0
+	legacyTXStatusStatusOffset  = 1
+)
+
+// 802.15.4 TX status values
+const (
+	LegacyTXStatusSuccess    byte = 0x00
+	LegacyTXStatusNoACK      byte = 0x01
+	LegacyTXStatusCCAFailure byte = 0x02
+	LegacyTXStatusPurged     byte = 0x03
+)
+
+var _ Frame = (*LegacyTXStatus)(nil)
+
+// LegacyTXStatus 802.15.4 TX status rx frame
+type LegacyTXStatus struct {
+	buffer []byte
+}
+
+func newLegacyTXStatus() Frame {
+	return &LegacyTXStatus{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *LegacyTXStatus) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// ID frame ID of TX frame this status is associated with
+func (f *LegacyTXStatus) ID() byte {
+	return f.buffer[legacyTXStatusFrameIDOffset]
+}
+
+// Status delivery status of the TX
+func (f *LegacyTXStatus) Status() byte {
+	return f.buffer[legacyTXStatusStatusOffset]
+}
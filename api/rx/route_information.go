@@ -0,0 +1,90 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	routeInformationAPIID byte = 0x8D
+
+	routeInformationEventOffset      = 0
+	routeInformationLengthOffset     = 1
+	routeInformationTimestampOffset  = 2
+	routeInformationTimestampLength  = 4
+	routeInformationACKTimeoutOffset = 6
+	routeInformationTXBlockedOffset  = 7
+	routeInformationDstOffset        = 9
+	routeInformationSrcOffset        = 17
+	routeInformationResponderOffset  = 25
+	routeInformationReceiverOffset   = 33
+)
+
+// Route information source events
+const (
+	RouteEventNACK       byte = 0x11
+	RouteEventTraceRoute byte = 0x12
+)
+
+var _ Frame = (*RouteInformation)(nil)
+
+// RouteInformation rx frame, reported by each hop of a DigiMesh unicast sent
+// with the trace route or NACK transmit option
+type RouteInformation struct {
+	buffer []byte
+}
+
+func newRouteInformation() Frame {
+	return &RouteInformation{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *RouteInformation) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Event source event, RouteEventNACK or RouteEventTraceRoute
+func (f *RouteInformation) Event() byte {
+	return f.buffer[routeInformationEventOffset]
+}
+
+// Length number of bytes following the length field
+func (f *RouteInformation) Length() byte {
+	return f.buffer[routeInformationLengthOffset]
+}
+
+// Timestamp system timer value, in microseconds, of the node generating this frame
+func (f *RouteInformation) Timestamp() uint32 {
+	return binary.BigEndian.Uint32(f.buffer[routeInformationTimestampOffset : routeInformationTimestampOffset+routeInformationTimestampLength])
+}
+
+// ACKTimeouts number of MAC ACK timeouts
+func (f *RouteInformation) ACKTimeouts() byte {
+	return f.buffer[routeInformationACKTimeoutOffset]
+}
+
+// TXBlocked number of times the transmission was blocked by reception in progress
+func (f *RouteInformation) TXBlocked() byte {
+	return f.buffer[routeInformationTXBlockedOffset]
+}
+
+// Destination 64-bit address of the final destination of the data packet
+func (f *RouteInformation) Destination() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[routeInformationDstOffset : routeInformationDstOffset+addr64Length])
+}
+
+// Source 64-bit address of the source of the data packet
+func (f *RouteInformation) Source() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[routeInformationSrcOffset : routeInformationSrcOffset+addr64Length])
+}
+
+// Responder 64-bit address of the node that generated this frame after relaying the data packet
+func (f *RouteInformation) Responder() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[routeInformationResponderOffset : routeInformationResponderOffset+addr64Length])
+}
+
+// Receiver 64-bit address of the node the responder relayed the data packet to
+func (f *RouteInformation) Receiver() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[routeInformationReceiverOffset : routeInformationReceiverOffset+addr64Length])
+}
@@ -0,0 +1,56 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	rx16APIID byte = 0x81
+
+	rx16Addr16Offset  = 0
+	rx16RSSIOffset    = 2
+	rx16OptionsOffset = 3
+	rx16DataOffset    = 4
+)
+
+var _ Frame = (*RX16)(nil)
+
+// RX16 802.15.4 rx frame, 16-bit addressing
+type RX16 struct {
+	buffer []byte
+}
+
+func newRX16() Frame {
+	return &RX16{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *RX16) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Addr16 16-bit address of sender
+func (f *RX16) Addr16() uint16 {
+	return binary.BigEndian.Uint16(f.buffer[rx16Addr16Offset : rx16Addr16Offset+addr16Length])
+}
+
+// RSSI received signal strength, in -dBm
+func (f *RX16) RSSI() byte {
+	return f.buffer[rx16RSSIOffset]
+}
+
+// Options frame options
+func (f *RX16) Options() byte {
+	return f.buffer[rx16OptionsOffset]
+}
+
+// Data frame data
+func (f *RX16) Data() []byte {
+	if len(f.buffer) == rx16DataOffset {
+		return nil
+	}
+
+	return f.buffer[rx16DataOffset:]
+}
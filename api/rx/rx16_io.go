@@ -0,0 +1,63 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	rx16IOAPIID byte = 0x83
+
+	rx16IOAddr16Offset  = 0
+	rx16IORSSIOffset    = 2
+	rx16IOOptionsOffset = 3
+	rx16IOSampleOffset  = 4
+)
+
+var _ Frame = (*RX16IO)(nil)
+
+// RX16IO 802.15.4 IO sample rx frame, 16-bit addressing
+type RX16IO struct {
+	buffer []byte
+}
+
+func newRX16IO() Frame {
+	return &RX16IO{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *RX16IO) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Addr16 16-bit address of sender
+func (f *RX16IO) Addr16() uint16 {
+	return binary.BigEndian.Uint16(f.buffer[rx16IOAddr16Offset : rx16IOAddr16Offset+addr16Length])
+}
+
+// RSSI received signal strength, in -dBm
+func (f *RX16IO) RSSI() byte {
+	return f.buffer[rx16IORSSIOffset]
+}
+
+// Options frame options
+func (f *RX16IO) Options() byte {
+	return f.buffer[rx16IOOptionsOffset]
+}
+
+// SampleCount number of sample sets in the frame
+func (f *RX16IO) SampleCount() byte {
+	return f.buffer[rx16IOSampleOffset+legacyIOSampleCountOffset]
+}
+
+// ChannelIndicator lines enabled for sampling, bits 0-8 DIO0-DIO8, bits 9-14 AD0-AD5
+func (f *RX16IO) ChannelIndicator() uint16 {
+	offset := rx16IOSampleOffset + legacyIOChannelIndicatorOffset
+	return binary.BigEndian.Uint16(f.buffer[offset : offset+legacyIOChannelIndicatorLength])
+}
+
+// Samples decoded sample sets, oldest first
+func (f *RX16IO) Samples() ([]Sample, error) {
+	return decodeLegacyIO(f.buffer[rx16IOSampleOffset:])
+}
@@ -0,0 +1,56 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	rx64APIID byte = 0x80
+
+	rx64Addr64Offset  = 0
+	rx64RSSIOffset    = 8
+	rx64OptionsOffset = 9
+	rx64DataOffset    = 10
+)
+
+var _ Frame = (*RX64)(nil)
+
+// RX64 802.15.4 rx frame, 64-bit addressing
+type RX64 struct {
+	buffer []byte
+}
+
+func newRX64() Frame {
+	return &RX64{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *RX64) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Addr64 64-bit address of sender
+func (f *RX64) Addr64() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[rx64Addr64Offset : rx64Addr64Offset+addr64Length])
+}
+
+// RSSI received signal strength, in -dBm
+func (f *RX64) RSSI() byte {
+	return f.buffer[rx64RSSIOffset]
+}
+
+// Options frame options
+func (f *RX64) Options() byte {
+	return f.buffer[rx64OptionsOffset]
+}
+
+// Data frame data
+func (f *RX64) Data() []byte {
+	if len(f.buffer) == rx64DataOffset {
+		return nil
+	}
+
+	return f.buffer[rx64DataOffset:]
+}
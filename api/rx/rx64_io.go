@@ -0,0 +1,63 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	rx64IOAPIID byte = 0x82
+
+	rx64IOAddr64Offset  = 0
+	rx64IORSSIOffset    = 8
+	rx64IOOptionsOffset = 9
+	rx64IOSampleOffset  = 10
+)
+
+var _ Frame = (*RX64IO)(nil)
+
+// RX64IO 802.15.4 IO sample rx frame, 64-bit addressing
+type RX64IO struct {
+	buffer []byte
+}
+
+func newRX64IO() Frame {
+	return &RX64IO{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *RX64IO) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Addr64 64-bit address of sender
+func (f *RX64IO) Addr64() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[rx64IOAddr64Offset : rx64IOAddr64Offset+addr64Length])
+}
+
+// RSSI received signal strength, in -dBm
+func (f *RX64IO) RSSI() byte {
+	return f.buffer[rx64IORSSIOffset]
+}
+
+// Options frame options
+func (f *RX64IO) Options() byte {
+	return f.buffer[rx64IOOptionsOffset]
+}
+
+// SampleCount number of sample sets in the frame
+func (f *RX64IO) SampleCount() byte {
+	return f.buffer[rx64IOSampleOffset+legacyIOSampleCountOffset]
+}
+
+// ChannelIndicator lines enabled for sampling, bits 0-8 DIO0-DIO8, bits 9-14 AD0-AD5
+func (f *RX64IO) ChannelIndicator() uint16 {
+	offset := rx64IOSampleOffset + legacyIOChannelIndicatorOffset
+	return binary.BigEndian.Uint16(f.buffer[offset : offset+legacyIOChannelIndicatorLength])
+}
+
+// Samples decoded sample sets, oldest first
+func (f *RX64IO) Samples() ([]Sample, error) {
+	return decodeLegacyIO(f.buffer[rx64IOSampleOffset:])
+}
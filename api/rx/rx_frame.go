@@ -82,6 +82,11 @@ type DiscoveryGetter interface {
 	Discovery() byte
 }
 
+// RSSIGetter gets received signal strength
+type RSSIGetter interface {
+	RSSI() byte
+}
+
 // SampleCountGetter gets sample count
 type SampleCountGetter interface {
 	SampleCount() byte
@@ -1,6 +1,10 @@
 package rx
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/pauleyj/gobee/api"
+)
 
 // FrameFactory defines a function returning an RX Frame
 type FrameFactory func() Frame
@@ -9,6 +13,7 @@ var (
 	errUnknownFrameAPIID = errors.New("unknown frame API ID")
 	errFrameAPIIDExists  = errors.New("factory for API ID already exists")
 	rxFrameFactory       map[byte]FrameFactory
+	protocolFrameFactory map[api.Protocol]map[byte]FrameFactory
 )
 
 func init() {
@@ -21,6 +26,17 @@ func init() {
 	rxFrameFactory[atRemoteAPIID] = newATRemote
 	rxFrameFactory[modemStatusAPIID] = newModemStatus
 	rxFrameFactory[ioSampleAPIID] = newIOSample
+	rxFrameFactory[routeInformationAPIID] = newRouteInformation
+	rxFrameFactory[sensorReadAPIID] = newSensorRead
+
+	protocolFrameFactory = make(map[api.Protocol]map[byte]FrameFactory)
+	protocolFrameFactory[api.Protocol802154] = map[byte]FrameFactory{
+		rx64APIID:           newRX64,
+		rx16APIID:           newRX16,
+		rx64IOAPIID:         newRX64IO,
+		rx16IOAPIID:         newRX16IO,
+		legacyTXStatusAPIID: newLegacyTXStatus,
+	}
 }
 
 // NewFrameForAPIID creates an appropriate RxFrame for the given API ID
@@ -32,6 +48,16 @@ func NewFrameForAPIID(id byte) (Frame, error) {
 	return nil, errUnknownFrameAPIID
 }
 
+// NewFrameForProtocolAPIID creates an appropriate RxFrame for the given API ID,
+// frames particular to the protocol family take precedence
+func NewFrameForProtocolAPIID(protocol api.Protocol, id byte) (Frame, error) {
+	if f, ok := protocolFrameFactory[protocol][id]; ok {
+		return f(), nil
+	}
+
+	return NewFrameForAPIID(id)
+}
+
 // AddFactoryForAPIID add frame by ID so factory can produce
 func AddFactoryForAPIID(id byte, factory FrameFactory) error {
 	if _, ok := rxFrameFactory[id]; !ok {
@@ -2,6 +2,7 @@ package rx
 
 import (
 	"errors"
+	"math"
 	"reflect"
 	"testing"
 
@@ -266,6 +267,139 @@ var rxTests = []rxTest{
 		},
 		err: nil,
 	},
+	{
+		name: "RX Route Information",
+		input: []byte{
+			0x7E, 0x00, 0x2A, 0x8D,
+			0x12, 0x27, 0x00, 0x01,
+			0x02, 0x03, 0x01, 0x02,
+			0x00, 0x00, 0x13, 0xA2,
+			0x00, 0x40, 0x52, 0x2B,
+			0xAA, 0x00, 0x13, 0xA2,
+			0x00, 0x40, 0x52, 0x2B,
+			0xBB, 0x00, 0x13, 0xA2,
+			0x00, 0x40, 0x52, 0x2B,
+			0xBB, 0x00, 0x13, 0xA2,
+			0x00, 0x40, 0x52, 0x2B,
+			0xCC, 0x7C},
+		f: New(),
+		expected: &RouteInformation{
+			[]byte{
+				0x12, 0x27, 0x00, 0x01,
+				0x02, 0x03, 0x01, 0x02,
+				0x00, 0x00, 0x13, 0xA2,
+				0x00, 0x40, 0x52, 0x2B,
+				0xAA, 0x00, 0x13, 0xA2,
+				0x00, 0x40, 0x52, 0x2B,
+				0xBB, 0x00, 0x13, 0xA2,
+				0x00, 0x40, 0x52, 0x2B,
+				0xBB, 0x00, 0x13, 0xA2,
+				0x00, 0x40, 0x52, 0x2B,
+				0xCC},
+		},
+		err: nil,
+	},
+	{
+		name: "RX 802.15.4 RX64",
+		input: []byte{
+			0x7E, 0x00, 0x0D, 0x80,
+			0x00, 0x13, 0xA2, 0x00,
+			0x40, 0x52, 0x2B, 0xAA,
+			0x28, 0x00, 0x68, 0x69,
+			0x6A},
+		f: New(api.APIProtocol(api.Protocol802154)),
+		expected: &RX64{
+			[]byte{
+				0x00, 0x13, 0xA2, 0x00,
+				0x40, 0x52, 0x2B, 0xAA,
+				0x28, 0x00, 0x68, 0x69},
+		},
+		err: nil,
+	},
+	{
+		name: "RX 802.15.4 RX16",
+		input: []byte{
+			0x7E, 0x00, 0x07, 0x81,
+			0x7D, 0x84, 0x28, 0x02,
+			0x68, 0x69, 0x82},
+		f: New(api.APIProtocol(api.Protocol802154)),
+		expected: &RX16{
+			[]byte{
+				0x7D, 0x84, 0x28, 0x02,
+				0x68, 0x69},
+		},
+		err: nil,
+	},
+	{
+		name: "RX 802.15.4 RX64 IO",
+		input: []byte{
+			0x7E, 0x00, 0x14, 0x82,
+			0x00, 0x13, 0xA2, 0x00,
+			0x40, 0x52, 0x2B, 0xAA,
+			0x28, 0x00, 0x01, 0x06,
+			0x05, 0x00, 0x01, 0x02,
+			0x00, 0x00, 0x04, 0x26},
+		f: New(api.APIProtocol(api.Protocol802154)),
+		expected: &RX64IO{
+			[]byte{
+				0x00, 0x13, 0xA2, 0x00,
+				0x40, 0x52, 0x2B, 0xAA,
+				0x28, 0x00, 0x01, 0x06,
+				0x05, 0x00, 0x01, 0x02,
+				0x00, 0x00, 0x04},
+		},
+		err: nil,
+	},
+	{
+		name: "RX 802.15.4 RX16 IO",
+		input: []byte{
+			0x7E, 0x00, 0x0A, 0x83,
+			0x7D, 0x84, 0x28, 0x00,
+			0x01, 0x02, 0x00, 0x02,
+			0x25, 0x29},
+		f: New(api.APIProtocol(api.Protocol802154)),
+		expected: &RX16IO{
+			[]byte{
+				0x7D, 0x84, 0x28, 0x00,
+				0x01, 0x02, 0x00, 0x02,
+				0x25},
+		},
+		err: nil,
+	},
+	{
+		name: "RX 802.15.4 TX Status",
+		input: []byte{
+			0x7E, 0x00, 0x03, 0x89,
+			0x01, 0x00, 0x75},
+		f: New(api.APIProtocol(api.Protocol802154)),
+		expected: &LegacyTXStatus{
+			[]byte{
+				0x01, 0x00},
+		},
+		err: nil,
+	},
+	{
+		name: "RX Sensor Read",
+		input: []byte{
+			0x7E, 0x00, 0x17, 0x94,
+			0x00, 0x13, 0xA2, 0x00,
+			0x40, 0x52, 0x2B, 0xAA,
+			0xDD, 0x6C, 0x01, 0x03,
+			0x00, 0x02, 0x00, 0xCE,
+			0x00, 0xEA, 0x00, 0x52,
+			0x01, 0x6A, 0x8B},
+		f: New(),
+		expected: &SensorRead{
+			[]byte{
+				0x00, 0x13, 0xA2, 0x00,
+				0x40, 0x52, 0x2B, 0xAA,
+				0xDD, 0x6C, 0x01, 0x03,
+				0x00, 0x02, 0x00, 0xCE,
+				0x00, 0xEA, 0x00, 0x52,
+				0x01, 0x6A},
+		},
+		err: nil,
+	},
 }
 
 func TestRXAPIFrame(t *testing.T) {
@@ -420,6 +554,13 @@ func TestRXAPIFrame(t *testing.T) {
 					}
 				}
 
+				if f, ok := actual.(RSSIGetter); ok {
+					e := tt.expected.(RSSIGetter)
+					if f.RSSI() != e.RSSI() {
+						t.Fatalf("Expected RSSI=%#0.2x, but got %#0.2x", e.RSSI(), f.RSSI())
+					}
+				}
+
 				if f, ok := actual.(SampleCountGetter); ok {
 					e := tt.expected.(SampleCountGetter)
 					if f.SampleCount() != e.SampleCount() {
@@ -458,3 +599,222 @@ func TestRXAPIFrame(t *testing.T) {
 		}
 	})
 }
+
+func TestRouteInformation(t *testing.T) {
+	f := &RouteInformation{[]byte{
+		0x12, 0x27, 0x00, 0x01,
+		0x02, 0x03, 0x01, 0x02,
+		0x00, 0x00, 0x13, 0xA2,
+		0x00, 0x40, 0x52, 0x2B,
+		0xAA, 0x00, 0x13, 0xA2,
+		0x00, 0x40, 0x52, 0x2B,
+		0xBB, 0x00, 0x13, 0xA2,
+		0x00, 0x40, 0x52, 0x2B,
+		0xBB, 0x00, 0x13, 0xA2,
+		0x00, 0x40, 0x52, 0x2B,
+		0xCC}}
+
+	if f.Event() != RouteEventTraceRoute {
+		t.Fatalf("Expected event=%#0.2x, but got %#0.2x", RouteEventTraceRoute, f.Event())
+	}
+	if f.Length() != 0x27 {
+		t.Fatalf("Expected length=0x27, but got %#0.2x", f.Length())
+	}
+	if f.Timestamp() != 0x00010203 {
+		t.Fatalf("Expected timestamp=0x00010203, but got %#0.8x", f.Timestamp())
+	}
+	if f.ACKTimeouts() != 1 {
+		t.Fatalf("Expected ACK timeouts=1, but got %d", f.ACKTimeouts())
+	}
+	if f.TXBlocked() != 2 {
+		t.Fatalf("Expected TX blocked=2, but got %d", f.TXBlocked())
+	}
+	if f.Destination() != 0x0013A20040522BAA {
+		t.Fatalf("Expected destination=0x0013a20040522baa, but got %#0.16x", f.Destination())
+	}
+	if f.Source() != 0x0013A20040522BBB {
+		t.Fatalf("Expected source=0x0013a20040522bbb, but got %#0.16x", f.Source())
+	}
+	if f.Responder() != 0x0013A20040522BBB {
+		t.Fatalf("Expected responder=0x0013a20040522bbb, but got %#0.16x", f.Responder())
+	}
+	if f.Receiver() != 0x0013A20040522BCC {
+		t.Fatalf("Expected receiver=0x0013a20040522bcc, but got %#0.16x", f.Receiver())
+	}
+}
+
+func TestRX64IO_Samples(t *testing.T) {
+	// two sample sets, DIO0 and DIO2 digital lines, AD0 and AD1 analog lines
+	f := &RX64IO{[]byte{
+		0x00, 0x13, 0xA2, 0x00,
+		0x40, 0x52, 0x2B, 0xAA,
+		0x28, 0x00, 0x02, 0x06,
+		0x05, 0x00, 0x01, 0x02,
+		0x00, 0x00, 0x04, 0x00,
+		0x05, 0x03, 0x00, 0x00,
+		0x10}}
+
+	if f.ChannelIndicator() != 0x0605 {
+		t.Fatalf("Expected channel indicator=0x0605, but got %#0.4x", f.ChannelIndicator())
+	}
+
+	samples, err := f.Samples()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []Sample{
+		{
+			Digital: map[api.DIO]bool{api.DIO0: true, api.DIO2: false},
+			Analog:  map[api.AD]uint16{api.AD0: 0x0200, api.AD1: 0x0004},
+		},
+		{
+			Digital: map[api.DIO]bool{api.DIO0: true, api.DIO2: true},
+			Analog:  map[api.AD]uint16{api.AD0: 0x0300, api.AD1: 0x0010},
+		},
+	}
+	if !reflect.DeepEqual(samples, expected) {
+		t.Fatalf("Expected samples=%+v, but got %+v", expected, samples)
+	}
+}
+
+func TestRX64IO_Samples_Short(t *testing.T) {
+	f := &RX64IO{[]byte{
+		0x00, 0x13, 0xA2, 0x00,
+		0x40, 0x52, 0x2B, 0xAA,
+		0x28, 0x00, 0x02, 0x06,
+		0x05, 0x00, 0x01, 0x02}}
+
+	if _, err := f.Samples(); err != errSampleLength {
+		t.Fatalf("Expected error=%v, but got %v", errSampleLength, err)
+	}
+}
+
+func TestRX_Protocol_Frames(t *testing.T) {
+	if _, err := NewFrameForProtocolAPIID(api.ProtocolZigbee, rx64APIID); err != errUnknownFrameAPIID {
+		t.Fatalf("Expected error=%v, but got %v", errUnknownFrameAPIID, err)
+	}
+
+	f, err := NewFrameForProtocolAPIID(api.Protocol802154, atAPIID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if _, ok := f.(*AT); !ok {
+		t.Fatalf("Expected *AT, but got %T", f)
+	}
+}
+
+func TestSensorRead(t *testing.T) {
+	f := &SensorRead{[]byte{
+		0x00, 0x13, 0xA2, 0x00,
+		0x40, 0x52, 0x2B, 0xAA,
+		0xDD, 0x6C, 0x01, 0x03,
+		0x00, 0x02, 0x00, 0xCE,
+		0x00, 0xEA, 0x00, 0x52,
+		0x01, 0x6A}}
+
+	if f.Sensors() != SensorAD|SensorTemperature {
+		t.Fatalf("Expected sensors=%#0.2x, but got %#0.2x", SensorAD|SensorTemperature, f.Sensors())
+	}
+	if ad := f.AD(); ad != [4]uint16{0x0002, 0x00CE, 0x00EA, 0x0052} {
+		t.Fatalf("Expected A/D values [0x0002 0x00ce 0x00ea 0x0052], but got %#0.4x", ad)
+	}
+	if !f.HasTemperature() {
+		t.Fatal("Expected temperature sensor present")
+	}
+
+	approx := func(name string, expected, actual float64) {
+		if math.Abs(expected-actual) > 0.01 {
+			t.Fatalf("Expected %s=%.2f, but got %.2f", name, expected, actual)
+		}
+	}
+	approx("celsius", 22.625, f.Celsius())
+	approx("lux", 1026.98, f.Lux())
+	approx("humidity", 11.76, f.Humidity())
+
+	for _, channel := range []int{-1, 4} {
+		if _, ok := f.Millivolts(channel); ok {
+			t.Fatalf("Expected channel %d to be rejected", channel)
+		}
+	}
+
+	negative := &SensorRead{append(append([]byte{}, f.buffer[:sensorReadTemperatureOffset]...), 0xFF, 0x5E)}
+	approx("negative celsius", -10.125, negative.Celsius())
+
+	none := &SensorRead{append(append([]byte{}, f.buffer[:sensorReadTemperatureOffset]...), 0xFF, 0xFF)}
+	if none.HasTemperature() {
+		t.Fatal("Expected no temperature sensor")
+	}
+}
+
+func TestIOSample_Sample(t *testing.T) {
+	// DIO2, DIO3, DIO4 digital lines, AD1, AD2 and supply voltage analog lines
+	f := &IOSample{[]byte{
+		0x00, 0x13, 0xA2, 0x00,
+		0x40, 0x52, 0x2B, 0xAA,
+		0x7D, 0x84, 0x01, 0x01,
+		0x00, 0x1C, 0x86, 0x00,
+		0x14, 0x02, 0x25, 0x00,
+		0xF5, 0x03, 0xFF}}
+
+	s, err := f.Sample()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := Sample{
+		Digital: map[api.DIO]bool{api.DIO2: true, api.DIO3: false, api.DIO4: true},
+		Analog:  map[api.AD]uint16{api.AD1: 0x0225, api.AD2: 0x00F5, api.SupplyVoltage: 0x03FF},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("Expected sample=%+v, but got %+v", expected, s)
+	}
+
+	if f.AnalogSample() != 0x0225 {
+		t.Fatalf("Expected analog sample=0x0225, but got %#0.4x", f.AnalogSample())
+	}
+
+	mv, ok := s.Millivolts(api.SupplyVoltage, DefaultReference)
+	if !ok || mv != DefaultReference {
+		t.Fatalf("Expected supply voltage=%.0f mV, but got %.0f mV", DefaultReference, mv)
+	}
+
+	mv, ok = s.Millivolts(api.AD1, 2500)
+	if !ok || math.Abs(mv-1341.64) > 0.01 {
+		t.Fatalf("Expected AD1=1341.64 mV, but got %.2f mV", mv)
+	}
+
+	if _, ok := s.Millivolts(api.AD0, DefaultReference); ok {
+		t.Fatal("Expected AD0 not sampled")
+	}
+}
+
+func TestIOSample_Sample_Analog_Only(t *testing.T) {
+	// no digital lines, AD0 and AD3 analog lines
+	f := &IOSample{[]byte{
+		0x00, 0x13, 0xA2, 0x00,
+		0x40, 0x52, 0x2B, 0xAA,
+		0x7D, 0x84, 0x01, 0x01,
+		0x00, 0x00, 0x09, 0x01,
+		0x00, 0x02, 0x00}}
+
+	s, err := f.Sample()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := Sample{
+		Digital: map[api.DIO]bool{},
+		Analog:  map[api.AD]uint16{api.AD0: 0x0100, api.AD3: 0x0200},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("Expected sample=%+v, but got %+v", expected, s)
+	}
+
+	if f.DigitalSamples() != 0 {
+		t.Fatalf("Expected digital samples=0, but got %#0.4x", f.DigitalSamples())
+	}
+	if f.AnalogSample() != 0x0100 {
+		t.Fatalf("Expected analog sample=0x0100, but got %#0.4x", f.AnalogSample())
+	}
+}
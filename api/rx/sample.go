@@ -0,0 +1,95 @@
+package rx
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+const (
+	sampleCountOffset       = 0
+	sampleDigitalMaskOffset = 1
+	sampleAnalogMaskOffset  = 3
+	sampleDataOffset        = 4
+
+	digitalSamplesLength = 2
+	analogSampleLength   = 2
+	analogLineCount      = 8
+)
+
+// DefaultReference millivolt reference of the XBee's 10-bit A/D converter
+const DefaultReference = 1200.0
+
+const analogResolution = 1023.0
+
+var errSampleLength = errors.New("IO sample data too short")
+
+// Sample a single set of IO line readings, only lines enabled for sampling are present
+type Sample struct {
+	Digital map[api.DIO]bool
+	Analog  map[api.AD]uint16
+}
+
+// DecodeSample decodes sample data laid out as in IO Data Sample frames and IS
+// command responses: sample count, digital mask, analog mask, then the sample
+func DecodeSample(p []byte) (Sample, error) {
+	if len(p) < sampleDataOffset {
+		return Sample{}, errSampleLength
+	}
+
+	digitalMask := binary.BigEndian.Uint16(p[sampleDigitalMaskOffset:sampleAnalogMaskOffset])
+	s, _, err := decodeSample(digitalMask, p[sampleAnalogMaskOffset], p[sampleDataOffset:])
+
+	return s, err
+}
+
+// decodeSample decodes the digital and analog readings enabled by the sample
+// masks from p, returns the sample and the number of bytes consumed
+func decodeSample(digitalMask uint16, analogMask byte, p []byte) (Sample, int, error) {
+	s := Sample{
+		Digital: make(map[api.DIO]bool),
+		Analog:  make(map[api.AD]uint16),
+	}
+
+	n := 0
+	if digitalMask != 0 {
+		if len(p) < digitalSamplesLength {
+			return s, 0, errSampleLength
+		}
+
+		digital := binary.BigEndian.Uint16(p)
+		for i := uint(0); i < 16; i++ {
+			if digitalMask&(1<<i) != 0 {
+				s.Digital[api.DIO(i)] = digital&(1<<i) != 0
+			}
+		}
+		n += digitalSamplesLength
+	}
+
+	for i := uint(0); i < analogLineCount; i++ {
+		if analogMask&(1<<i) == 0 {
+			continue
+		}
+
+		if len(p) < n+analogSampleLength {
+			return s, 0, errSampleLength
+		}
+
+		s.Analog[api.AD(i)] = binary.BigEndian.Uint16(p[n:])
+		n += analogSampleLength
+	}
+
+	return s, n, nil
+}
+
+// Millivolts raw reading of analog line converted to millivolts against the
+// A/D converter's reference, in millivolts. Reports false when line was not sampled.
+func (s Sample) Millivolts(line api.AD, reference float64) (float64, bool) {
+	raw, ok := s.Analog[line]
+	if !ok {
+		return 0, false
+	}
+
+	return float64(raw) * reference / analogResolution, true
+}
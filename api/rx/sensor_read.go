@@ -0,0 +1,135 @@
+package rx
+
+import "encoding/binary"
+
+const (
+	sensorReadAPIID byte = 0x94
+
+	sensorReadAddr64Offset      = 0
+	sensorReadAddr16Offset      = 8
+	sensorReadOptionsOffset     = 10
+	sensorReadSensorsOffset     = 11
+	sensorReadADOffset          = 12
+	sensorReadADCount           = 4
+	sensorReadTemperatureOffset = 20
+	sensorReadTemperatureLength = 2
+
+	// sensorReadNoTemperature temperature reading when no temperature sensor is present
+	sensorReadNoTemperature = 0xFFFF
+
+	// sensorReadReference millivolt reference of the 1-Wire adapter's 10-bit A/D converter
+	sensorReadReference  = 5100.0
+	sensorReadResolution = 1023.0
+)
+
+// 1-Wire sensor flags
+const (
+	SensorAD           byte = 0x01
+	SensorTemperature  byte = 0x02
+	SensorWaterPresent byte = 0x60
+)
+
+// A/D channels of the XBee Sensor /L/T/H 1-Wire adapter
+const (
+	SensorLightAD    = 1
+	SensorHumidityAD = 2
+)
+
+var _ Frame = (*SensorRead)(nil)
+
+// SensorRead XBee sensor read indicator rx frame, reported by 1-Wire sensor adapters
+type SensorRead struct {
+	buffer []byte
+}
+
+func newSensorRead() Frame {
+	return &SensorRead{
+		buffer: make([]byte, 0),
+	}
+}
+
+// RX frame data
+func (f *SensorRead) RX(b byte) error {
+	f.buffer = append(f.buffer, b)
+
+	return nil
+}
+
+// Addr64 64-bit address of sender
+func (f *SensorRead) Addr64() uint64 {
+	return binary.BigEndian.Uint64(f.buffer[sensorReadAddr64Offset : sensorReadAddr64Offset+addr64Length])
+}
+
+// Addr16 16-bit address of sender
+func (f *SensorRead) Addr16() uint16 {
+	return binary.BigEndian.Uint16(f.buffer[sensorReadAddr16Offset : sensorReadAddr16Offset+addr16Length])
+}
+
+// Options frame options
+func (f *SensorRead) Options() byte {
+	return f.buffer[sensorReadOptionsOffset]
+}
+
+// Sensors 1-Wire sensor flags, see SensorAD, SensorTemperature and SensorWaterPresent
+func (f *SensorRead) Sensors() byte {
+	return f.buffer[sensorReadSensorsOffset]
+}
+
+// AD raw A/D values of the four A/D channels
+func (f *SensorRead) AD() [sensorReadADCount]uint16 {
+	var ad [sensorReadADCount]uint16
+	for i := range ad {
+		offset := sensorReadADOffset + i*analogSampleLength
+		ad[i] = binary.BigEndian.Uint16(f.buffer[offset : offset+analogSampleLength])
+	}
+
+	return ad
+}
+
+// Temperature raw temperature reading, 0xFFFF when no temperature sensor is present
+func (f *SensorRead) Temperature() uint16 {
+	return binary.BigEndian.Uint16(f.buffer[sensorReadTemperatureOffset : sensorReadTemperatureOffset+sensorReadTemperatureLength])
+}
+
+// HasTemperature is a temperature sensor present
+func (f *SensorRead) HasTemperature() bool {
+	return f.Temperature() != sensorReadNoTemperature
+}
+
+// Millivolts A/D value of channel converted to millivolts. Reports false when
+// channel is not one of the four A/D channels, 0 through 3.
+func (f *SensorRead) Millivolts(channel int) (float64, bool) {
+	if channel < 0 || channel >= sensorReadADCount {
+		return 0, false
+	}
+
+	return float64(f.AD()[channel]) * sensorReadReference / sensorReadResolution, true
+}
+
+// Celsius temperature in degrees Celsius, the reading is a two's complement
+// value in 1/16 degree increments
+func (f *SensorRead) Celsius() float64 {
+	return float64(int16(f.Temperature())) / 16
+}
+
+// Humidity relative humidity in percent from the humidity A/D channel,
+// compensated for temperature when a temperature sensor is present
+//
+//	RH = (mV / 5000 - 0.16) / 0.0062
+//	true RH = RH / (1.0546 - 0.00216 * C)
+func (f *SensorRead) Humidity() float64 {
+	mv, _ := f.Millivolts(SensorHumidityAD)
+	rh := (mv/5000 - 0.16) / 0.0062
+	if !f.HasTemperature() {
+		return rh
+	}
+
+	return rh / (1.0546 - 0.00216*f.Celsius())
+}
+
+// Lux illuminance from the light A/D channel, one lux per millivolt
+func (f *SensorRead) Lux() float64 {
+	mv, _ := f.Millivolts(SensorLightAD)
+
+	return mv
+}
@@ -1,3 +1,13 @@
 package tx
 
 var NI = [...]byte{'N', 'I'}
+
+var HV = [...]byte{'H', 'V'}
+
+var IS = [...]byte{'I', 'S'}
+
+var IR = [...]byte{'I', 'R'}
+
+var IC = [...]byte{'I', 'C'}
+
+var AC = [...]byte{'A', 'C'}
@@ -7,6 +7,10 @@ import (
 
 const atRemoteAPIID byte = 0x17
 
+// OptionApplyChanges remote AT command option, apply changes on the remote
+// device, without it changes are queued until AC is sent
+const OptionApplyChanges byte = 0x02
+
 func NewATRemote(options ...func(interface{})) *ATRemote {
 	f := &ATRemote{Addr64: 0xFFFF, Addr16: 0xFFFE, Cmd: NI}
 
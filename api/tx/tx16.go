@@ -0,0 +1,61 @@
+package tx
+
+import (
+	"bytes"
+	"github.com/pauleyj/gobee/api/tx/util"
+)
+
+const tx16APIID byte = 0x01
+
+// TX16 802.15.4 transmit frame, 16-bit addressing
+type TX16 struct {
+	FrameID byte
+	Addr16  uint16
+	Options byte
+	Data    []byte
+}
+
+func NewTX16(options ...func(interface{})) *TX16 {
+	f := &TX16{Addr16: 0xFFFF}
+
+	optionsRunner(f, options...)
+
+	return f
+}
+
+// SetFrameID satisfy FrameIDSetter interface
+func (f *TX16) SetFrameID(id byte) {
+	f.FrameID = id
+}
+
+// SetAddr16 satisfy Addr16Setter interface
+func (f *TX16) SetAddr16(addr uint16) {
+	f.Addr16 = addr
+}
+
+// SetOptions satisfy OptionsSetter interface
+func (f *TX16) SetOptions(options byte) {
+	f.Options = options
+}
+
+// SetData satisfy DataSetter interface
+func (f *TX16) SetData(data []byte) {
+	f.Data = make([]byte, len(data))
+	copy(f.Data, data)
+}
+
+// Bytes turn TX16 frame into bytes, satisfy Frame interface
+func (f *TX16) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte(tx16APIID)
+	b.WriteByte(f.FrameID)
+	b.Write(util.Uint16ToBytes(f.Addr16))
+	b.WriteByte(f.Options)
+
+	if f.Data != nil && len(f.Data) > 0 {
+		b.Write(f.Data)
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package tx
+
+import (
+	"testing"
+)
+
+var _ Frame = (*TX16)(nil)
+var _ FrameIDSetter = (*TX16)(nil)
+var _ Addr16Setter = (*TX16)(nil)
+var _ OptionsSetter = (*TX16)(nil)
+var _ DataSetter = (*TX16)(nil)
+
+type tx16Test struct {
+	name     string
+	input    *TX16
+	expected []byte
+}
+
+var tx16Tests = []tx16Test{
+	{"TX16 Defaults",
+		NewTX16(),
+		[]byte{tx16APIID, 0, 0xff, 0xff, 0x00}},
+	{"TX16 FrameID",
+		NewTX16(FrameID(1)),
+		[]byte{tx16APIID, 1, 0xff, 0xff, 0x00}},
+	{"TX16 Address",
+		NewTX16(Addr16(0x0102)),
+		[]byte{tx16APIID, 0, 0x01, 0x02, 0x00}},
+	{"TX16 Options",
+		NewTX16(Options(OptionDisableACK)),
+		[]byte{tx16APIID, 0, 0xff, 0xff, 0x01}},
+	{"TX16 Data",
+		NewTX16(Data([]byte{'h', 'e', 'l', 'l', 'o'})),
+		[]byte{tx16APIID, 0, 0xff, 0xff, 0x00, 'h', 'e', 'l', 'l', 'o'}},
+	{"TX16 nil Data",
+		NewTX16(Data(nil)),
+		[]byte{tx16APIID, 0, 0xff, 0xff, 0x00}},
+}
+
+func TestTX16(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TX16 Test Suite", func(t *testing.T) {
+		for _, tt := range tx16Tests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := tt.input.Bytes()
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if len(actual) != len(tt.expected) {
+					t.Fatalf("Expected TX16 frame to be %d bytes in length, got: %d", len(tt.expected), len(actual))
+				}
+				for i, b := range actual {
+					if b != tt.expected[i] {
+						t.Fatalf("Expected 0x%02x, but got 0x%02x at index %d", tt.expected[i], b, i)
+					}
+				}
+			})
+		}
+	})
+}
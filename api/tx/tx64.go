@@ -0,0 +1,67 @@
+package tx
+
+import (
+	"bytes"
+	"github.com/pauleyj/gobee/api/tx/util"
+)
+
+const tx64APIID byte = 0x00
+
+// 802.15.4 transmit options
+const (
+	OptionDisableACK     byte = 0x01
+	OptionBroadcastPANID byte = 0x04
+)
+
+// TX64 802.15.4 transmit frame, 64-bit addressing
+type TX64 struct {
+	FrameID byte
+	Addr64  uint64
+	Options byte
+	Data    []byte
+}
+
+func NewTX64(options ...func(interface{})) *TX64 {
+	f := &TX64{Addr64: 0xFFFF}
+
+	optionsRunner(f, options...)
+
+	return f
+}
+
+// SetFrameID satisfy FrameIDSetter interface
+func (f *TX64) SetFrameID(id byte) {
+	f.FrameID = id
+}
+
+// SetAddr64 satisfy Addr64Setter interface
+func (f *TX64) SetAddr64(addr uint64) {
+	f.Addr64 = addr
+}
+
+// SetOptions satisfy OptionsSetter interface
+func (f *TX64) SetOptions(options byte) {
+	f.Options = options
+}
+
+// SetData satisfy DataSetter interface
+func (f *TX64) SetData(data []byte) {
+	f.Data = make([]byte, len(data))
+	copy(f.Data, data)
+}
+
+// Bytes turn TX64 frame into bytes, satisfy Frame interface
+func (f *TX64) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte(tx64APIID)
+	b.WriteByte(f.FrameID)
+	b.Write(util.Uint64ToBytes(f.Addr64))
+	b.WriteByte(f.Options)
+
+	if f.Data != nil && len(f.Data) > 0 {
+		b.Write(f.Data)
+	}
+
+	return b.Bytes(), nil
+}
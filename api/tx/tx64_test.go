@@ -0,0 +1,65 @@
+package tx
+
+import (
+	"testing"
+)
+
+var _ Frame = (*TX64)(nil)
+var _ FrameIDSetter = (*TX64)(nil)
+var _ Addr64Setter = (*TX64)(nil)
+var _ OptionsSetter = (*TX64)(nil)
+var _ DataSetter = (*TX64)(nil)
+
+type tx64Test struct {
+	name     string
+	input    *TX64
+	expected []byte
+}
+
+var tx64Tests = []tx64Test{
+	{"TX64 Defaults",
+		NewTX64(),
+		[]byte{tx64APIID, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00}},
+	{"TX64 FrameID",
+		NewTX64(FrameID(1)),
+		[]byte{tx64APIID, 1, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00}},
+	{"TX64 Addresses",
+		NewTX64(Addr64(0x0001020304050607)),
+		[]byte{tx64APIID, 0, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x00}},
+	{"TX64 Options",
+		NewTX64(Options(OptionDisableACK)),
+		[]byte{tx64APIID, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x01}},
+	{"TX64 Data",
+		NewTX64(Data([]byte{'h', 'e', 'l', 'l', 'o'})),
+		[]byte{tx64APIID, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 'h', 'e', 'l', 'l', 'o'}},
+	{"TX64 nil Data",
+		NewTX64(Data(nil)),
+		[]byte{tx64APIID, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00}},
+}
+
+func TestTX64(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TX64 Test Suite", func(t *testing.T) {
+		for _, tt := range tx64Tests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := tt.input.Bytes()
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				if len(actual) != len(tt.expected) {
+					t.Fatalf("Expected TX64 frame to be %d bytes in length, got: %d", len(tt.expected), len(actual))
+				}
+				for i, b := range actual {
+					if b != tt.expected[i] {
+						t.Fatalf("Expected 0x%02x, but got 0x%02x at index %d", tt.expected[i], b, i)
+					}
+				}
+			})
+		}
+	})
+}
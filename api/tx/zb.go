@@ -7,6 +7,12 @@ import (
 
 const zbAPIID byte = 0x10
 
+// DigiMesh unicast transmit options
+const (
+	OptionNACK       byte = 0x04
+	OptionTraceRoute byte = 0x08
+)
+
 // ZB transmit frame
 type ZB struct {
 	FrameID         byte
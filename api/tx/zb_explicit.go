@@ -7,6 +7,12 @@ import (
 
 const zbExplicitAPIID byte = 0x11
 
+// Zigbee explicit transmit options
+const (
+	// OptionMulticast send to the group in the 16-bit address
+	OptionMulticast byte = 0x08
+)
+
 // ZBExplicit transmit frame
 type ZBExplicit struct {
 	FrameID         byte
@@ -0,0 +1,127 @@
+package gobee
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// atStatusOK AT command response status OK
+const atStatusOK byte = 0
+
+// localAT sends an AT command to the local XBee and waits for its response
+func (x *XBee) localAT(ctx context.Context, cmd [2]byte, parameter []byte) (*rx.AT, error) {
+	id := x.NextFrameID()
+
+	return x.localATFrame(ctx, id, cmd, tx.NewAT(tx.FrameID(id), tx.Command(cmd), tx.Parameter(parameter)))
+}
+
+// localATQueue queues an AT command parameter on the local XBee, applied by a
+// later AC or non-queued AT command, and waits for its response
+func (x *XBee) localATQueue(ctx context.Context, cmd [2]byte, parameter []byte) (*rx.AT, error) {
+	id := x.NextFrameID()
+
+	return x.localATFrame(ctx, id, cmd, tx.NewATQueue(tx.FrameID(id), tx.Command(cmd), tx.Parameter(parameter)))
+}
+
+// localATFrame sends the local AT or AT queue frame with frame ID id and waits
+// for its response
+func (x *XBee) localATFrame(ctx context.Context, id byte, cmd [2]byte, frame tx.Frame) (*rx.AT, error) {
+	f, err := x.Request(ctx, frame,
+		func(f rx.Frame) bool {
+			at, ok := f.(*rx.AT)
+			return ok && at.ID() == id
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	at := f.(*rx.AT)
+	if at.Status() != atStatusOK {
+		return nil, &ATError{Command: cmd, Status: at.Status()}
+	}
+
+	return at, nil
+}
+
+// remoteAT sends an AT command to the remote XBee at addr and waits for its response
+func (x *XBee) remoteAT(ctx context.Context, addr uint64, cmd [2]byte, parameter []byte, options byte) (*rx.ATRemote, error) {
+	id := x.NextFrameID()
+
+	f, err := x.Request(ctx,
+		tx.NewATRemote(tx.FrameID(id), tx.Addr64(addr), tx.Options(options), tx.Command(cmd), tx.Parameter(parameter)),
+		func(f rx.Frame) bool {
+			at, ok := f.(*rx.ATRemote)
+			return ok && at.ID() == id
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	at := f.(*rx.ATRemote)
+	if at.Status() != atStatusOK {
+		return nil, &ATError{Command: cmd, Status: at.Status()}
+	}
+
+	return at, nil
+}
+
+// command cmd as the XBee's protocol family defines it, at.ErrProtocol when
+// the protocol does not support it
+func (x *XBee) command(cmd *at.Command) (*at.Command, error) {
+	c, ok := cmd.For(x.Protocol())
+	if !ok {
+		return nil, at.ErrProtocol
+	}
+
+	return c, nil
+}
+
+// Get reads the local XBee's parameter for cmd, decoded per the command's
+// parameter type on the XBee's protocol family
+func (x *XBee) Get(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	cmd, err := x.command(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if !cmd.Readable() {
+		return nil, at.ErrAccess
+	}
+
+	r, err := x.localAT(ctx, cmd.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.Decode(r.Data())
+}
+
+// Set validates v against cmd on the XBee's protocol family and writes it to
+// the local XBee's parameter, out of range values are rejected before sending
+func (x *XBee) Set(ctx context.Context, cmd *at.Command, v interface{}) error {
+	p, err := x.encodeParameter(cmd, v)
+	if err != nil {
+		return err
+	}
+
+	_, err = x.localAT(ctx, cmd.Name, p)
+
+	return err
+}
+
+// Execute runs the local XBee's execute only command cmd
+func (x *XBee) Execute(ctx context.Context, cmd *at.Command) error {
+	cmd, err := x.command(cmd)
+	if err != nil {
+		return err
+	}
+	if cmd.Access&at.Execute == 0 {
+		return at.ErrAccess
+	}
+
+	_, err = x.localAT(ctx, cmd.Name, nil)
+
+	return err
+}
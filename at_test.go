@@ -0,0 +1,109 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/at"
+)
+
+func TestXBee_Get(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if string(p[2:4]) != "ID" || len(p) != 4 {
+			t.Fatalf("Expected ID query, but got %s % x", p[2:4], p[4:])
+		}
+		return [][]byte{atResponse(p, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12, 0x34)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	v, err := xbee.Get(ctx, at.PanID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if id, ok := v.(uint64); !ok || id != 0x1234 {
+		t.Fatalf("Expected uint64 0x1234, but got %#v", v)
+	}
+
+	if _, err := xbee.Get(ctx, at.LinkKey); err != at.ErrAccess {
+		t.Fatalf("Expected error %v, but got %v", at.ErrAccess, err)
+	}
+}
+
+func TestXBee_Set(t *testing.T) {
+	sent := 0
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent++
+		if string(p[2:4]) != "CH" || len(p) != 5 || p[4] != 0x0F {
+			t.Fatalf("Expected CH=0x0F, but got %s % x", p[2:4], p[4:])
+		}
+		return [][]byte{atResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := xbee.Set(ctx, at.Channel, 0x0F); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if err := xbee.Set(ctx, at.Channel, 0x30); err != at.ErrRange {
+		t.Fatalf("Expected error %v, but got %v", at.ErrRange, err)
+	}
+	if err := xbee.Set(ctx, at.SerialNumberHigh, 1); err != at.ErrAccess {
+		t.Fatalf("Expected error %v, but got %v", at.ErrAccess, err)
+	}
+	if sent != 1 {
+		t.Fatalf("Expected rejected values not to be sent, but sent %d", sent)
+	}
+}
+
+func TestXBee_Execute(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if string(p[2:4]) != "FR" || len(p) != 4 {
+			t.Fatalf("Expected FR, but got %s % x", p[2:4], p[4:])
+		}
+		return [][]byte{atResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := xbee.Execute(ctx, at.SoftwareReset); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := xbee.Execute(ctx, at.PanID); err != at.ErrAccess {
+		t.Fatalf("Expected error %v, but got %v", at.ErrAccess, err)
+	}
+}
+
+func TestXBee_Get_Protocol(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if string(p[2:4]) != "ID" {
+			t.Fatalf("Expected ID query, but got %s", p[2:4])
+		}
+		return [][]byte{atResponse(p, 0, 0x12, 0x34)}
+	})
+	xbee.SetAPIProtocol(api.ProtocolDigiMesh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	v, err := xbee.Get(ctx, at.PanID)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if id, ok := v.(uint16); !ok || id != 0x1234 {
+		t.Fatalf("Expected uint16 0x1234, but got %#v", v)
+	}
+
+	if _, err := xbee.Get(ctx, at.ParentAddr); err != at.ErrProtocol {
+		t.Fatalf("Expected error %v, but got %v", at.ErrProtocol, err)
+	}
+	if err := xbee.Set(ctx, at.PanID, 0x8000); err != at.ErrRange {
+		t.Fatalf("Expected error %v, but got %v", at.ErrRange, err)
+	}
+}
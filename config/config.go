@@ -0,0 +1,211 @@
+// Package config reads XBee configuration into snapshots that serialize to
+// JSON or YAML, diffs snapshots and desired profiles, and restores
+// configuration by writing only the parameters that differ.
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/at"
+)
+
+// ErrCommand parameter name is not a cataloged AT command
+var ErrCommand = errors.New("unknown AT command")
+
+// status parameters that report the device's identity or runtime state
+// rather than its configuration, they are left out of snapshots, diffs and
+// restores
+var status = map[string]struct{}{
+	at.SerialNumberHigh.String():      {},
+	at.SerialNumberLow.String():       {},
+	at.NetworkAddr.String():           {},
+	at.ParentAddr.String():            {},
+	at.RemainingChildren.String():     {},
+	at.OperatingPanID.String():        {},
+	at.OperatingPanID16.String():      {},
+	at.AssociationIndication.String(): {},
+	at.RSSI.String():                  {},
+	at.ACKFailures.String():           {},
+	at.CCAFailures.String():           {},
+	at.SupplyVoltage.String():         {},
+	at.Temperature.String():           {},
+	at.UnicastHopTime.String():        {},
+	at.BroadcastHopTime.String():      {},
+	at.ReceivedErrors.String():        {},
+	at.GoodPackets.String():           {},
+	at.TransmitErrors.String():        {},
+	at.UnicastAttempts.String():       {},
+	at.SleepStatus.String():           {},
+	at.SleepTime.String():             {},
+	at.WakeTime.String():              {},
+	at.MissedSyncs.String():           {},
+	at.MissedSleeps.String():          {},
+}
+
+// isStatus reports whether the named parameter is a status parameter
+func isStatus(name string) bool {
+	_, ok := status[name]
+	return ok
+}
+
+// Parameters AT parameter values keyed by command name, values are formatted
+// with at.Command.Format
+type Parameters map[string]string
+
+// Profile desired parameter values, imported from JSON or YAML, only the
+// parameters present are managed
+type Profile struct {
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Parameters Parameters `json:"parameters" yaml:"parameters"`
+}
+
+// Change a parameter whose value differs, From or To is empty when the
+// parameter is absent
+type Change struct {
+	Command string `json:"command" yaml:"command"`
+	From    string `json:"from" yaml:"from"`
+	To      string `json:"to" yaml:"to"`
+}
+
+// Diff changes from one set of parameters to another, sorted by command name,
+// status parameters are ignored
+func Diff(from, to Parameters) []Change {
+	names := make(map[string]struct{})
+	for name := range from {
+		names[name] = struct{}{}
+	}
+	for name := range to {
+		names[name] = struct{}{}
+	}
+	for name := range status {
+		delete(names, name)
+	}
+
+	var changes []Change
+	for name := range names {
+		f, fok := from[name]
+		t, tok := to[name]
+		if fok && tok && equal(name, f, t) {
+			continue
+		}
+
+		changes = append(changes, Change{Command: name, From: f, To: t})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Command < changes[j].Command
+	})
+
+	return changes
+}
+
+// Diff changes needed to bring the snapshot's parameters to the profile,
+// parameters not in the profile are ignored
+func (p *Profile) Diff(s *Snapshot) []Change {
+	have := make(Parameters, len(p.Parameters))
+	for name := range p.Parameters {
+		if v, ok := s.Parameters[name]; ok {
+			have[name] = v
+		}
+	}
+
+	return Diff(have, p.Parameters)
+}
+
+// Apply writes the profile's parameters that differ from the device's current
+// values, see Restore
+func (p *Profile) Apply(ctx context.Context, d gobee.Device, options ...func(interface{})) ([]Change, error) {
+	return Restore(ctx, d, p.Parameters, options...)
+}
+
+// Restore writes the parameters of want that differ from the device's current
+// values in a gobee.ConfigTransaction, options are passed to
+// gobee.NewConfigTransaction. Status and read-only parameters are skipped and
+// write-only parameters, which cannot be compared, are always written.
+// Parameters the device's protocol family does not support fail with
+// at.ErrProtocol.
+func Restore(ctx context.Context, d gobee.Device, want Parameters, options ...func(interface{})) ([]Change, error) {
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := gobee.NewConfigTransaction(d, options...)
+
+	var changes []Change
+	for _, name := range names {
+		cmd, ok := at.Lookup(name)
+		if !ok {
+			return nil, ErrCommand
+		}
+		if cmd, ok = cmd.For(d.Protocol()); !ok {
+			return nil, at.ErrProtocol
+		}
+		if !cmd.Writable() || isStatus(name) {
+			continue
+		}
+
+		v, err := cmd.Parse(want[name])
+		if err != nil {
+			return nil, err
+		}
+
+		var current string
+		if cmd.Readable() {
+			c, err := d.GetParameter(ctx, cmd)
+			if err != nil {
+				return nil, err
+			}
+
+			current = cmd.Format(c)
+			if equal(name, current, want[name]) {
+				continue
+			}
+		}
+
+		if err := t.Stage(cmd, v); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, Change{Command: name, From: current, To: want[name]})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if err := t.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// equal compares parameter values by their parsed value, so that 0x0F and 15
+// are equal, values of unknown commands are compared as strings
+func equal(name, a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	cmd, ok := at.Lookup(name)
+	if !ok {
+		return false
+	}
+
+	av, err := cmd.Parse(a)
+	if err != nil {
+		return false
+	}
+	bv, err := cmd.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+// device a fake gobee.Device holding decoded parameter values, queued values
+// are applied by ApplyChanges and commands without a value are invalid
+type device struct {
+	values  map[string]interface{}
+	queued  map[string]interface{}
+	read    []string
+	written []string
+}
+
+func newDevice(values map[string]interface{}) *device {
+	return &device{values: values, queued: make(map[string]interface{})}
+}
+
+func (d *device) Addr64(ctx context.Context) (uint64, error)   { return 0x0013A20040522BAA, nil }
+func (d *device) NodeID(ctx context.Context) (string, error)   { return "", nil }
+func (d *device) Firmware(ctx context.Context) (uint16, error) { return 0, nil }
+
+func (d *device) GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	d.read = append(d.read, cmd.String())
+
+	v, ok := d.values[cmd.String()]
+	if !ok {
+		return nil, &gobee.ATError{Command: cmd.Name, Status: 2}
+	}
+
+	return v, nil
+}
+
+func (d *device) SetParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	if err := d.QueueParameter(ctx, cmd, v); err != nil {
+		return err
+	}
+
+	return d.ApplyChanges(ctx)
+}
+
+func (d *device) QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	p, err := cmd.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	d.queued[cmd.String()], _ = cmd.Decode(p)
+	d.written = append(d.written, cmd.String())
+
+	return nil
+}
+
+func (d *device) ApplyChanges(ctx context.Context) error {
+	for k, v := range d.queued {
+		d.values[k] = v
+	}
+	d.queued = make(map[string]interface{})
+
+	return nil
+}
+
+func (d *device) WriteChanges(ctx context.Context) error        { return nil }
+func (d *device) SoftwareReset(ctx context.Context) error       { return nil }
+func (d *device) ReadIO(ctx context.Context) (rx.Sample, error) { return rx.Sample{}, nil }
+func (d *device) Protocol() api.Protocol                        { return api.ProtocolZigbee }
+
+func TestRead(t *testing.T) {
+	d := newDevice(map[string]interface{}{
+		"ID": uint64(0x1234),
+		"CH": uint8(0x0F),
+		"NI": "PUMP-3",
+		"CE": uint8(1),
+		"MY": uint16(0x5A2B),
+		"DB": uint8(0x2D),
+	})
+
+	s, err := Read(context.Background(), d)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := Parameters{
+		"ID": "0x1234",
+		"CH": "0xf",
+		"NI": "PUMP-3",
+		"CE": at.CoordinatorEnable.Format(uint8(1)),
+	}
+	if s.Addr64 != 0x0013A20040522BAA || !reflect.DeepEqual(s.Parameters, expected) {
+		t.Fatalf("Expected %v, but got %#x %v", expected, s.Addr64, s.Parameters)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	var decoded Snapshot
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(decoded.Diff(s)) != 0 {
+		t.Fatalf("Expected decoded snapshot to equal snapshot, but got %v", decoded.Diff(s))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := Parameters{"ID": "0x1234", "CH": "0xf", "NI": "PUMP-3", "TP": "0x1a"}
+	to := Parameters{"ID": "4660", "CH": "0x10", "PL": "0x4", "TP": "0x1f"}
+
+	expected := []Change{
+		{Command: "CH", From: "0xf", To: "0x10"},
+		{Command: "NI", From: "PUMP-3", To: ""},
+		{Command: "PL", From: "", To: "0x4"},
+	}
+
+	if actual := Diff(from, to); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, actual)
+	}
+
+	p := &Profile{Parameters: Parameters{"CH": "0x0F", "PL": "0x4"}}
+	expected = []Change{{Command: "PL", From: "", To: "0x4"}}
+
+	if actual := p.Diff(&Snapshot{Parameters: from}); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, actual)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	d := newDevice(map[string]interface{}{
+		"ID": uint64(0x1234),
+		"CH": uint8(0x0F),
+		"NI": "PUMP-3",
+		"SH": uint32(0x0013A200),
+		"MY": uint16(0x5A2B),
+		"EA": uint16(3),
+	})
+
+	changes, err := Restore(context.Background(), d, Parameters{
+		"ID": "0x1234",
+		"CH": "0x10",
+		"NI": "PUMP-4",
+		"SH": "0x0",
+		"MY": "0x0",
+		"EA": "0x0",
+		"KY": "0A0B",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []Change{
+		{Command: "CH", From: "0xf", To: "0x10"},
+		{Command: "KY", From: "", To: "0A0B"},
+		{Command: "NI", From: "PUMP-3", To: "PUMP-4"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, changes)
+	}
+
+	sort.Strings(d.written)
+	if !reflect.DeepEqual(d.written, []string{"CH", "KY", "NI"}) {
+		t.Fatalf("Expected only differing parameters written, but got %v", d.written)
+	}
+	if d.values["CH"] != uint8(0x10) || d.values["NI"] != "PUMP-4" {
+		t.Fatalf("Expected parameters applied, but got %v", d.values)
+	}
+
+	if _, err := Restore(context.Background(), d, Parameters{"ZZ": "1"}); err != ErrCommand {
+		t.Fatalf("Expected error %v, but got %v", ErrCommand, err)
+	}
+	if _, err := Restore(context.Background(), d, Parameters{"MR": "0x1"}); err != at.ErrProtocol {
+		t.Fatalf("Expected error %v, but got %v", at.ErrProtocol, err)
+	}
+}
@@ -0,0 +1,342 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/at"
+)
+
+const (
+	// DefaultAuditInterval time between audits of every watched device
+	DefaultAuditInterval = time.Hour
+	// DefaultRequestInterval minimum time between AT requests of an audit
+	DefaultRequestInterval = 250 * time.Millisecond
+
+	driftEventBufferSize = 16
+)
+
+// DriftEvent a device's parameters differ from their desired values, or the
+// device could not be audited
+type DriftEvent struct {
+	Addr64 uint64
+	Time   time.Time
+	// Changes needed to bring the device to its desired values
+	Changes []Change
+	// Remediated the changes were written to the device
+	Remediated bool
+	// Err audit or remediation failure
+	Err error
+}
+
+// Monitor periodically audits watched devices against their desired
+// parameters, emitting a DriftEvent for each device that has drifted
+type Monitor struct {
+	auditInterval   time.Duration
+	requestInterval time.Duration
+	remediate       bool
+
+	mu      sync.Mutex
+	watched []watched
+}
+
+// watched a device and its desired parameters
+type watched struct {
+	device  gobee.Device
+	desired Parameters
+}
+
+// AuditIntervalSetter interface for AuditInterval setters
+type AuditIntervalSetter interface {
+	SetAuditInterval(time.Duration)
+}
+
+// RequestIntervalSetter interface for RequestInterval setters
+type RequestIntervalSetter interface {
+	SetRequestInterval(time.Duration)
+}
+
+// RemediateSetter interface for Remediate setters
+type RemediateSetter interface {
+	SetRemediate(bool)
+}
+
+// AuditInterval helper option function to NewMonitor, time between audits, an
+// interval of 0 audits once
+func AuditInterval(interval time.Duration) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(AuditIntervalSetter); ok {
+			s.SetAuditInterval(interval)
+		}
+	}
+}
+
+// RequestInterval helper option function to NewMonitor, minimum time between
+// AT requests so audits do not flood the network, an interval of 0 disables
+// rate limiting
+func RequestInterval(interval time.Duration) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(RequestIntervalSetter); ok {
+			s.SetRequestInterval(interval)
+		}
+	}
+}
+
+// Remediate helper option function to NewMonitor, write desired values to
+// devices that have drifted
+func Remediate(remediate bool) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(RemediateSetter); ok {
+			s.SetRemediate(remediate)
+		}
+	}
+}
+
+// NewMonitor constructs a Monitor
+func NewMonitor(options ...func(interface{})) *Monitor {
+	m := &Monitor{
+		auditInterval:   DefaultAuditInterval,
+		requestInterval: DefaultRequestInterval,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(m)
+	}
+
+	return m
+}
+
+// SetAuditInterval satisfy AuditIntervalSetter interface
+func (m *Monitor) SetAuditInterval(interval time.Duration) {
+	m.auditInterval = interval
+}
+
+// SetRequestInterval satisfy RequestIntervalSetter interface
+func (m *Monitor) SetRequestInterval(interval time.Duration) {
+	m.requestInterval = interval
+}
+
+// SetRemediate satisfy RemediateSetter interface
+func (m *Monitor) SetRemediate(remediate bool) {
+	m.remediate = remediate
+}
+
+// Watch audits the device against desired, replacing its desired parameters
+// if already watched. Write-only parameters cannot be audited and are ignored,
+// parameters the device's protocol does not support fail the audit with
+// at.ErrProtocol.
+func (m *Monitor) Watch(device gobee.Device, desired Parameters) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.watched {
+		if m.watched[i].device == device {
+			m.watched[i].desired = desired
+			return
+		}
+	}
+
+	m.watched = append(m.watched, watched{device: device, desired: desired})
+}
+
+// Forget stops auditing the device
+func (m *Monitor) Forget(device gobee.Device) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.watched {
+		if m.watched[i].device == device {
+			m.watched = append(m.watched[:i], m.watched[i+1:]...)
+			return
+		}
+	}
+}
+
+// Run audits every watched device immediately and then every audit interval
+// until ctx is done, the channel is closed when ctx is done or, without an
+// audit interval, after the first audit
+func (m *Monitor) Run(ctx context.Context) <-chan DriftEvent {
+	c := make(chan DriftEvent, driftEventBufferSize)
+
+	go func() {
+		defer close(c)
+
+		var limit <-chan time.Time
+		if m.requestInterval > 0 {
+			t := time.NewTicker(m.requestInterval)
+			defer t.Stop()
+			limit = t.C
+		}
+
+		var audit <-chan time.Time
+		if m.auditInterval > 0 {
+			t := time.NewTicker(m.auditInterval)
+			defer t.Stop()
+			audit = t.C
+		}
+
+		for {
+			m.mu.Lock()
+			devices := append([]watched(nil), m.watched...)
+			m.mu.Unlock()
+
+			for _, w := range devices {
+				d := &limitedDevice{Device: w.device, limit: limit}
+
+				e, drifted := m.audit(ctx, d, w.desired)
+				if ctx.Err() != nil {
+					return
+				}
+				if !drifted {
+					continue
+				}
+
+				select {
+				case c <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if audit == nil {
+				return
+			}
+
+			select {
+			case <-audit:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c
+}
+
+// audit reads the device's desired parameters and remediates drift
+func (m *Monitor) audit(ctx context.Context, d gobee.Device, desired Parameters) (DriftEvent, bool) {
+	var e DriftEvent
+
+	e.Addr64, e.Err = d.Addr64(ctx)
+	if e.Err != nil {
+		return e, true
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	want := make(Parameters)
+	have := make(Parameters)
+	values := make(map[string]interface{})
+	for _, name := range names {
+		cmd, ok := at.Lookup(name)
+		if !ok {
+			e.Err = ErrCommand
+			return e, true
+		}
+		if cmd, ok = cmd.For(d.Protocol()); !ok {
+			e.Err = at.ErrProtocol
+			return e, true
+		}
+		if !cmd.Readable() {
+			continue
+		}
+
+		v, err := d.GetParameter(ctx, cmd)
+		if err != nil {
+			e.Err = err
+			return e, true
+		}
+
+		want[name] = desired[name]
+		have[name] = cmd.Format(v)
+		values[name] = v
+	}
+
+	e.Time = time.Now()
+	e.Changes = Diff(have, want)
+	if len(e.Changes) == 0 {
+		return e, false
+	}
+
+	if m.remediate {
+		drifted := make(Parameters, len(e.Changes))
+		for _, c := range e.Changes {
+			drifted[c.Command] = c.To
+		}
+
+		_, e.Err = Restore(ctx, &auditedDevice{Device: d, values: values}, drifted)
+		e.Remediated = e.Err == nil
+	}
+
+	return e, true
+}
+
+// auditedDevice a gobee.Device answering GetParameter with the values an
+// audit has just read, so remediation does not read them again
+type auditedDevice struct {
+	gobee.Device
+	values map[string]interface{}
+}
+
+func (d *auditedDevice) GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	if v, ok := d.values[cmd.String()]; ok {
+		return v, nil
+	}
+
+	return d.Device.GetParameter(ctx, cmd)
+}
+
+// limitedDevice a gobee.Device waiting for the rate limit, if any, before
+// each request an audit or remediation makes
+type limitedDevice struct {
+	gobee.Device
+	limit <-chan time.Time
+}
+
+func (d *limitedDevice) wait(ctx context.Context) error {
+	if d.limit == nil {
+		return nil
+	}
+
+	select {
+	case <-d.limit:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *limitedDevice) GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	if err := d.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return d.Device.GetParameter(ctx, cmd)
+}
+
+func (d *limitedDevice) QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	if err := d.wait(ctx); err != nil {
+		return err
+	}
+
+	return d.Device.QueueParameter(ctx, cmd, v)
+}
+
+func (d *limitedDevice) ApplyChanges(ctx context.Context) error {
+	if err := d.wait(ctx); err != nil {
+		return err
+	}
+
+	return d.Device.ApplyChanges(ctx)
+}
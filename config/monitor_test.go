@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+func TestMonitor(t *testing.T) {
+	drifted := newDevice(map[string]interface{}{"CH": uint8(0x0B), "NI": "PUMP-3"})
+	matching := newDevice(map[string]interface{}{"CH": uint8(0x0C), "NI": "PUMP-4"})
+
+	m := NewMonitor(RequestInterval(time.Millisecond), AuditInterval(time.Hour), Remediate(true))
+	m.Watch(drifted, Parameters{"CH": "0x0C", "NI": "PUMP-3", "KY": "0A0B"})
+	m.Watch(matching, Parameters{"CH": "0x0C"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events := m.Run(ctx)
+
+	e, ok := <-events
+	if !ok {
+		t.Fatalf("Expected drift event, but events closed")
+	}
+
+	expected := []Change{{Command: "CH", From: "0xb", To: "0x0C"}}
+	if e.Err != nil || !e.Remediated || !reflect.DeepEqual(e.Changes, expected) {
+		t.Fatalf("Expected remediated changes %v, but got %+v", expected, e)
+	}
+	if drifted.values["CH"] != uint8(0x0C) {
+		t.Fatalf("Expected CH remediated, but got %v", drifted.values["CH"])
+	}
+	if !reflect.DeepEqual(drifted.read, []string{"CH", "NI"}) {
+		t.Fatalf("Expected parameters read once, but got %v", drifted.read)
+	}
+
+	cancel()
+	if e, ok := <-events; ok {
+		t.Fatalf("Expected a single drift event, but got %+v", e)
+	}
+}
+
+func TestMonitor_Protocol(t *testing.T) {
+	d := newDevice(map[string]interface{}{"CH": uint8(0x0B)})
+
+	m := NewMonitor(RequestInterval(0), AuditInterval(0))
+	m.Watch(d, Parameters{"CH": "0x0C", "MR": "0x1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if e, ok := <-m.Run(ctx); !ok || e.Err != at.ErrProtocol {
+		t.Fatalf("Expected error %v, but got %+v", at.ErrProtocol, e)
+	}
+}
+
+func TestMonitor_Forget(t *testing.T) {
+	d := newDevice(map[string]interface{}{"CH": uint8(0x0B)})
+
+	m := NewMonitor(RequestInterval(time.Millisecond))
+	m.Watch(d, Parameters{"CH": "0x0C"})
+	m.Forget(d)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if e, ok := <-m.Run(ctx); ok {
+		t.Fatalf("Expected no drift events, but got %+v", e)
+	}
+}
+
+func TestMonitor_Once(t *testing.T) {
+	d := newDevice(map[string]interface{}{"CH": uint8(0x0B)})
+
+	m := NewMonitor(RequestInterval(0), AuditInterval(0))
+	m.Watch(d, Parameters{"CH": "0x0C"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events := m.Run(ctx)
+
+	if e, ok := <-events; !ok || e.Remediated || len(e.Changes) != 1 {
+		t.Fatalf("Expected unremediated drift event, but got %+v", e)
+	}
+	if e, ok := <-events; ok {
+		t.Fatalf("Expected events closed after one audit, but got %+v", e)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("Expected events closed before ctx is done, but got %v", ctx.Err())
+	}
+}
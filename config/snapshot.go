@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/at"
+)
+
+// atStatusInvalidCommand AT command response status, the firmware does not
+// support the command
+const atStatusInvalidCommand byte = 2
+
+// Snapshot every readable configuration parameter of a device at a point in
+// time, it serializes to JSON or YAML
+type Snapshot struct {
+	Addr64     uint64     `json:"addr64" yaml:"addr64"`
+	Taken      time.Time  `json:"taken" yaml:"taken"`
+	Parameters Parameters `json:"parameters" yaml:"parameters"`
+}
+
+// Read reads every readable parameter the device's protocol family supports,
+// status parameters and parameters the firmware rejects as invalid commands
+// are omitted
+func Read(ctx context.Context, d gobee.Device) (*Snapshot, error) {
+	addr64, err := d.Addr64(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Addr64:     addr64,
+		Taken:      time.Now(),
+		Parameters: make(Parameters),
+	}
+
+	for _, cmd := range at.Commands() {
+		cmd, ok := cmd.For(d.Protocol())
+		if !ok || !cmd.Readable() || cmd.Type == at.TypeNone || isStatus(cmd.String()) {
+			continue
+		}
+
+		v, err := d.GetParameter(ctx, cmd)
+		if e, ok := err.(*gobee.ATError); ok && e.Status == atStatusInvalidCommand {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.Parameters[cmd.String()] = cmd.Format(v)
+	}
+
+	return s, nil
+}
+
+// Diff changes from the snapshot to another snapshot
+func (s *Snapshot) Diff(to *Snapshot) []Change {
+	return Diff(s.Parameters, to.Parameters)
+}
+
+// Restore writes the snapshot's parameters that differ from the device's
+// current values, see Restore
+func (s *Snapshot) Restore(ctx context.Context, d gobee.Device, options ...func(interface{})) ([]Change, error) {
+	return Restore(ctx, d, s.Parameters, options...)
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+// xproProfile the XCTU profile document within an .xpro archive
+const xproProfile = "profile.xml"
+
+// ErrXPro archive is not an XCTU profile
+var ErrXPro = errors.New("not an XCTU profile")
+
+// UnknownCommandsError an XCTU profile has settings for commands not in the at
+// catalog
+type UnknownCommandsError struct {
+	Commands []string
+}
+
+func (e *UnknownCommandsError) Error() string {
+	return fmt.Sprintf("unknown AT commands %s", strings.Join(e.Commands, ", "))
+}
+
+// Unwrap satisfy errors.Unwrap, an UnknownCommandsError is an ErrCommand
+func (e *UnknownCommandsError) Unwrap() error {
+	return ErrCommand
+}
+
+// xpro XCTU profile document
+type xpro struct {
+	XMLName xml.Name `xml:"data"`
+	Profile struct {
+		Description string `xml:"description"`
+		Settings    []struct {
+			Command string `xml:"command,attr"`
+			Value   string `xml:",chardata"`
+		} `xml:"settings>setting"`
+	} `xml:"profile"`
+}
+
+// OpenXPro reads the XCTU profile (.xpro) file at name, see ReadXPro
+func OpenXPro(name string) (*Profile, error) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return readXPro(&r.Reader)
+}
+
+// ReadXPro reads an XCTU profile (.xpro), a zip archive holding the profile's
+// settings as XML, into a Profile. XCTU writes numeric settings in hex without
+// a prefix. Empty numeric settings are skipped. Settings for commands not in
+// the at catalog are left out of the Profile, which is returned along with an
+// *UnknownCommandsError naming them.
+func ReadXPro(r io.ReaderAt, size int64) (*Profile, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return readXPro(z)
+}
+
+func readXPro(z *zip.Reader) (*Profile, error) {
+	for _, f := range z.File {
+		if path.Base(f.Name) != xproProfile {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseXPro(b)
+	}
+
+	return nil, ErrXPro
+}
+
+func parseXPro(b []byte) (*Profile, error) {
+	var doc xpro
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	p := &Profile{
+		Name:       strings.TrimSpace(doc.Profile.Description),
+		Parameters: make(Parameters),
+	}
+
+	var unknown []string
+	for _, s := range doc.Profile.Settings {
+		cmd, ok := at.Lookup(s.Command)
+		if !ok {
+			unknown = append(unknown, s.Command)
+			continue
+		}
+
+		value := s.Value
+		switch cmd.Type {
+		case at.TypeString:
+		case at.TypeBytes:
+			value = strings.TrimSpace(value)
+		default:
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			value = "0x" + value
+		}
+
+		v, err := cmd.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s: %w", s.Command, err)
+		}
+
+		p.Parameters[cmd.String()] = cmd.Format(v)
+	}
+
+	if len(unknown) > 0 {
+		return p, &UnknownCommandsError{Commands: unknown}
+	}
+
+	return p, nil
+}
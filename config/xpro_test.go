@@ -0,0 +1,109 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+const profileXML = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<data>
+  <profile>
+    <description_file>xb3-24_1008.xml</description_file>
+    <settings>
+      <setting command="CH">C</setting>
+      <setting command="ID">3332</setting>
+      <setting command="NI">PUMP-3</setting>
+      <setting command="CE">1</setting>
+      <setting command="DH"></setting>
+      <setting command="ZZ">1</setting>
+    </settings>
+    <description>Pump controller</description>
+    <reset_settings>true</reset_settings>
+    <flash_firmware>false</flash_firmware>
+  </profile>
+</data>`
+
+func xproArchive(t *testing.T, name, content string) *bytes.Reader {
+	var b bytes.Buffer
+
+	z := zip.NewWriter(&b)
+	w, err := z.Create(name)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	w.Write([]byte(content))
+	if err := z.Close(); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	return bytes.NewReader(b.Bytes())
+}
+
+func TestReadXPro(t *testing.T) {
+	r := xproArchive(t, "profile.xml", profileXML)
+
+	p, err := ReadXPro(r, r.Size())
+	e, ok := err.(*UnknownCommandsError)
+	if !ok || !reflect.DeepEqual(e.Commands, []string{"ZZ"}) || !errors.Is(err, ErrCommand) {
+		t.Fatalf("Expected unknown command ZZ, but got %v", err)
+	}
+
+	expected := Parameters{
+		"CH": "0xc",
+		"ID": "0x3332",
+		"NI": "PUMP-3",
+		"CE": at.CoordinatorEnable.Format(uint64(1)),
+	}
+	if p.Name != "Pump controller" || !reflect.DeepEqual(p.Parameters, expected) {
+		t.Fatalf("Expected Pump controller %v, but got %q %v", expected, p.Name, p.Parameters)
+	}
+
+	d := newDevice(map[string]interface{}{
+		"CH": uint8(0x0B),
+		"ID": uint64(0x3332),
+		"NI": "PUMP-3",
+		"CE": uint8(1),
+	})
+	changes, err := p.Apply(context.Background(), d)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	expectedChanges := []Change{{Command: "CH", From: "0xb", To: "0xc"}}
+	if !reflect.DeepEqual(changes, expectedChanges) || d.values["CH"] != uint8(0x0C) {
+		t.Fatalf("Expected changes %v, but got %v", expectedChanges, changes)
+	}
+}
+
+func TestReadXPro_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		content string
+	}{
+		{"Missing Profile", "radio_fw/firmware.gbl", ""},
+		{"Invalid Value", "profile.xml", `<data><profile><settings><setting command="CH">XY</setting></settings></profile></data>`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			r := xproArchive(t, tt.archive, tt.content)
+
+			if p, err := ReadXPro(r, r.Size()); err == nil {
+				t.Fatalf("Expected error, but got %v", p)
+			}
+		})
+	}
+
+	r := xproArchive(t, "radio_fw/firmware.gbl", "")
+	if _, err := ReadXPro(r, r.Size()); err != ErrXPro {
+		t.Fatalf("Expected error %v, but got %v", ErrXPro, err)
+	}
+}
@@ -0,0 +1,310 @@
+package gobee
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// Device an XBee configured and sampled through the local XBee, either the
+// local XBee itself or a remote XBee
+type Device interface {
+	// Addr64 64-bit address of the XBee
+	Addr64(ctx context.Context) (uint64, error)
+	// NodeID node identifier (NI) of the XBee
+	NodeID(ctx context.Context) (string, error)
+	// Firmware firmware version (VR) of the XBee
+	Firmware(ctx context.Context) (uint16, error)
+	// GetParameter reads the parameter for cmd, decoded per the command's parameter type
+	GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error)
+	// SetParameter writes the parameter for cmd and applies it
+	SetParameter(ctx context.Context, cmd *at.Command, v interface{}) error
+	// QueueParameter writes the parameter for cmd without applying it
+	QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error
+	// ApplyChanges applies queued parameters (AC)
+	ApplyChanges(ctx context.Context) error
+	// WriteChanges writes parameters to non-volatile memory (WR)
+	WriteChanges(ctx context.Context) error
+	// SoftwareReset resets the XBee (FR)
+	SoftwareReset(ctx context.Context) error
+	// ReadIO forces a sample of the XBee's enabled IO lines (IS)
+	ReadIO(ctx context.Context) (rx.Sample, error)
+	// Protocol protocol family of the XBee's firmware
+	Protocol() api.Protocol
+}
+
+// LocalDevice the XBee attached to the UART
+type LocalDevice struct {
+	xbee *XBee
+
+	mu     sync.Mutex
+	addr64 uint64
+	read   bool
+}
+
+// Local constructs a LocalDevice for the local XBee
+func (x *XBee) Local() *LocalDevice {
+	return &LocalDevice{xbee: x}
+}
+
+// Addr64 64-bit address of the local XBee, read from SH and SL on first use
+func (d *LocalDevice) Addr64(ctx context.Context) (uint64, error) {
+	d.mu.Lock()
+	addr64, read := d.addr64, d.read
+	d.mu.Unlock()
+
+	if read {
+		return addr64, nil
+	}
+
+	addr64, err := readAddr64(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+
+	d.mu.Lock()
+	d.addr64, d.read = addr64, true
+	d.mu.Unlock()
+
+	return addr64, nil
+}
+
+// NodeID node identifier (NI) of the local XBee
+func (d *LocalDevice) NodeID(ctx context.Context) (string, error) {
+	return readNodeID(ctx, d)
+}
+
+// Firmware firmware version (VR) of the local XBee
+func (d *LocalDevice) Firmware(ctx context.Context) (uint16, error) {
+	return readFirmware(ctx, d)
+}
+
+// GetParameter reads the local XBee's parameter for cmd with tx.AT
+func (d *LocalDevice) GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	return d.xbee.Get(ctx, cmd)
+}
+
+// SetParameter writes the local XBee's parameter for cmd with tx.AT, which
+// applies it along with any queued parameters
+func (d *LocalDevice) SetParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	return d.xbee.Set(ctx, cmd, v)
+}
+
+// QueueParameter queues the local XBee's parameter for cmd with tx.ATQueue
+func (d *LocalDevice) QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	p, err := d.xbee.encodeParameter(cmd, v)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.xbee.localATQueue(ctx, cmd.Name, p)
+
+	return err
+}
+
+// ApplyChanges applies the local XBee's queued parameters
+func (d *LocalDevice) ApplyChanges(ctx context.Context) error {
+	return d.execute(ctx, at.ApplyChanges)
+}
+
+// WriteChanges writes the local XBee's parameters to non-volatile memory
+func (d *LocalDevice) WriteChanges(ctx context.Context) error {
+	return d.execute(ctx, at.WriteChanges)
+}
+
+// SoftwareReset resets the local XBee
+func (d *LocalDevice) SoftwareReset(ctx context.Context) error {
+	return d.execute(ctx, at.SoftwareReset)
+}
+
+// ReadIO forces a sample of the local XBee's enabled IO lines
+func (d *LocalDevice) ReadIO(ctx context.Context) (rx.Sample, error) {
+	return d.xbee.ForceSample(ctx, api.LocalAddr64)
+}
+
+// Send transmits payload from the local XBee to the XBee at addr and waits
+// for the transmit status
+func (d *LocalDevice) Send(ctx context.Context, addr uint64, payload []byte) error {
+	return d.xbee.send(ctx, addr, api.BroadcastAddr16, payload)
+}
+
+// Protocol protocol family of the local XBee's firmware
+func (d *LocalDevice) Protocol() api.Protocol {
+	return d.xbee.Protocol()
+}
+
+func (d *LocalDevice) execute(ctx context.Context, cmd *at.Command) error {
+	return d.xbee.Execute(ctx, cmd)
+}
+
+// Addr64 64-bit address of the remote XBee
+func (d *RemoteDevice) Addr64(ctx context.Context) (uint64, error) {
+	return d.addr64, nil
+}
+
+// NodeID node identifier (NI) of the remote XBee
+func (d *RemoteDevice) NodeID(ctx context.Context) (string, error) {
+	return readNodeID(ctx, d)
+}
+
+// Firmware firmware version (VR) of the remote XBee
+func (d *RemoteDevice) Firmware(ctx context.Context) (uint16, error) {
+	return readFirmware(ctx, d)
+}
+
+// GetParameter reads the remote XBee's parameter for cmd with tx.ATRemote
+func (d *RemoteDevice) GetParameter(ctx context.Context, cmd *at.Command) (interface{}, error) {
+	cmd, err := d.xbee.command(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if !cmd.Readable() {
+		return nil, at.ErrAccess
+	}
+
+	r, err := d.xbee.remoteAT(ctx, d.addr64, cmd.Name, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.Decode(r.Data())
+}
+
+// SetParameter writes the remote XBee's parameter for cmd with tx.ATRemote
+// and the apply changes option
+func (d *RemoteDevice) SetParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	return d.set(ctx, cmd, v, tx.OptionApplyChanges)
+}
+
+// QueueParameter writes the remote XBee's parameter for cmd with tx.ATRemote
+// without the apply changes option
+func (d *RemoteDevice) QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	return d.set(ctx, cmd, v, 0)
+}
+
+// ApplyChanges applies the remote XBee's queued parameters
+func (d *RemoteDevice) ApplyChanges(ctx context.Context) error {
+	return d.execute(ctx, at.ApplyChanges)
+}
+
+// WriteChanges writes the remote XBee's parameters to non-volatile memory
+func (d *RemoteDevice) WriteChanges(ctx context.Context) error {
+	return d.execute(ctx, at.WriteChanges)
+}
+
+// SoftwareReset resets the remote XBee
+func (d *RemoteDevice) SoftwareReset(ctx context.Context) error {
+	return d.execute(ctx, at.SoftwareReset)
+}
+
+// ReadIO forces a sample of the remote XBee's enabled IO lines
+func (d *RemoteDevice) ReadIO(ctx context.Context) (rx.Sample, error) {
+	return d.xbee.ForceSample(ctx, d.addr64)
+}
+
+// Send transmits payload to the remote XBee and waits for the transmit status
+func (d *RemoteDevice) Send(ctx context.Context, payload []byte) error {
+	return d.xbee.send(ctx, d.addr64, api.BroadcastAddr16, payload)
+}
+
+// Protocol protocol family of the remote XBee's firmware, the same as the
+// local XBee's
+func (d *RemoteDevice) Protocol() api.Protocol {
+	return d.xbee.Protocol()
+}
+
+func (d *RemoteDevice) set(ctx context.Context, cmd *at.Command, v interface{}, options byte) error {
+	p, err := d.xbee.encodeParameter(cmd, v)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.xbee.remoteAT(ctx, d.addr64, cmd.Name, p, options)
+
+	return err
+}
+
+func (d *RemoteDevice) execute(ctx context.Context, cmd *at.Command) error {
+	if _, err := d.xbee.command(cmd); err != nil {
+		return err
+	}
+
+	_, err := d.xbee.remoteAT(ctx, d.addr64, cmd.Name, nil, 0)
+
+	return err
+}
+
+// encodeParameter validates v against cmd on the XBee's protocol family and
+// encodes it
+func (x *XBee) encodeParameter(cmd *at.Command, v interface{}) ([]byte, error) {
+	cmd, err := x.command(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeParameter(cmd, v)
+}
+
+// encodeParameter validates v against cmd and encodes it
+func encodeParameter(cmd *at.Command, v interface{}) ([]byte, error) {
+	if !cmd.Writable() {
+		return nil, at.ErrAccess
+	}
+
+	return cmd.Encode(v)
+}
+
+func readAddr64(ctx context.Context, d Device) (uint64, error) {
+	sh, err := d.GetParameter(ctx, at.SerialNumberHigh)
+	if err != nil {
+		return 0, err
+	}
+
+	sl, err := d.GetParameter(ctx, at.SerialNumberLow)
+	if err != nil {
+		return 0, err
+	}
+
+	high, ok := sh.(uint32)
+	if !ok {
+		return 0, at.ErrType
+	}
+	low, ok := sl.(uint32)
+	if !ok {
+		return 0, at.ErrType
+	}
+
+	return uint64(high)<<32 | uint64(low), nil
+}
+
+func readNodeID(ctx context.Context, d Device) (string, error) {
+	v, err := d.GetParameter(ctx, at.NodeIdentifier)
+	if err != nil {
+		return "", err
+	}
+
+	ni, ok := v.(string)
+	if !ok {
+		return "", at.ErrType
+	}
+
+	return ni, nil
+}
+
+func readFirmware(ctx context.Context, d Device) (uint16, error) {
+	v, err := d.GetParameter(ctx, at.FirmwareVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	vr, ok := v.(uint16)
+	if !ok {
+		return 0, at.ErrType
+	}
+
+	return vr, nil
+}
@@ -0,0 +1,134 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+var _ Device = (*LocalDevice)(nil)
+var _ Device = (*RemoteDevice)(nil)
+
+func TestLocalDevice(t *testing.T) {
+	var sent []string
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent = append(sent, string(p[2:4]))
+		switch string(p[2:4]) {
+		case "SH":
+			return [][]byte{atResponse(p, 0, 0x00, 0x13, 0xA2, 0x00)}
+		case "SL":
+			return [][]byte{atResponse(p, 0, 0x40, 0x52, 0x2B, 0xAA)}
+		case "NI":
+			return [][]byte{atResponse(p, 0, 'G', 'W')}
+		case "VR":
+			return [][]byte{atResponse(p, 0, 0x10, 0x0A)}
+		case "CH":
+			if p[0] != 0x09 || len(p) != 5 || p[4] != 0x0F {
+				t.Fatalf("Expected queued CH=0x0F, but got % x", p)
+			}
+		}
+		return [][]byte{atResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	d := xbee.Local()
+	for i := 0; i < 2; i++ {
+		addr, err := d.Addr64(ctx)
+		if err != nil || addr != 0x0013A20040522BAA {
+			t.Fatalf("Expected 0x0013A20040522BAA, but got %#x, %v", addr, err)
+		}
+	}
+
+	if ni, err := d.NodeID(ctx); err != nil || ni != "GW" {
+		t.Fatalf("Expected GW, but got %q, %v", ni, err)
+	}
+	if vr, err := d.Firmware(ctx); err != nil || vr != 0x100A {
+		t.Fatalf("Expected 0x100A, but got %#x, %v", vr, err)
+	}
+	if err := d.QueueParameter(ctx, at.Channel, 0x0F); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := d.ApplyChanges(ctx); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := d.QueueParameter(ctx, at.SerialNumberLow, 1); err != at.ErrAccess {
+		t.Fatalf("Expected error %v, but got %v", at.ErrAccess, err)
+	}
+
+	expected := []string{"SH", "SL", "NI", "VR", "CH", "AC"}
+	if len(sent) != len(expected) {
+		t.Fatalf("Expected commands %v, but got %v", expected, sent)
+	}
+	for i := range expected {
+		if sent[i] != expected[i] {
+			t.Fatalf("Expected commands %v, but got %v", expected, sent)
+		}
+	}
+}
+
+func TestRemoteDevice_Parameters(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		c := parseRemoteCommand(t, p)
+		sent = append(sent, c)
+		if c.command == "ID" {
+			return [][]byte{atRemoteResponse(p, 0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12, 0x34)}
+		}
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	d := xbee.Remote(0x0013A20040522BAA)
+
+	v, err := d.GetParameter(ctx, at.PanID)
+	if err != nil || v != uint64(0x1234) {
+		t.Fatalf("Expected uint64 0x1234, but got %#v, %v", v, err)
+	}
+	if err := d.SetParameter(ctx, at.Channel, 0x0F); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := d.QueueParameter(ctx, at.Channel, 0x10); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := d.WriteChanges(ctx); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []remoteCommand{
+		{0x00, "ID", []byte{}},
+		{0x02, "CH", []byte{0x0F}},
+		{0x00, "CH", []byte{0x10}},
+		{0x00, "WR", []byte{}},
+	}
+	if len(sent) != len(expected) {
+		t.Fatalf("Expected %d commands, but got %d", len(expected), len(sent))
+	}
+	for i, e := range expected {
+		if sent[i].command != e.command || sent[i].options != e.options || string(sent[i].parameter) != string(e.parameter) {
+			t.Fatalf("Expected command %+v, but got %+v", e, sent[i])
+		}
+	}
+}
+
+func TestRemoteDevice_Send(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if p[0] != 0x10 || p[9] != 0xAA || string(p[14:]) != "on" {
+			t.Fatalf("Expected transmit request of on, but got % x", p)
+		}
+		return [][]byte{txStatus(p, 0x21)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := xbee.Remote(0x0013A20040522BAA).Send(ctx, []byte("on"))
+	if e, ok := err.(*DeliveryError); !ok || e.Status != 0x21 {
+		t.Fatalf("Expected delivery error 0x21, but got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package gobee
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const (
+	// discoveryTimeoutUnit NT is in 100 ms units
+	discoveryTimeoutUnit = 100 * time.Millisecond
+	// discoveryMargin allowance for responses in flight when NT expires
+	discoveryMargin = time.Second
+
+	nodeAddr16Offset     = 0
+	nodeAddr64Offset     = 2
+	nodeIdentifierOffset = 10
+
+	// offsets of the fixed fields following the null terminated node identifier
+	nodeParentOffset         = 0
+	nodeDeviceTypeOffset     = 2
+	nodeStatusOffset         = 3
+	nodeProfileIDOffset      = 4
+	nodeManufacturerIDOffset = 6
+	nodeFixedLength          = 8
+	nodeDTILength            = 4
+	nodeRSSILength           = 1
+	nodeMinimumLength        = nodeIdentifierOffset + 1 + nodeFixedLength
+
+	legacyNodeRSSIOffset       = 10
+	legacyNodeIdentifierOffset = 11
+	legacyNodeMinimumLength    = 12
+)
+
+// ErrNodeRecord node discovery response is not a valid node record
+var ErrNodeRecord = errors.New("invalid node record")
+
+// DeviceType defines node device types
+type DeviceType byte
+
+// Node device types
+const (
+	DeviceCoordinator = DeviceType(0)
+	DeviceRouter      = DeviceType(1)
+	DeviceEndDevice   = DeviceType(2)
+)
+
+// Node a node reported by node discovery
+type Node struct {
+	Addr16         uint16
+	Addr64         uint64
+	NodeIdentifier string
+	ParentAddr     uint16
+	DeviceType     DeviceType
+	Status         byte
+	ProfileID      uint16
+	ManufacturerID uint16
+	// DeviceTypeIdentifier reported when NO bit 0 is set
+	DeviceTypeIdentifier    uint32
+	HasDeviceTypeIdentifier bool
+	// RSSI of the last hop, in -dBm, reported when NO bit 2 is set and always by 802.15.4
+	RSSI    byte
+	HasRSSI bool
+}
+
+// DiscoveryOptions node discovery options
+type DiscoveryOptions struct {
+	// NodeIdentifier when set only the node with this identifier responds
+	NodeIdentifier string
+}
+
+// ParseNode decodes a node discovery response's node record as reported by
+// the protocol family
+func ParseNode(protocol api.Protocol, p []byte) (*Node, error) {
+	if protocol == api.Protocol802154 {
+		return parseLegacyNode(p)
+	}
+
+	if len(p) < nodeMinimumLength {
+		return nil, ErrNodeRecord
+	}
+
+	n := &Node{
+		Addr16: binary.BigEndian.Uint16(p[nodeAddr16Offset:]),
+		Addr64: binary.BigEndian.Uint64(p[nodeAddr64Offset:]),
+	}
+
+	end := bytes.IndexByte(p[nodeIdentifierOffset:], 0)
+	if end < 0 {
+		return nil, ErrNodeRecord
+	}
+	n.NodeIdentifier = string(p[nodeIdentifierOffset : nodeIdentifierOffset+end])
+
+	p = p[nodeIdentifierOffset+end+1:]
+	if len(p) < nodeFixedLength {
+		return nil, ErrNodeRecord
+	}
+
+	n.ParentAddr = binary.BigEndian.Uint16(p[nodeParentOffset:])
+	n.DeviceType = DeviceType(p[nodeDeviceTypeOffset])
+	n.Status = p[nodeStatusOffset]
+	n.ProfileID = binary.BigEndian.Uint16(p[nodeProfileIDOffset:])
+	n.ManufacturerID = binary.BigEndian.Uint16(p[nodeManufacturerIDOffset:])
+
+	p = p[nodeFixedLength:]
+	switch len(p) {
+	case 0:
+	case nodeRSSILength:
+		n.RSSI, n.HasRSSI = p[0], true
+	case nodeDTILength:
+		n.DeviceTypeIdentifier, n.HasDeviceTypeIdentifier = binary.BigEndian.Uint32(p), true
+	case nodeDTILength + nodeRSSILength:
+		n.DeviceTypeIdentifier, n.HasDeviceTypeIdentifier = binary.BigEndian.Uint32(p), true
+		n.RSSI, n.HasRSSI = p[nodeDTILength], true
+	default:
+		return nil, ErrNodeRecord
+	}
+
+	return n, nil
+}
+
+// parseLegacyNode decodes an 802.15.4 node record: MY, SH, SL, DB, NI
+func parseLegacyNode(p []byte) (*Node, error) {
+	if len(p) < legacyNodeMinimumLength {
+		return nil, ErrNodeRecord
+	}
+
+	n := &Node{
+		Addr16:  binary.BigEndian.Uint16(p[nodeAddr16Offset:]),
+		Addr64:  binary.BigEndian.Uint64(p[nodeAddr64Offset:]),
+		RSSI:    p[legacyNodeRSSIOffset],
+		HasRSSI: true,
+	}
+
+	ni := p[legacyNodeIdentifierOffset:]
+	if end := bytes.IndexByte(ni, 0); end >= 0 {
+		ni = ni[:end]
+	}
+	n.NodeIdentifier = string(ni)
+
+	return n, nil
+}
+
+// DiscoverNodes sends ND and streams the nodes responding until the discovery
+// window, the local XBee's NT, ends or ctx is done. The channel is closed when
+// discovery ends. With a NodeIdentifier only that node is awaited.
+func (x *XBee) DiscoverNodes(ctx context.Context, opts DiscoveryOptions) (<-chan *Node, error) {
+	v, err := x.Get(ctx, at.DiscoveryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	window := time.Duration(v.(uint16))*discoveryTimeoutUnit + discoveryMargin
+
+	id := x.NextFrameID()
+	// every response is queued, a slow reader must not lose nodes
+	l := x.listenAll(func(f rx.Frame) bool {
+		r, ok := f.(*rx.AT)
+		return ok && r.ID() == id
+	})
+
+	var ni []byte
+	if opts.NodeIdentifier != "" {
+		ni = []byte(opts.NodeIdentifier)
+	}
+
+	_, err = x.TX(tx.NewAT(tx.FrameID(id), tx.Command(at.NodeDiscover.Name), tx.Parameter(ni)))
+	if err != nil {
+		x.unlisten(l)
+		return nil, err
+	}
+
+	nodes := make(chan *Node, listenerBufferSize)
+
+	go func() {
+		defer close(nodes)
+		defer x.unlisten(l)
+
+		wait, cancel := context.WithTimeout(ctx, window)
+		defer cancel()
+
+		for {
+			f, err := l.next(wait)
+			if err != nil {
+				return
+			}
+
+			r := f.(*rx.AT)
+			if r.Status() != atStatusOK || len(r.Data()) == 0 {
+				return
+			}
+
+			n, err := ParseNode(x.protocol, r.Data())
+			if err != nil {
+				continue
+			}
+
+			select {
+			case nodes <- n:
+			case <-ctx.Done():
+				return
+			}
+
+			if opts.NodeIdentifier != "" {
+				return
+			}
+		}
+	}()
+
+	return nodes, nil
+}
@@ -0,0 +1,192 @@
+package gobee
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+func nodeRecord(addr16 uint16, addr64 uint64, ni string, extra ...byte) []byte {
+	p := []byte{byte(addr16 >> 8), byte(addr16)}
+	for i := 56; i >= 0; i -= 8 {
+		p = append(p, byte(addr64>>uint(i)))
+	}
+	p = append(p, ni...)
+	p = append(p, 0x00, 0xFF, 0xFE, 0x01, 0x00, 0xC1, 0x05, 0x10, 0x1E)
+	return append(p, extra...)
+}
+
+type parseNodeTest struct {
+	name     string
+	protocol api.Protocol
+	input    []byte
+	expected *Node
+	err      error
+}
+
+var parseNodeTests = []parseNodeTest{
+	{"Zigbee", api.ProtocolZigbee,
+		nodeRecord(0x7D84, 0x0013A20040522BAA, "PUMP-3"),
+		&Node{Addr16: 0x7D84, Addr64: 0x0013A20040522BAA, NodeIdentifier: "PUMP-3", ParentAddr: 0xFFFE, DeviceType: DeviceRouter, ProfileID: 0xC105, ManufacturerID: 0x101E},
+		nil},
+	{"Zigbee RSSI", api.ProtocolZigbee,
+		nodeRecord(0x7D84, 0x0013A20040522BAA, "", 0x28),
+		&Node{Addr16: 0x7D84, Addr64: 0x0013A20040522BAA, ParentAddr: 0xFFFE, DeviceType: DeviceRouter, ProfileID: 0xC105, ManufacturerID: 0x101E, RSSI: 0x28, HasRSSI: true},
+		nil},
+	{"Zigbee Device Type Identifier And RSSI", api.ProtocolZigbee,
+		nodeRecord(0x7D84, 0x0013A20040522BAA, "PUMP-3", 0x00, 0x03, 0x00, 0x00, 0x28),
+		&Node{Addr16: 0x7D84, Addr64: 0x0013A20040522BAA, NodeIdentifier: "PUMP-3", ParentAddr: 0xFFFE, DeviceType: DeviceRouter, ProfileID: 0xC105, ManufacturerID: 0x101E,
+			DeviceTypeIdentifier: 0x00030000, HasDeviceTypeIdentifier: true, RSSI: 0x28, HasRSSI: true},
+		nil},
+	{"Zigbee Short", api.ProtocolZigbee,
+		nodeRecord(0x7D84, 0x0013A20040522BAA, "PUMP-3")[:16],
+		nil,
+		ErrNodeRecord},
+	{"Zigbee Trailing Bytes", api.ProtocolZigbee,
+		nodeRecord(0x7D84, 0x0013A20040522BAA, "PUMP-3", 0x01, 0x02),
+		nil,
+		ErrNodeRecord},
+	{"802.15.4", api.Protocol802154,
+		[]byte{0x00, 0x01, 0x00, 0x13, 0xA2, 0x00, 0x40, 0x52, 0x2B, 0xAA, 0x28, 'P', 'U', 'M', 'P', '-', '3', 0x00},
+		&Node{Addr16: 0x0001, Addr64: 0x0013A20040522BAA, NodeIdentifier: "PUMP-3", RSSI: 0x28, HasRSSI: true},
+		nil},
+}
+
+func TestParseNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Parse Node Test Suite", func(t *testing.T) {
+		for _, tt := range parseNodeTests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := ParseNode(tt.protocol, tt.input)
+				if err != tt.err {
+					t.Fatalf("Expected error=%v, but got %v", tt.err, err)
+				}
+				if !reflect.DeepEqual(actual, tt.expected) {
+					t.Fatalf("Expected %+v, but got %+v", tt.expected, actual)
+				}
+			})
+		}
+	})
+}
+
+func TestXBee_DiscoverNodes(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		switch string(p[2:4]) {
+		case "NT":
+			return [][]byte{atResponse(p, 0, 0x00, 0x3C)}
+		case "ND":
+			if len(p) != 4 {
+				t.Fatalf("Expected no node identifier, but got %q", p[4:])
+			}
+			return [][]byte{
+				atResponse(p, 0, nodeRecord(0x0001, 0x0013A20040000001, "PUMP-1")...),
+				atResponse(p, 0, nodeRecord(0x0002, 0x0013A20040000002, "PUMP-2")...),
+				atResponse(p, 0),
+			}
+		}
+		t.Fatalf("Unexpected command %s", p[2:4])
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	nodes, err := xbee.DiscoverNodes(ctx, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	var found []string
+	for n := range nodes {
+		found = append(found, n.NodeIdentifier)
+	}
+
+	if !reflect.DeepEqual(found, []string{"PUMP-1", "PUMP-2"}) {
+		t.Fatalf("Expected PUMP-1 and PUMP-2, but got %v", found)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Expected discovery to end before the context")
+	}
+}
+
+func TestXBee_DiscoverNodes_Burst(t *testing.T) {
+	nd := make(chan []byte, 1)
+	xbee, radio := newRadio(func(p []byte) [][]byte {
+		switch string(p[2:4]) {
+		case "NT":
+			return [][]byte{atResponse(p, 0, 0x00, 0x3C)}
+		case "ND":
+			nd <- append([]byte(nil), p...)
+			return nil
+		}
+		t.Fatalf("Unexpected command %s", p[2:4])
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	nodes, err := xbee.DiscoverNodes(ctx, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	// every response arrives before any node is read
+	p := <-nd
+	count := 4 * listenerBufferSize
+	for i := 0; i < count; i++ {
+		radio.send(atResponse(p, 0, nodeRecord(uint16(i), 0x0013A20040000000|uint64(i), "PUMP")...))
+	}
+	radio.send(atResponse(p, 0))
+
+	found := 0
+	for n := range nodes {
+		if n.Addr16 != uint16(found) {
+			t.Fatalf("Expected node %d, but got %+v", found, n)
+		}
+		found++
+	}
+	if found != count {
+		t.Fatalf("Expected %d nodes, but got %d", count, found)
+	}
+}
+
+func TestXBee_DiscoverNodes_NodeIdentifier(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		switch string(p[2:4]) {
+		case "NT":
+			return [][]byte{atResponse(p, 0, 0x00, 0x3C)}
+		case "ND":
+			if string(p[4:]) != "PUMP-2" {
+				t.Fatalf("Expected node identifier PUMP-2, but got %q", p[4:])
+			}
+			return [][]byte{atResponse(p, 0, nodeRecord(0x0002, 0x0013A20040000002, "PUMP-2")...)}
+		}
+		t.Fatalf("Unexpected command %s", p[2:4])
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	nodes, err := xbee.DiscoverNodes(ctx, DiscoveryOptions{NodeIdentifier: "PUMP-2"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	n := <-nodes
+	if n == nil || n.Addr64 != 0x0013A20040000002 {
+		t.Fatalf("Expected PUMP-2, but got %+v", n)
+	}
+	if _, ok := <-nodes; ok {
+		t.Fatal("Expected discovery to end after the node responded")
+	}
+}
@@ -0,0 +1,253 @@
+package endpoint
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Access attribute access
+type Access byte
+
+// Attribute access bits
+const (
+	Read   = Access(1 << 0)
+	Write  = Access(1 << 1)
+	Report = Access(1 << 2)
+)
+
+// Request a ZCL frame received on a cluster
+type Request struct {
+	// Src the sender, addressed from the receiving endpoint
+	Src     zcl.Address
+	Cluster uint16
+	Frame   *zcl.Frame
+}
+
+// CommandHandler answers a cluster specific command with a response command,
+// whose Command and Payload are sent, or nil and the status for a Default
+// Response
+type CommandHandler func(ctx context.Context, r *Request) (*zcl.Frame, zcl.Status)
+
+// WriteHandler accepts or rejects a write of a writable attribute before the
+// value is stored
+type WriteHandler func(src zcl.Address, a zcl.Attribute) zcl.Status
+
+// ReportHandler receives the attributes of a Report Attributes
+type ReportHandler func(src zcl.Address, attributes []zcl.Attribute)
+
+// attribute an attribute served by a cluster
+type attribute struct {
+	zcl.Attribute
+	access Access
+}
+
+// Cluster a cluster of a registered endpoint, serving its attributes and
+// passing commands and reports to its handlers
+type Cluster struct {
+	id uint16
+
+	mu         sync.Mutex
+	attributes map[uint16]*attribute
+	command    CommandHandler
+	write      WriteHandler
+	report     ReportHandler
+}
+
+func newCluster(id uint16) *Cluster {
+	return &Cluster{id: id, attributes: make(map[uint16]*attribute)}
+}
+
+// ID the cluster ID
+func (c *Cluster) ID() uint16 {
+	return c.id
+}
+
+// AddAttribute adds or replaces attribute a with access
+func (c *Cluster) AddAttribute(a zcl.Attribute, access Access) error {
+	if _, err := zcl.EncodeValue(a.Type, a.Value); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attributes[a.ID] = &attribute{Attribute: a, access: access}
+
+	return nil
+}
+
+// Set sets the value of attribute id
+func (c *Cluster) Set(id uint16, v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	a, ok := c.attributes[id]
+	if !ok {
+		return ErrAttribute
+	}
+
+	if _, err := zcl.EncodeValue(a.Type, v); err != nil {
+		return err
+	}
+	a.Value = v
+
+	return nil
+}
+
+// Get returns the value of attribute id
+func (c *Cluster) Get(id uint16) (interface{}, bool) {
+	a, ok := c.attribute(id)
+	return a.Value, ok
+}
+
+// HandleCommand sets the handler of cluster specific commands, commands are
+// otherwise answered as unsupported
+func (c *Cluster) HandleCommand(h CommandHandler) {
+	c.mu.Lock()
+	c.command = h
+	c.mu.Unlock()
+}
+
+// HandleWrite sets the handler approving writes
+func (c *Cluster) HandleWrite(h WriteHandler) {
+	c.mu.Lock()
+	c.write = h
+	c.mu.Unlock()
+}
+
+// HandleReport sets the handler of received attribute reports
+func (c *Cluster) HandleReport(h ReportHandler) {
+	c.mu.Lock()
+	c.report = h
+	c.mu.Unlock()
+}
+
+func (c *Cluster) attribute(id uint16) (zcl.Attribute, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	a, ok := c.attributes[id]
+	if !ok {
+		return zcl.Attribute{}, false
+	}
+
+	return a.Attribute, true
+}
+
+// read answers a Read Attributes
+func (c *Cluster) read(ids []uint16) []zcl.ReadRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	records := make([]zcl.ReadRecord, len(ids))
+	for i, id := range ids {
+		records[i].ID = id
+
+		a, ok := c.attributes[id]
+		switch {
+		case !ok:
+			records[i].Status = zcl.StatusUnsupportedAttribute
+		case a.access&Read == 0:
+			records[i].Status = zcl.StatusWriteOnly
+		default:
+			records[i].Type = a.Type
+			records[i].Value = a.Value
+		}
+	}
+
+	return records
+}
+
+// writeStatus status of writing a checked against the attribute's access and
+// type, without storing it, c.mu must be held
+func (c *Cluster) writeStatus(a zcl.Attribute) zcl.Status {
+	current, ok := c.attributes[a.ID]
+	switch {
+	case !ok:
+		return zcl.StatusUnsupportedAttribute
+	case current.access&Write == 0:
+		return zcl.StatusReadOnly
+	case current.Type != a.Type:
+		return zcl.StatusInvalidDataType
+	}
+
+	return zcl.StatusSuccess
+}
+
+// writeAll answers a Write Attributes, an undivided write stores nothing
+// unless every attribute can be written. The WriteHandler runs unlocked so
+// that it may Get and Set the cluster's attributes.
+func (c *Cluster) writeAll(src zcl.Address, attributes []zcl.Attribute, undivided bool) []zcl.WriteRecord {
+	records := make([]zcl.WriteRecord, len(attributes))
+
+	c.mu.Lock()
+	write := c.write
+	for i, a := range attributes {
+		records[i] = zcl.WriteRecord{ID: a.ID, Status: c.writeStatus(a)}
+	}
+	c.mu.Unlock()
+
+	failed := false
+	for i, a := range attributes {
+		if records[i].Status == zcl.StatusSuccess && write != nil {
+			records[i].Status = write(src, a)
+		}
+		failed = failed || records[i].Status != zcl.StatusSuccess
+	}
+
+	if undivided && failed {
+		return records
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, a := range attributes {
+		if records[i].Status != zcl.StatusSuccess {
+			continue
+		}
+
+		// the attribute may have been replaced while the handler ran
+		if records[i].Status = c.writeStatus(a); records[i].Status == zcl.StatusSuccess {
+			c.attributes[a.ID].Value = a.Value
+		}
+	}
+
+	return records
+}
+
+// discover answers a Discover Attributes
+func (c *Cluster) discover(start uint16, max byte) (bool, []zcl.AttributeInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]int, 0, len(c.attributes))
+	for id := range c.attributes {
+		if id >= start {
+			ids = append(ids, int(id))
+		}
+	}
+	sort.Ints(ids)
+
+	complete := len(ids) <= int(max)
+	if !complete {
+		ids = ids[:max]
+	}
+
+	info := make([]zcl.AttributeInfo, len(ids))
+	for i, id := range ids {
+		info[i] = zcl.AttributeInfo{ID: uint16(id), Type: c.attributes[uint16(id)].Type}
+	}
+
+	return complete, info
+}
+
+func (c *Cluster) handlers() (CommandHandler, ReportHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.command, c.report
+}
@@ -0,0 +1,257 @@
+// Package endpoint serves host side Zigbee endpoints, answering ZDO
+// descriptor requests and ZCL requests received as explicit frames.
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/internal/rxloop"
+	"github.com/pauleyj/gobee/zcl"
+	"github.com/pauleyj/gobee/zdo"
+)
+
+const (
+	// maxEndpoint highest application endpoint
+	maxEndpoint byte = 0xF0
+
+	// rxOptionBroadcast receive option, the frame was broadcast
+	rxOptionBroadcast byte = 0x02
+)
+
+var (
+	// ErrEndpoint endpoint is reserved or already registered
+	ErrEndpoint = errors.New("invalid endpoint")
+	// ErrCluster cluster is not on the endpoint
+	ErrCluster = errors.New("cluster not on endpoint")
+	// ErrAttribute attribute is not on the cluster
+	ErrAttribute = errors.New("attribute not on cluster")
+)
+
+// ErrorHandlerSetter interface for ErrorHandler setters
+type ErrorHandlerSetter interface {
+	SetErrorHandler(func(error))
+}
+
+// ErrorHandler helper option function to NewServer, receives failures to
+// answer requests
+func ErrorHandler(f func(error)) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(ErrorHandlerSetter); ok {
+			s.SetErrorHandler(f)
+		}
+	}
+}
+
+// Server answers ZDO Simple_Desc, Active_EP and Match_Desc requests and ZCL
+// requests for its registered endpoints. Receiving the ZDO requests requires
+// the local XBee's AO set to 3, ZCL requests AO set to 1 or 3.
+type Server struct {
+	xbee   *gobee.XBee
+	client *zcl.Client
+	errors func(error)
+
+	mu        sync.Mutex
+	endpoints map[byte]*Endpoint
+}
+
+// NewServer constructs a Server with no endpoints
+func NewServer(xbee *gobee.XBee, options ...func(interface{})) *Server {
+	s := &Server{
+		xbee:      xbee,
+		client:    zcl.New(xbee),
+		endpoints: make(map[byte]*Endpoint),
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(s)
+	}
+
+	return s
+}
+
+// SetErrorHandler satisfy ErrorHandlerSetter interface
+func (s *Server) SetErrorHandler(f func(error)) {
+	s.errors = f
+}
+
+// Register adds an endpoint described by d, with a cluster for each of its
+// input and output clusters
+func (s *Server) Register(d zdo.SimpleDescriptor) (*Endpoint, error) {
+	if d.Endpoint == zdo.Endpoint || d.Endpoint > maxEndpoint {
+		return nil, ErrEndpoint
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.endpoints[d.Endpoint]; ok {
+		return nil, ErrEndpoint
+	}
+
+	e := &Endpoint{descriptor: d, clusters: make(map[uint16]*Cluster)}
+	for _, ids := range [][]uint16{d.InputClusters, d.OutputClusters} {
+		for _, id := range ids {
+			if _, ok := e.clusters[id]; !ok {
+				e.clusters[id] = newCluster(id)
+			}
+		}
+	}
+	s.endpoints[d.Endpoint] = e
+
+	return e, nil
+}
+
+// Endpoint returns the registered endpoint, nil if not registered
+func (s *Server) Endpoint(endpoint byte) *Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.endpoints[endpoint]
+}
+
+// active registered endpoints in ascending order
+func (s *Server) active() []*Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoints := make([]*Endpoint, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		endpoints = append(endpoints, e)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].descriptor.Endpoint < endpoints[j].descriptor.Endpoint
+	})
+
+	return endpoints
+}
+
+// Serve answers requests until ctx is done
+func (s *Server) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return s.loop(ctx, s.subscribe(ctx))
+}
+
+// subscribe to the requests for the registered endpoints
+func (s *Server) subscribe(ctx context.Context) <-chan rx.Frame {
+	return s.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		e, ok := f.(*rx.ZBExplicit)
+		if !ok {
+			return false
+		}
+		if e.DstEP() == zdo.Endpoint {
+			return e.ProfileID() == zdo.Profile
+		}
+
+		return s.Endpoint(e.DstEP()) != nil
+	})
+}
+
+// loop answers requests until they are closed
+func (s *Server) loop(ctx context.Context, requests <-chan rx.Frame) error {
+	rxloop.Each(requests, func(f rx.Frame) func() {
+		e := f.(*rx.ZBExplicit)
+
+		return func() {
+			var err error
+			if e.DstEP() == zdo.Endpoint {
+				err = s.serveZDO(ctx, e)
+			} else {
+				err = s.serveZCL(ctx, e)
+			}
+
+			if err != nil && s.errors != nil && ctx.Err() == nil {
+				s.errors(err)
+			}
+		}
+	})
+
+	return ctx.Err()
+}
+
+// Report sends a Report Attributes of the current values of ids, on the
+// cluster of the local endpoint dst.SrcEndpoint, to dst
+func (s *Server) Report(ctx context.Context, dst zcl.Address, cluster uint16, ids ...uint16) error {
+	e := s.Endpoint(dst.SrcEndpoint)
+	if e == nil {
+		return ErrEndpoint
+	}
+
+	c := e.Cluster(cluster)
+	if c == nil {
+		return ErrCluster
+	}
+
+	attributes := make([]zcl.Attribute, 0, len(ids))
+	for _, id := range ids {
+		a, ok := c.attribute(id)
+		if !ok {
+			return ErrAttribute
+		}
+		attributes = append(attributes, a)
+	}
+
+	payload, err := zcl.EncodeAttributes(attributes...)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Send(ctx, dst, cluster, &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   zcl.FrameGeneral,
+			Direction:              zcl.ServerToClient,
+			DisableDefaultResponse: true,
+			Seq:                    s.client.NextSeq(),
+			Command:                zcl.CommandReportAttributes,
+		},
+		Payload: payload,
+	})
+}
+
+// Endpoint a registered endpoint
+type Endpoint struct {
+	descriptor zdo.SimpleDescriptor
+	clusters   map[uint16]*Cluster
+}
+
+// Descriptor the endpoint's simple descriptor
+func (e *Endpoint) Descriptor() zdo.SimpleDescriptor {
+	return e.descriptor
+}
+
+// Cluster returns the endpoint's cluster, nil if not on the endpoint
+func (e *Endpoint) Cluster(id uint16) *Cluster {
+	return e.clusters[id]
+}
+
+// matches the endpoint has profile, or profile is the wildcard, and any of
+// the input or output clusters
+func (e *Endpoint) matches(profile uint16, in, out []uint16) bool {
+	if profile != e.descriptor.ProfileID && profile != 0xFFFF {
+		return false
+	}
+
+	return intersects(in, e.descriptor.InputClusters) || intersects(out, e.descriptor.OutputClusters)
+}
+
+func intersects(a, b []uint16) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
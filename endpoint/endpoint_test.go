@@ -0,0 +1,286 @@
+package endpoint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/internal/radiotest"
+	"github.com/pauleyj/gobee/zcl"
+	"github.com/pauleyj/gobee/zdo"
+)
+
+const (
+	remoteAddr64 uint64 = 0x0013A20040522BAA
+	remoteAddr16 uint16 = 0x1234
+)
+
+var thermostat = zdo.SimpleDescriptor{
+	Endpoint:       0x01,
+	ProfileID:      zcl.ProfileHomeAutomation,
+	DeviceID:       0x0301,
+	InputClusters:  []uint16{0x0000, 0x0201},
+	OutputClusters: []uint16{0x0402},
+}
+
+// serve starts a server with the thermostat endpoint on a fake radio,
+// returning the radio and the explicit frames the server transmits
+func serve(t *testing.T) (*Server, *radiotest.Radio, <-chan radiotest.Explicit) {
+	sent := make(chan radiotest.Explicit, 16)
+	xbee, radio := radiotest.New(func(p []byte) [][]byte {
+		if p[0] == 0x08 && string(p[2:4]) == "MY" {
+			return [][]byte{{0x88, p[1], p[2], p[3], 0x00, 0x56, 0x78}}
+		}
+
+		e, ok := radiotest.ParseExplicit(p)
+		if !ok {
+			t.Fatalf("Expected explicit frame, but got % x", p)
+		}
+		e.Data = append([]byte(nil), e.Data...)
+		sent <- e
+
+		return [][]byte{radiotest.TXStatus(e.ID, 0)}
+	})
+
+	s := NewServer(xbee)
+	e, err := s.Register(thermostat)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	err = e.Cluster(0x0201).AddAttribute(zcl.Attribute{ID: 0x0000, Type: zcl.TypeInt16, Value: int16(2050)}, Read|Report)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	err = e.Cluster(0x0201).AddAttribute(zcl.Attribute{ID: 0x0012, Type: zcl.TypeInt16, Value: int16(2000)}, Read|Write)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	requests := s.subscribe(ctx)
+	go s.loop(ctx, requests)
+
+	return s, radio, sent
+}
+
+// receive feeds a request from the remote endpoint and waits for the reply
+func receive(t *testing.T, radio *radiotest.Radio, sent <-chan radiotest.Explicit, request radiotest.Explicit) radiotest.Explicit {
+	request.Addr64 = remoteAddr64
+	request.Addr16 = remoteAddr16
+	radio.Send(request.Received())
+
+	select {
+	case e := <-sent:
+		if e.Addr64 != remoteAddr64 || e.DstEndpoint != request.SrcEndpoint || e.SrcEndpoint != request.DstEndpoint {
+			t.Fatalf("Expected reply to the requesting endpoint, but got %+v", e)
+		}
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("Expected reply")
+	}
+
+	return radiotest.Explicit{}
+}
+
+func zdoRequest(cluster uint16, payload ...byte) radiotest.Explicit {
+	return radiotest.Explicit{ClusterID: cluster, ProfileID: zdo.Profile, Data: append([]byte{0x42}, payload...)}
+}
+
+func zclRequest(cluster uint16, f *zcl.Frame) radiotest.Explicit {
+	return radiotest.Explicit{SrcEndpoint: 0x01, DstEndpoint: 0x01, ClusterID: cluster, ProfileID: zcl.ProfileHomeAutomation, Data: f.Bytes()}
+}
+
+func TestServer_Register(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(nil)
+
+	var tests = []struct {
+		name     string
+		endpoint byte
+		err      error
+	}{
+		{"Valid", 0x01, nil},
+		{"Duplicate", 0x01, ErrEndpoint},
+		{"ZDO", 0x00, ErrEndpoint},
+		{"Reserved", 0xF1, ErrEndpoint},
+	}
+
+	for _, tt := range tests {
+		_, err := s.Register(zdo.SimpleDescriptor{Endpoint: tt.endpoint})
+		if err != tt.err {
+			t.Fatalf("%s: Expected error %v, but got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestServer_ZDO(t *testing.T) {
+	_, radio, sent := serve(t)
+
+	e := receive(t, radio, sent, zdoRequest(zdo.ActiveEPReq, 0x00, 0x00))
+	if expected := []byte{0x42, 0x00, 0x00, 0x00, 0x01, 0x01}; e.ClusterID != 0x8005 || !reflect.DeepEqual(e.Data, expected) {
+		t.Fatalf("Expected Active_EP_rsp % x, but got 0x%04x % x", expected, e.ClusterID, e.Data)
+	}
+
+	e = receive(t, radio, sent, zdoRequest(zdo.SimpleDescReq, 0x00, 0x00, 0x01))
+	if e.ClusterID != 0x8004 || len(e.Data) < 5 || e.Data[1] != byte(zdo.StatusSuccess) {
+		t.Fatalf("Expected Simple_Desc_rsp, but got 0x%04x % x", e.ClusterID, e.Data)
+	}
+	d, err := zdo.ParseSimpleDescriptor(e.Data[5:])
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(*d, thermostat) {
+		t.Fatalf("Expected %+v, but got %+v", thermostat, d)
+	}
+
+	e = receive(t, radio, sent, zdoRequest(zdo.SimpleDescReq, 0x00, 0x00, 0x02))
+	if expected := []byte{0x42, byte(zdo.StatusNotActive), 0x00, 0x00, 0x00}; !reflect.DeepEqual(e.Data, expected) {
+		t.Fatalf("Expected % x, but got % x", expected, e.Data)
+	}
+
+	e = receive(t, radio, sent, zdoRequest(zdo.MatchDescReq, 0x00, 0x00, 0x04, 0x01, 0x01, 0x01, 0x02, 0x00))
+	if expected := []byte{0x42, 0x00, 0x00, 0x00, 0x01, 0x01}; e.ClusterID != 0x8006 || !reflect.DeepEqual(e.Data, expected) {
+		t.Fatalf("Expected Match_Desc_rsp % x, but got 0x%04x % x", expected, e.ClusterID, e.Data)
+	}
+
+	// broadcast for every receiver on when idle node, answered with the local address
+	request := zdoRequest(zdo.MatchDescReq, 0xFD, 0xFF, 0x04, 0x01, 0x01, 0x01, 0x02, 0x00)
+	request.Options = rxOptionBroadcast
+	e = receive(t, radio, sent, request)
+	if expected := []byte{0x42, 0x00, 0x78, 0x56, 0x01, 0x01}; e.ClusterID != 0x8006 || !reflect.DeepEqual(e.Data, expected) {
+		t.Fatalf("Expected Match_Desc_rsp % x, but got 0x%04x % x", expected, e.ClusterID, e.Data)
+	}
+}
+
+func TestServer_ZCL(t *testing.T) {
+	s, radio, sent := serve(t)
+
+	response := func(e radiotest.Explicit) *zcl.Frame {
+		f, err := zcl.Parse(e.Data)
+		if err != nil {
+			t.Fatalf("Expected ZCL frame, but got %v", err)
+		}
+		if f.Seq != 0x07 || f.Direction != zcl.ServerToClient {
+			t.Fatalf("Expected response to sequence 7, but got %+v", f.Header)
+		}
+		return f
+	}
+
+	read := &zcl.Frame{
+		Header:  zcl.Header{Seq: 0x07, Command: zcl.CommandReadAttributes},
+		Payload: zcl.EncodeReadAttributes(0x0000, 0x0001),
+	}
+	f := response(receive(t, radio, sent, zclRequest(0x0201, read)))
+	records, _ := zcl.DecodeReadAttributesResponse(f.Payload)
+	expected := []zcl.ReadRecord{
+		{ID: 0x0000, Status: zcl.StatusSuccess, Type: zcl.TypeInt16, Value: int16(2050)},
+		{ID: 0x0001, Status: zcl.StatusUnsupportedAttribute},
+	}
+	if f.Command != zcl.CommandReadAttributesResponse || !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, records)
+	}
+
+	payload, _ := zcl.EncodeAttributes(
+		zcl.Attribute{ID: 0x0000, Type: zcl.TypeInt16, Value: int16(0)},
+		zcl.Attribute{ID: 0x0012, Type: zcl.TypeInt16, Value: int16(2200)})
+	write := &zcl.Frame{Header: zcl.Header{Seq: 0x07, Command: zcl.CommandWriteAttributes}, Payload: payload}
+	f = response(receive(t, radio, sent, zclRequest(0x0201, write)))
+	written, _ := zcl.DecodeWriteAttributesResponse(f.Payload)
+	if expected := []zcl.WriteRecord{{Status: zcl.StatusReadOnly, ID: 0x0000}}; !reflect.DeepEqual(written, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, written)
+	}
+	if v, _ := s.Endpoint(0x01).Cluster(0x0201).Get(0x0012); v != int16(2200) {
+		t.Fatalf("Expected written value 2200, but got %v", v)
+	}
+
+	command := &zcl.Frame{Header: zcl.Header{Type: zcl.FrameCluster, Seq: 0x07, Command: 0x00}}
+	f = response(receive(t, radio, sent, zclRequest(0x0006, command)))
+	if c, status, _ := zcl.DecodeDefaultResponse(f.Payload); f.Command != zcl.CommandDefaultResponse || c != 0x00 || status != zcl.StatusUnsupportedCluster {
+		t.Fatalf("Expected unsupported cluster, but got %+v % x", f.Header, f.Payload)
+	}
+}
+
+func TestServer_Command(t *testing.T) {
+	s, radio, sent := serve(t)
+
+	s.Endpoint(0x01).Cluster(0x0201).HandleCommand(func(ctx context.Context, r *Request) (*zcl.Frame, zcl.Status) {
+		if r.Frame.Command != 0x00 || r.Src.Addr64 != remoteAddr64 {
+			return nil, zcl.StatusInvalidField
+		}
+		return &zcl.Frame{Header: zcl.Header{Command: 0x00}, Payload: []byte{0x01}}, zcl.StatusSuccess
+	})
+
+	command := &zcl.Frame{Header: zcl.Header{Type: zcl.FrameCluster, Seq: 0x07, Command: 0x00}}
+	e := receive(t, radio, sent, zclRequest(0x0201, command))
+	f, _ := zcl.Parse(e.Data)
+	if f.Type != zcl.FrameCluster || f.Seq != 0x07 || !reflect.DeepEqual(f.Payload, []byte{0x01}) {
+		t.Fatalf("Expected cluster response, but got %+v", f)
+	}
+}
+
+func TestServer_Report(t *testing.T) {
+	s, radio, sent := serve(t)
+
+	reports := make(chan []zcl.Attribute, 1)
+	s.Endpoint(0x01).Cluster(0x0402).HandleReport(func(src zcl.Address, attributes []zcl.Attribute) {
+		reports <- attributes
+	})
+
+	payload, _ := zcl.EncodeAttributes(zcl.Attribute{ID: 0x0000, Type: zcl.TypeInt16, Value: int16(1950)})
+	report := &zcl.Frame{Header: zcl.Header{Seq: 0x07, Direction: zcl.ServerToClient, Command: zcl.CommandReportAttributes}, Payload: payload}
+	e := receive(t, radio, sent, zclRequest(0x0402, report))
+
+	f, _ := zcl.Parse(e.Data)
+	if f.Command != zcl.CommandDefaultResponse || f.Direction != zcl.ClientToServer {
+		t.Fatalf("Expected Default Response, but got %+v", f.Header)
+	}
+	if attributes := <-reports; len(attributes) != 1 || attributes[0].Value != int16(1950) {
+		t.Fatalf("Expected report of 1950, but got %+v", attributes)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dst := zcl.Address{Addr64: remoteAddr64, Addr16: remoteAddr16, SrcEndpoint: 0x01, DstEndpoint: 0x01, ProfileID: zcl.ProfileHomeAutomation}
+	if err := s.Report(ctx, dst, 0x0201, 0x0000); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	e = <-sent
+	f, _ = zcl.Parse(e.Data)
+	attributes, _ := zcl.DecodeAttributes(f.Payload)
+	if f.Command != zcl.CommandReportAttributes || len(attributes) != 1 || attributes[0].Value != int16(2050) {
+		t.Fatalf("Expected report of 2050, but got %+v %+v", f.Header, attributes)
+	}
+}
+
+func TestCluster_Write_Handler(t *testing.T) {
+	c := newCluster(0x0201)
+	c.AddAttribute(zcl.Attribute{ID: 0x0012, Type: zcl.TypeInt16, Value: int16(2000)}, Read|Write)
+	c.AddAttribute(zcl.Attribute{ID: 0x0014, Type: zcl.TypeInt16, Value: int16(1600)}, Read|Write)
+
+	// the handler reads the cluster, keeping heating above cooling
+	c.HandleWrite(func(src zcl.Address, a zcl.Attribute) zcl.Status {
+		cooling, _ := c.Get(0x0012)
+		if a.ID == 0x0014 && a.Value.(int16) >= cooling.(int16) {
+			return zcl.StatusInvalidValue
+		}
+		return zcl.StatusSuccess
+	})
+
+	records := c.writeAll(zcl.Address{}, []zcl.Attribute{
+		{ID: 0x0012, Type: zcl.TypeInt16, Value: int16(2200)},
+		{ID: 0x0014, Type: zcl.TypeInt16, Value: int16(2100)},
+	}, true)
+
+	expected := []zcl.WriteRecord{{ID: 0x0012, Status: zcl.StatusSuccess}, {ID: 0x0014, Status: zcl.StatusInvalidValue}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, records)
+	}
+	if v, _ := c.Get(0x0012); v != int16(2000) {
+		t.Fatalf("Expected undivided write to store nothing, but got %v", v)
+	}
+}
@@ -0,0 +1,162 @@
+package endpoint
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// serveZCL answers a ZCL frame received on a registered endpoint
+func (s *Server) serveZCL(ctx context.Context, f *rx.ZBExplicit) error {
+	e := s.Endpoint(f.DstEP())
+	if e == nil || f.ProfileID() != e.descriptor.ProfileID {
+		return nil
+	}
+
+	frame, err := zcl.Parse(f.Data())
+	if err != nil {
+		return nil
+	}
+
+	r := &Request{
+		Src: zcl.Address{
+			Addr64:      f.Addr64(),
+			Addr16:      f.Addr16(),
+			SrcEndpoint: f.DstEP(),
+			DstEndpoint: f.SrcEP(),
+			ProfileID:   f.ProfileID(),
+		},
+		Cluster: f.ClusterID(),
+		Frame:   frame,
+	}
+	broadcast := f.Options()&rxOptionBroadcast != 0
+
+	c := e.Cluster(r.Cluster)
+	if c == nil {
+		if frame.Direction == zcl.ServerToClient {
+			return nil
+		}
+
+		return s.defaultResponse(ctx, r, zcl.StatusUnsupportedCluster, broadcast)
+	}
+
+	if frame.Type == zcl.FrameCluster || frame.ManufacturerSpecific {
+		return s.serveCommand(ctx, c, r, broadcast)
+	}
+
+	switch frame.Command {
+	case zcl.CommandReadAttributes:
+		ids, err := zcl.DecodeReadAttributes(frame.Payload)
+		if err != nil {
+			return s.defaultResponse(ctx, r, zcl.StatusMalformedCommand, broadcast)
+		}
+
+		payload, err := zcl.EncodeReadAttributesResponse(c.read(ids)...)
+		if err != nil {
+			return err
+		}
+
+		return s.respond(ctx, r, zcl.CommandReadAttributesResponse, payload)
+	case zcl.CommandWriteAttributes, zcl.CommandWriteAttributesUndivided, zcl.CommandWriteAttributesNoResponse:
+		attributes, err := zcl.DecodeAttributes(frame.Payload)
+		if err != nil {
+			return s.defaultResponse(ctx, r, zcl.StatusMalformedCommand, broadcast)
+		}
+
+		records := c.writeAll(r.Src, attributes, frame.Command == zcl.CommandWriteAttributesUndivided)
+		if frame.Command == zcl.CommandWriteAttributesNoResponse {
+			return nil
+		}
+
+		return s.respond(ctx, r, zcl.CommandWriteAttributesResponse, zcl.EncodeWriteAttributesResponse(records...))
+	case zcl.CommandDiscoverAttributes:
+		start, max, err := zcl.DecodeDiscoverAttributes(frame.Payload)
+		if err != nil {
+			return s.defaultResponse(ctx, r, zcl.StatusMalformedCommand, broadcast)
+		}
+
+		complete, info := c.discover(start, max)
+
+		return s.respond(ctx, r, zcl.CommandDiscoverAttributesResponse, zcl.EncodeDiscoverAttributesResponse(complete, info...))
+	case zcl.CommandReportAttributes:
+		attributes, err := zcl.DecodeAttributes(frame.Payload)
+		if err != nil {
+			return s.defaultResponse(ctx, r, zcl.StatusMalformedCommand, broadcast)
+		}
+
+		if _, report := c.handlers(); report != nil {
+			report(r.Src, attributes)
+		}
+
+		return s.defaultResponse(ctx, r, zcl.StatusSuccess, broadcast)
+	}
+
+	// responses to requests of this endpoint's own clients are not answered
+	if frame.Direction == zcl.ServerToClient {
+		return nil
+	}
+
+	return s.defaultResponse(ctx, r, zcl.StatusUnsupportedGeneralCommand, broadcast)
+}
+
+// serveCommand passes a cluster specific or manufacturer specific command to
+// the cluster's command handler
+func (s *Server) serveCommand(ctx context.Context, c *Cluster, r *Request, broadcast bool) error {
+	command, _ := c.handlers()
+	if command == nil {
+		if r.Frame.Direction == zcl.ServerToClient {
+			return nil
+		}
+
+		return s.defaultResponse(ctx, r, zcl.StatusUnsupportedClusterCommand, broadcast)
+	}
+
+	response, status := command(ctx, r)
+	if response != nil {
+		return s.reply(ctx, r, zcl.FrameCluster, response.Command, response.Payload)
+	}
+
+	return s.defaultResponse(ctx, r, status, broadcast)
+}
+
+// respond sends a general command response to r
+func (s *Server) respond(ctx context.Context, r *Request, command byte, payload []byte) error {
+	return s.reply(ctx, r, zcl.FrameGeneral, command, payload)
+}
+
+// defaultResponse sends a Default Response to r unless r is itself a Default
+// Response, was broadcast, or succeeded with default responses disabled
+func (s *Server) defaultResponse(ctx context.Context, r *Request, status zcl.Status, broadcast bool) error {
+	f := r.Frame
+	if f.Type == zcl.FrameGeneral && f.Command == zcl.CommandDefaultResponse {
+		return nil
+	}
+	if broadcast || status == zcl.StatusSuccess && f.DisableDefaultResponse {
+		return nil
+	}
+
+	return s.respond(ctx, r, zcl.CommandDefaultResponse, zcl.EncodeDefaultResponse(f.Command, status))
+}
+
+// reply sends a response frame to r, with its sequence number, in the
+// opposite direction
+func (s *Server) reply(ctx context.Context, r *Request, t zcl.FrameType, command byte, payload []byte) error {
+	direction := zcl.ServerToClient
+	if r.Frame.Direction == zcl.ServerToClient {
+		direction = zcl.ClientToServer
+	}
+
+	return s.client.Send(ctx, r.Src, r.Cluster, &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   t,
+			Direction:              direction,
+			DisableDefaultResponse: true,
+			ManufacturerSpecific:   r.Frame.ManufacturerSpecific,
+			ManufacturerCode:       r.Frame.ManufacturerCode,
+			Seq:                    r.Frame.Seq,
+			Command:                command,
+		},
+		Payload: payload,
+	})
+}
@@ -0,0 +1,136 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+	"github.com/pauleyj/gobee/zdo"
+)
+
+// serveZDO answers the ZDO descriptor requests passed through by the XBee
+func (s *Server) serveZDO(ctx context.Context, f *rx.ZBExplicit) error {
+	p := f.Data()
+	if len(p) < 3 {
+		return nil
+	}
+
+	seq, addr16, payload := p[0], p[1:3], p[3:]
+
+	var response []byte
+	switch f.ClusterID() {
+	case zdo.ActiveEPReq:
+		response = s.activeEndpoints()
+	case zdo.SimpleDescReq:
+		if len(payload) < 1 {
+			return nil
+		}
+		response = s.simpleDescriptor(payload[0])
+	case zdo.MatchDescReq:
+		response = s.matchDescriptor(payload)
+		// broadcasts are only answered by matching nodes
+		if response == nil || response[1] == 0 && f.Options()&rxOptionBroadcast != 0 {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	// responses carry the status then the NWK address of interest, the local
+	// address when the request was for a broadcast address
+	if isBroadcast(binary.LittleEndian.Uint16(addr16)) {
+		my, err := s.xbee.Get(ctx, at.NetworkAddr)
+		if err != nil {
+			return err
+		}
+		addr16 = make([]byte, 2)
+		binary.LittleEndian.PutUint16(addr16, my.(uint16))
+	}
+	data := append([]byte{seq, response[0]}, addr16...)
+
+	return s.xbee.SendExplicit(ctx,
+		tx.Addr64(f.Addr64()),
+		tx.Addr16(f.Addr16()),
+		tx.SrcEP(zdo.Endpoint),
+		tx.DstEP(zdo.Endpoint),
+		tx.ClusterID(f.ClusterID()|zdo.ResponseCluster),
+		tx.ProfileID(zdo.Profile),
+		tx.Data(append(data, response[1:]...)))
+}
+
+// isBroadcast reports whether addr16 is a broadcast address
+func isBroadcast(addr16 uint16) bool {
+	return addr16 >= 0xFFF8 && addr16 != 0xFFFE
+}
+
+// activeEndpoints Active_EP_rsp status and endpoint list
+func (s *Server) activeEndpoints() []byte {
+	endpoints := s.active()
+
+	p := []byte{byte(zdo.StatusSuccess), byte(len(endpoints))}
+	for _, e := range endpoints {
+		p = append(p, e.descriptor.Endpoint)
+	}
+
+	return p
+}
+
+// simpleDescriptor Simple_Desc_rsp status and length prefixed descriptor
+func (s *Server) simpleDescriptor(endpoint byte) []byte {
+	if endpoint == zdo.Endpoint || endpoint > maxEndpoint {
+		return []byte{byte(zdo.StatusInvalidEP), 0}
+	}
+
+	e := s.Endpoint(endpoint)
+	if e == nil {
+		return []byte{byte(zdo.StatusNotActive), 0}
+	}
+
+	d := e.descriptor.Bytes()
+
+	return append([]byte{byte(zdo.StatusSuccess), byte(len(d))}, d...)
+}
+
+// matchDescriptor Match_Desc_rsp status and matching endpoint list, nil if
+// the request is malformed
+func (s *Server) matchDescriptor(p []byte) []byte {
+	if len(p) < 2 {
+		return nil
+	}
+
+	profile := binary.LittleEndian.Uint16(p)
+
+	in, p, ok := clusters(p[2:])
+	if !ok {
+		return nil
+	}
+	out, _, ok := clusters(p)
+	if !ok {
+		return nil
+	}
+
+	var matched []byte
+	for _, e := range s.active() {
+		if e.matches(profile, in, out) {
+			matched = append(matched, e.descriptor.Endpoint)
+		}
+	}
+
+	return append([]byte{byte(zdo.StatusSuccess), byte(len(matched))}, matched...)
+}
+
+// clusters decodes a count prefixed cluster list, returning the remaining bytes
+func clusters(p []byte) ([]uint16, []byte, bool) {
+	if len(p) < 1 || len(p) < 1+2*int(p[0]) {
+		return nil, nil, false
+	}
+
+	ids := make([]uint16, p[0])
+	for i := range ids {
+		ids[i] = binary.LittleEndian.Uint16(p[1+2*i:])
+	}
+
+	return ids, p[1+2*len(ids):], true
+}
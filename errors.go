@@ -0,0 +1,54 @@
+package gobee
+
+import "fmt"
+
+// DeliveryError a transmitted frame was not delivered, Status is the delivery
+// status reported by the XBee in the transmit status frame
+type DeliveryError struct {
+	Status byte
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivery failed (%#0.2x)", e.Status)
+}
+
+// ATError an AT command response reported a status other than OK
+type ATError struct {
+	Command [2]byte
+	Status  byte
+}
+
+func (e *ATError) Error() string {
+	switch e.Status {
+	case 1:
+		return fmt.Sprintf("AT command %s error", e.Command[:])
+	case 2:
+		return fmt.Sprintf("AT command %s invalid command", e.Command[:])
+	case 3:
+		return fmt.Sprintf("AT command %s invalid parameter", e.Command[:])
+	case 4:
+		return fmt.Sprintf("AT command %s transmission failure", e.Command[:])
+	default:
+		return fmt.Sprintf("AT command %s unknown status (%#0.2x)", e.Command[:], e.Status)
+	}
+}
+
+// TransactionError a configuration transaction failed and was rolled back,
+// Rollback holds the error restoring the previous values, if any
+type TransactionError struct {
+	Err      error
+	Rollback error
+}
+
+func (e *TransactionError) Error() string {
+	if e.Rollback != nil {
+		return fmt.Sprintf("configuration transaction failed: %v, rollback failed: %v", e.Err, e.Rollback)
+	}
+
+	return fmt.Sprintf("configuration transaction failed: %v, rolled back", e.Err)
+}
+
+// Unwrap returns the error that failed the transaction
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
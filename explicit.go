@@ -0,0 +1,31 @@
+package gobee
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// SendExplicit transmits an explicit addressing frame built from options,
+// given its own frame ID, and waits for the transmit status. Receiving the
+// explicit frames sent in reply requires the local XBee's AO set to 1.
+func (x *XBee) SendExplicit(ctx context.Context, options ...func(interface{})) error {
+	id := x.NextFrameID()
+
+	f, err := x.Request(ctx,
+		tx.NewZBExplicit(append(options[:len(options):len(options)], tx.FrameID(id))...),
+		func(f rx.Frame) bool {
+			s, ok := f.(*rx.TXStatus)
+			return ok && s.ID() == id
+		})
+	if err != nil {
+		return err
+	}
+
+	if status := f.(*rx.TXStatus).Delivery(); status != 0 {
+		return &DeliveryError{Status: status}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+func TestXBee_SendExplicit(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if p[0] != 0x11 || p[12] != 0x00 || p[13] != 0x00 || p[14] != 0x00 || p[15] != 0x05 {
+			t.Fatalf("Expected explicit frame to ZDO cluster 0x0005, but got % x", p)
+		}
+		return [][]byte{txStatus(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := xbee.SendExplicit(ctx, tx.Addr64(0x0013A20040522BAA), tx.ClusterID(0x0005), tx.Data([]byte{0x01, 0x34, 0x12}))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+}
+
+func TestXBee_Subscribe(t *testing.T) {
+	xbee, radio := newRadio(func(p []byte) [][]byte {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	frames := xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		_, ok := f.(*rx.IOSample)
+		return ok
+	})
+
+	radio.send(
+		atResponse([]byte{0x08, 0x01, 'N', 'I'}, 0),
+		ioSample(0x0013A20040522BAA, true, 0x10),
+		ioSample(0x0013A20040522BAA, false, 0x20))
+
+	for i := 0; i < 2; i++ {
+		if _, ok := (<-frames).(*rx.IOSample); !ok {
+			t.Fatalf("Expected IO sample frame %d", i)
+		}
+	}
+
+	cancel()
+	if _, ok := <-frames; ok {
+		t.Fatalf("Expected frames closed")
+	}
+}
+
+func TestXBee_Subscribe_Full(t *testing.T) {
+	xbee, radio := newRadio(func(p []byte) [][]byte {
+		return [][]byte{atResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// never read, its buffers fill
+	xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		_, ok := f.(*rx.IOSample)
+		return ok
+	})
+
+	for i := 0; i < 4*listenerBufferSize; i++ {
+		radio.send(ioSample(0x0013A20040522BAA, true, 0x10))
+	}
+
+	if err := xbee.Execute(ctx, at.SoftwareReset); err != nil {
+		t.Fatalf("Expected requests to be answered, but got %v", err)
+	}
+}
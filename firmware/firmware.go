@@ -0,0 +1,254 @@
+// Package firmware updates the local XBee 3's firmware over its serial port,
+// invoking the bootloader and transferring a .gbl image with XMODEM-CRC.
+package firmware
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+const (
+	// DefaultBlockSize XMODEM block size, accepted by every XMODEM receiver
+	DefaultBlockSize = BlockSize128
+	// DefaultRetries times a rejected block is resent
+	DefaultRetries = 10
+
+	// gblHeaderTag Gecko bootloader image header tag
+	gblHeaderTag uint32 = 0x03A617EB
+
+	// modemStatusHardwareReset modem status sent once the firmware is running
+	modemStatusHardwareReset byte = 0x00
+
+	// bootloader menu prompt and choices, a carriage return shows the menu
+	menuShow     = '\r'
+	menuPrompt   = "BL >"
+	menuUpload   = '1'
+	menuRun      = '2'
+	uploadBegin  = "begin upload"
+	uploadDone   = "Serial upload complete"
+	uploadFailed = "Serial upload aborted"
+)
+
+var (
+	// ErrImage not a Gecko bootloader (.gbl) image
+	ErrImage = errors.New("invalid GBL image")
+	// ErrUpload the bootloader rejected the image
+	ErrUpload = errors.New("bootloader rejected image")
+	// ErrBlockSize block size is neither 128 nor 1024 bytes
+	ErrBlockSize = errors.New("invalid XMODEM block size")
+	// ErrVersion the new firmware's VR is not a firmware version
+	ErrVersion = errors.New("invalid firmware version")
+)
+
+// BlockSizeSetter interface for BlockSize setters
+type BlockSizeSetter interface {
+	SetBlockSize(int)
+}
+
+// BlockSize helper option function to NewUpdater, the XMODEM block size,
+// BlockSize128 or BlockSize1K
+func BlockSize(size int) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(BlockSizeSetter); ok {
+			s.SetBlockSize(size)
+		}
+	}
+}
+
+// RetriesSetter interface for Retries setters
+type RetriesSetter interface {
+	SetRetries(int)
+}
+
+// Retries helper option function to NewUpdater, times a rejected block is resent
+func Retries(retries int) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(RetriesSetter); ok {
+			s.SetRetries(retries)
+		}
+	}
+}
+
+// ValidateImage checks p is a Gecko bootloader image
+func ValidateImage(p []byte) error {
+	if len(p) < 4 || binary.LittleEndian.Uint32(p) != gblHeaderTag {
+		return ErrImage
+	}
+
+	return nil
+}
+
+// OpenImage reads a Gecko bootloader image
+func OpenImage(name string) ([]byte, error) {
+	p, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, ValidateImage(p)
+}
+
+// Updater updates the firmware of the local XBee whose API frames are
+// transmitted on rw. While Update runs it reads rw itself, feeding API
+// frames to the XBee, so nothing else may read rw. Reads from rw should time
+// out so that Update observes its context. The bootloader runs at 115200
+// baud, which the port must use.
+type Updater struct {
+	xbee      *gobee.XBee
+	port      *port
+	blockSize int
+	retries   int
+}
+
+// NewUpdater constructs an Updater
+func NewUpdater(xbee *gobee.XBee, rw io.ReadWriter, options ...func(interface{})) *Updater {
+	u := &Updater{
+		xbee:      xbee,
+		port:      &port{rw: rw},
+		blockSize: DefaultBlockSize,
+		retries:   DefaultRetries,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(u)
+	}
+
+	return u
+}
+
+// SetBlockSize satisfy BlockSizeSetter interface
+func (u *Updater) SetBlockSize(size int) {
+	u.blockSize = size
+}
+
+// SetRetries satisfy RetriesSetter interface
+func (u *Updater) SetRetries(retries int) {
+	u.retries = retries
+}
+
+// Update invokes the bootloader with %P, uploads image, runs the new
+// firmware and returns its version, read with VR once the new firmware
+// reports a hardware reset with a Modem Status frame
+func (u *Updater) Update(ctx context.Context, image []byte) (uint16, error) {
+	if u.blockSize != BlockSize128 && u.blockSize != BlockSize1K {
+		return 0, ErrBlockSize
+	}
+	if err := ValidateImage(image); err != nil {
+		return 0, err
+	}
+
+	err := u.api(ctx, func(ctx context.Context) error {
+		return u.xbee.Execute(ctx, at.InvokeBootloader)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := u.port.write(menuShow); err != nil {
+		return 0, err
+	}
+	if _, err := u.port.expect(ctx, menuPrompt); err != nil {
+		return 0, err
+	}
+	if err := u.port.write(menuUpload); err != nil {
+		return 0, err
+	}
+	if _, err := u.port.expect(ctx, uploadBegin); err != nil {
+		return 0, err
+	}
+
+	if err := u.port.send(ctx, image, u.blockSize, u.retries); err != nil {
+		// abort the receiver, which may still be waiting for blocks
+		_ = u.port.write(can, can, can)
+		return 0, err
+	}
+
+	result, err := u.port.expect(ctx, uploadDone, uploadFailed)
+	if err != nil {
+		return 0, err
+	}
+	if result != 0 {
+		return 0, ErrUpload
+	}
+
+	if _, err := u.port.expect(ctx, menuPrompt); err != nil {
+		return 0, err
+	}
+
+	started, cancel := context.WithCancel(ctx)
+	defer cancel()
+	reset := u.xbee.Subscribe(started, func(f rx.Frame) bool {
+		s, ok := f.(*rx.ModemStatus)
+		return ok && s.Status() == modemStatusHardwareReset
+	})
+
+	if err := u.port.write(menuRun); err != nil {
+		return 0, err
+	}
+
+	var version uint16
+	err = u.api(ctx, func(ctx context.Context) error {
+		if _, ok := <-reset; !ok {
+			return ctx.Err()
+		}
+
+		v, err := u.xbee.Get(ctx, at.FirmwareVersion)
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		if version, ok = v.(uint16); !ok {
+			return ErrVersion
+		}
+
+		return nil
+	})
+
+	return version, err
+}
+
+// api runs request against the XBee in API mode, feeding it the bytes read
+// until request completes. The byte read once it has completed is kept for
+// the bootloader.
+func (u *Updater) api(ctx context.Context, request func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	read, stop := context.WithCancel(ctx)
+	defer stop()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- request(ctx)
+		stop()
+	}()
+
+	for {
+		b, rerr := u.port.readByte(read)
+		select {
+		case err := <-done:
+			if rerr == nil {
+				u.port.unread(b)
+			}
+			return err
+		default:
+		}
+
+		if rerr != nil {
+			return rerr
+		}
+
+		u.xbee.RX(b)
+	}
+}
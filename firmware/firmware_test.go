@@ -0,0 +1,303 @@
+package firmware
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+const menu = "\r\nGecko Bootloader v1.9.1\r\n1. upload gbl\r\n2. run\r\n3. ebl info\r\nBL > "
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+// bootloader a fake XBee, answering API frames until invoking its bootloader
+type bootloader struct {
+	size   int
+	abort  bool
+	api    chan []byte
+	in     chan byte
+	out    chan []byte
+	image  []byte
+	naks   int
+	closed chan struct{}
+}
+
+func newBootloader(t *testing.T, size int, abort bool) (*gobee.XBee, *bootloader) {
+	b := &bootloader{
+		size:   size,
+		abort:  abort,
+		api:    make(chan []byte, 1),
+		in:     make(chan byte, 4096),
+		out:    make(chan []byte, 1),
+		closed: make(chan struct{}),
+	}
+	t.Cleanup(func() { close(b.closed) })
+
+	return gobee.New(b, b, gobee.APIEscapeMode(api.EscapeModeInactive)), b
+}
+
+// Transmit satisfy gobee.XBeeTransmitter interface
+func (b *bootloader) Transmit(p []byte) (int, error) {
+	b.api <- append([]byte(nil), p[3:len(p)-1]...)
+	return len(p), nil
+}
+
+// Receive satisfy gobee.XBeeReceiver interface
+func (b *bootloader) Receive(rx.Frame) error {
+	return nil
+}
+
+// Read the serial port, timing out when the device sends nothing
+func (b *bootloader) Read(p []byte) (int, error) {
+	select {
+	case c := <-b.in:
+		p[0] = c
+		return 1, nil
+	case <-time.After(5 * time.Millisecond):
+		return 0, timeoutError{}
+	}
+}
+
+// Write the serial port
+func (b *bootloader) Write(p []byte) (int, error) {
+	select {
+	case b.out <- append([]byte(nil), p...):
+	case <-b.closed:
+	}
+	return len(p), nil
+}
+
+func (b *bootloader) send(p ...byte) {
+	for _, c := range p {
+		b.in <- c
+	}
+}
+
+// respond sends an API frame answering the local AT command cmd
+func (b *bootloader) respond(cmd string, data ...byte) error {
+	var p []byte
+	select {
+	case p = <-b.api:
+	case <-time.After(time.Second):
+		return fmt.Errorf("expected %s", cmd)
+	}
+	if p[0] != 0x08 || string(p[2:4]) != cmd {
+		return fmt.Errorf("expected %s, but got % x", cmd, p)
+	}
+
+	b.frame(append([]byte{0x88, p[1], p[2], p[3], 0x00}, data...)...)
+
+	return nil
+}
+
+// frame sends frame data f as an API frame
+func (b *bootloader) frame(f ...byte) {
+	sum := byte(0)
+	for _, c := range f {
+		sum += c
+	}
+	b.send(0x7E, byte(len(f)>>8), byte(len(f)))
+	b.send(f...)
+	b.send(0xFF - sum)
+}
+
+func (b *bootloader) receive() ([]byte, error) {
+	select {
+	case p := <-b.out:
+		return p, nil
+	case <-time.After(time.Second):
+		return nil, errors.New("expected write")
+	}
+}
+
+// run scripts the XBee through bootloading and running the new firmware
+func (b *bootloader) run() error {
+	if err := b.respond("%P"); err != nil {
+		return err
+	}
+
+	if p, err := b.receive(); err != nil || string(p) != "\r" {
+		return fmt.Errorf("expected menu request, but got %q %v", p, err)
+	}
+	b.send([]byte(menu)...)
+
+	if p, err := b.receive(); err != nil || string(p) != "1" {
+		return fmt.Errorf("expected upload, but got %q %v", p, err)
+	}
+	b.send([]byte("1\r\nbegin upload\r\n")...)
+	b.send(crc)
+
+	header := soh
+	if b.size == BlockSize1K {
+		header = stx
+	}
+
+	for n := byte(1); ; {
+		p, err := b.receive()
+		if err != nil {
+			return err
+		}
+		if p[0] == eot {
+			b.send(ack)
+			break
+		}
+
+		if len(p) != 3+b.size+2 || p[0] != header || p[1] != n || p[2] != ^n || binary.BigEndian.Uint16(p[3+b.size:]) != crc16(p[3:3+b.size]) {
+			return fmt.Errorf("expected block %d, but got % x", n, p)
+		}
+
+		// reject the first block once
+		if n == 1 && b.naks == 0 {
+			b.naks++
+			b.send(nak)
+			continue
+		}
+
+		b.image = append(b.image, p[3:3+b.size]...)
+		b.send(ack)
+		n++
+	}
+
+	if b.abort {
+		b.send([]byte("\r\nSerial upload aborted\r\n")...)
+		return nil
+	}
+	b.send([]byte("\r\nSerial upload complete\r\n" + menu)...)
+
+	if p, err := b.receive(); err != nil || string(p) != "2" {
+		return fmt.Errorf("expected run, but got %q %v", p, err)
+	}
+
+	// the new firmware reports a hardware reset once running
+	b.frame(0x8A, 0x00)
+
+	return b.respond("VR", 0x10, 0x0A)
+}
+
+// start runs the script, returning its result
+func (b *bootloader) start() <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- b.run()
+	}()
+
+	return errs
+}
+
+func testImage(size int) []byte {
+	p := bytes.Repeat([]byte{0xA5}, size)
+	binary.LittleEndian.PutUint32(p, gblHeaderTag)
+	return p
+}
+
+// idle a serial port that never has data, counting reads
+type idle struct {
+	reads int
+}
+
+func (p *idle) Read([]byte) (int, error)    { p.reads++; return 0, nil }
+func (p *idle) Write(b []byte) (int, error) { return len(b), nil }
+
+func TestPort_ReadByte_Idle(t *testing.T) {
+	rw := &idle{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := (&port{rw: rw}).readByte(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected error %v, but got %v", context.DeadlineExceeded, err)
+	}
+	if rw.reads > 10 {
+		t.Fatalf("Expected reads to back off, but got %d reads", rw.reads)
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	if c := crc16([]byte("123456789")); c != 0x31C3 {
+		t.Fatalf("Expected 0x31c3, but got %#x", c)
+	}
+}
+
+func TestUpdater_Update(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name string
+		size int
+	}{
+		{"128", BlockSize128},
+		{"1K", BlockSize1K},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			xbee, b := newBootloader(t, tt.size, false)
+			script := b.start()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			image := testImage(1300)
+			version, err := NewUpdater(xbee, b, BlockSize(tt.size)).Update(ctx, image)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if err := <-script; err != nil {
+				t.Fatalf("Expected bootloader script to succeed, but got %v", err)
+			}
+			if version != 0x100A {
+				t.Fatalf("Expected version 0x100a, but got %#x", version)
+			}
+
+			if !bytes.Equal(bytes.TrimRight(b.image, string(pad)), image) {
+				t.Fatal("Expected received blocks to equal the image")
+			}
+			if b.naks != 1 {
+				t.Fatalf("Expected rejected block to be resent, but got %d rejections", b.naks)
+			}
+		})
+	}
+}
+
+func TestUpdater_Update_Aborted(t *testing.T) {
+	xbee, b := newBootloader(t, BlockSize128, true)
+	script := b.start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := NewUpdater(xbee, b).Update(ctx, testImage(200)); err != ErrUpload {
+		t.Fatalf("Expected error %v, but got %v", ErrUpload, err)
+	}
+	if err := <-script; err != nil {
+		t.Fatalf("Expected bootloader script to succeed, but got %v", err)
+	}
+}
+
+func TestUpdater_Update_Invalid(t *testing.T) {
+	xbee, b := newBootloader(t, BlockSize128, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := NewUpdater(xbee, b).Update(ctx, []byte("not an image")); err != ErrImage {
+		t.Fatalf("Expected error %v, but got %v", ErrImage, err)
+	}
+	if _, err := NewUpdater(xbee, b, BlockSize(256)).Update(ctx, testImage(200)); err != ErrBlockSize {
+		t.Fatalf("Expected error %v, but got %v", ErrBlockSize, err)
+	}
+}
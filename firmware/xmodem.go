@@ -0,0 +1,213 @@
+package firmware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// XMODEM control characters
+const (
+	soh byte = 0x01
+	stx byte = 0x02
+	eot byte = 0x04
+	ack byte = 0x06
+	nak byte = 0x15
+	can byte = 0x18
+	crc byte = 'C'
+
+	// pad fills the last block
+	pad byte = 0x1A
+)
+
+// readBackoff wait before reading again after a read returns no bytes
+const readBackoff = 10 * time.Millisecond
+
+// XMODEM block sizes
+const (
+	BlockSize128 = 128
+	BlockSize1K  = 1024
+)
+
+var (
+	// ErrCanceled the receiver canceled the transfer
+	ErrCanceled = errors.New("transfer canceled by receiver")
+	// ErrRetries a block was rejected too many times
+	ErrRetries = errors.New("too many retries")
+)
+
+// port reads the serial port a byte at a time. A port with a read timeout
+// returning no bytes lets reads observe their context.
+type port struct {
+	rw      io.ReadWriter
+	pending []byte
+}
+
+// readByte reads the next byte, waiting until ctx is done
+func (p *port) readByte(ctx context.Context) (byte, error) {
+	if len(p.pending) > 0 {
+		b := p.pending[0]
+		p.pending = p.pending[1:]
+		return b, nil
+	}
+
+	var b [1]byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		n, err := p.rw.Read(b[:])
+		if n == 1 {
+			return b[0], nil
+		}
+		if err != nil && !timeout(err) {
+			return 0, err
+		}
+
+		select {
+		case <-time.After(readBackoff):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// unread returns b to be read next
+func (p *port) unread(b byte) {
+	p.pending = append([]byte{b}, p.pending...)
+}
+
+// expect reads until one of texts has been received, returning its index
+func (p *port) expect(ctx context.Context, texts ...string) (int, error) {
+	var received []byte
+	for {
+		b, err := p.readByte(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		received = append(received, b)
+		for i, text := range texts {
+			if strings.HasSuffix(string(received), text) {
+				return i, nil
+			}
+		}
+	}
+}
+
+func (p *port) write(b ...byte) error {
+	_, err := p.rw.Write(b)
+	return err
+}
+
+func timeout(err error) bool {
+	t, ok := err.(interface{ Timeout() bool })
+	return ok && t.Timeout()
+}
+
+// crc16 CRC-16/XMODEM
+func crc16(p []byte) uint16 {
+	var c uint16
+	for _, b := range p {
+		c ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if c&0x8000 != 0 {
+				c = c<<1 ^ 0x1021
+			} else {
+				c <<= 1
+			}
+		}
+	}
+
+	return c
+}
+
+// block an XMODEM-CRC block of number n carrying data padded to size
+func block(n byte, data []byte, size int) []byte {
+	header := soh
+	if size == BlockSize1K {
+		header = stx
+	}
+
+	b := make([]byte, 3, 3+size+2)
+	b[0], b[1], b[2] = header, n, ^n
+	b = append(b, data...)
+	for len(b) < 3+size {
+		b = append(b, pad)
+	}
+
+	c := crc16(b[3:])
+
+	return append(b, byte(c>>8), byte(c))
+}
+
+// send transfers image with XMODEM-CRC once the receiver has requested CRC
+// mode, resending each rejected block up to retries times
+func (p *port) send(ctx context.Context, image []byte, size, retries int) error {
+	if err := p.await(ctx, crc); err != nil {
+		return err
+	}
+
+	n := byte(1)
+	for offset := 0; offset < len(image); offset += size {
+		end := offset + size
+		if end > len(image) {
+			end = len(image)
+		}
+
+		if err := p.transmit(ctx, block(n, image[offset:end], size), retries); err != nil {
+			return err
+		}
+		n++
+	}
+
+	return p.transmit(ctx, []byte{eot}, retries)
+}
+
+// await reads until the receiver sends b
+func (p *port) await(ctx context.Context, b byte) error {
+	for {
+		r, err := p.readByte(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch r {
+		case b:
+			return nil
+		case can:
+			return ErrCanceled
+		}
+	}
+}
+
+// transmit writes b until acknowledged
+func (p *port) transmit(ctx context.Context, b []byte, retries int) error {
+	for attempt := 0; attempt <= retries; attempt++ {
+		if err := p.write(b...); err != nil {
+			return err
+		}
+
+		for {
+			r, err := p.readByte(ctx)
+			if err != nil {
+				return err
+			}
+
+			if r == ack {
+				return nil
+			}
+			if r == can {
+				return ErrCanceled
+			}
+			if r == nak {
+				break
+			}
+		}
+	}
+
+	return ErrRetries
+}
@@ -1 +1,3 @@
 module github.com/pauleyj/gobee
+
+go 1.21
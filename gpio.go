@@ -0,0 +1,264 @@
+package gobee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// maxDutyCycle largest PWM duty cycle, 10-bit
+const maxDutyCycle uint16 = 0x03FF
+
+var (
+	// ErrPinMode the pin does not support the mode on the remote's hardware variant
+	ErrPinMode = errors.New("pin mode not supported")
+	// ErrDutyCycle PWM duty cycle out of range
+	ErrDutyCycle = errors.New("PWM duty cycle out of range")
+	// ErrNotSampled the pin was not part of the sample, it is not configured for input
+	ErrNotSampled = errors.New("pin not sampled")
+)
+
+// Variant defines XBee hardware variants
+type Variant byte
+
+// Hardware variants, pins of VariantUnknown are validated against the modes
+// every variant supports
+const (
+	VariantUnknown = Variant(0)
+	VariantS1      = Variant(1)
+	VariantS2      = Variant(2)
+	VariantS2C     = Variant(3)
+	VariantXBee3   = Variant(4)
+)
+
+// VariantSetter interface for HardwareVariant setters
+type VariantSetter interface {
+	SetVariant(Variant)
+}
+
+// HardwareVariant helper option function to XBee.Remote
+func HardwareVariant(variant Variant) func(interface{}) {
+	return func(i interface{}) {
+		if t, ok := i.(VariantSetter); ok {
+			t.SetVariant(variant)
+		}
+	}
+}
+
+// variantForHardwareVersion maps the HV command's hardware version to a variant
+func variantForHardwareVersion(hv uint16) Variant {
+	switch hv >> 8 {
+	case 0x17, 0x18:
+		return VariantS1
+	case 0x19, 0x1A, 0x1E:
+		return VariantS2
+	case 0x21, 0x22, 0x23, 0x2D:
+		return VariantS2C
+	case 0x41, 0x42, 0x43, 0x44, 0x46:
+		return VariantXBee3
+	default:
+		return VariantUnknown
+	}
+}
+
+// PinMode defines IO pin modes
+type PinMode byte
+
+// Pin modes
+const (
+	Disabled       = PinMode(0)
+	AnalogIn       = PinMode(1)
+	DigitalIn      = PinMode(2)
+	DigitalOutLow  = PinMode(3)
+	DigitalOutHigh = PinMode(4)
+	PWMOut         = PinMode(5)
+)
+
+func (m PinMode) String() string {
+	switch m {
+	case Disabled:
+		return "disabled"
+	case AnalogIn:
+		return "analog input"
+	case DigitalIn:
+		return "digital input"
+	case DigitalOutLow:
+		return "digital output, low"
+	case DigitalOutHigh:
+		return "digital output, high"
+	case PWMOut:
+		return "PWM output"
+	default:
+		return fmt.Sprintf("unknown mode (%d)", m)
+	}
+}
+
+// parameter the Dn/Pn command parameter selecting the mode
+func (m PinMode) parameter() byte {
+	switch m {
+	case AnalogIn, PWMOut:
+		return 2
+	case DigitalIn:
+		return 3
+	case DigitalOutLow:
+		return 4
+	case DigitalOutHigh:
+		return 5
+	default:
+		return 0
+	}
+}
+
+var (
+	digitalModes = []PinMode{Disabled, DigitalIn, DigitalOutLow, DigitalOutHigh}
+	analogModes  = []PinMode{Disabled, AnalogIn, DigitalIn, DigitalOutLow, DigitalOutHigh}
+	pwmModes     = []PinMode{Disabled, DigitalIn, DigitalOutLow, DigitalOutHigh, PWMOut}
+
+	// pinModes modes supported by each pin per hardware variant
+	pinModes = map[Variant]map[api.DIO][]PinMode{
+		// the modes shared by all variants
+		VariantUnknown: {
+			api.DIO0: analogModes, api.DIO1: analogModes, api.DIO2: analogModes,
+			api.DIO3: analogModes, api.DIO4: digitalModes, api.DIO5: digitalModes,
+			api.DIO6: digitalModes, api.DIO7: digitalModes, api.DIO10: digitalModes,
+			api.DIO11: digitalModes,
+		},
+		VariantS1: {
+			api.DIO0: analogModes, api.DIO1: analogModes, api.DIO2: analogModes,
+			api.DIO3: analogModes, api.DIO4: analogModes, api.DIO5: analogModes,
+			api.DIO6: digitalModes, api.DIO7: digitalModes, api.DIO8: digitalModes,
+			api.DIO10: pwmModes, api.DIO11: pwmModes,
+		},
+		VariantS2: {
+			api.DIO0: analogModes, api.DIO1: analogModes, api.DIO2: analogModes,
+			api.DIO3: analogModes, api.DIO4: digitalModes, api.DIO5: digitalModes,
+			api.DIO6: digitalModes, api.DIO7: digitalModes, api.DIO10: digitalModes,
+			api.DIO11: digitalModes, api.DIO12: digitalModes,
+		},
+		VariantS2C: {
+			api.DIO0: analogModes, api.DIO1: analogModes, api.DIO2: analogModes,
+			api.DIO3: analogModes, api.DIO4: digitalModes, api.DIO5: digitalModes,
+			api.DIO6: digitalModes, api.DIO7: digitalModes, api.DIO8: digitalModes,
+			api.DIO9: digitalModes, api.DIO10: pwmModes, api.DIO11: digitalModes,
+			api.DIO12: digitalModes,
+		},
+		VariantXBee3: {
+			api.DIO0: analogModes, api.DIO1: analogModes, api.DIO2: analogModes,
+			api.DIO3: analogModes, api.DIO4: digitalModes, api.DIO5: digitalModes,
+			api.DIO6: digitalModes, api.DIO7: digitalModes, api.DIO8: digitalModes,
+			api.DIO9: digitalModes, api.DIO10: pwmModes, api.DIO11: pwmModes,
+			api.DIO12: digitalModes,
+		},
+	}
+)
+
+// Supports does the pin support mode on the hardware variant, variants
+// without a table of their own support the modes shared by all variants
+func (v Variant) Supports(line api.DIO, mode PinMode) bool {
+	pins, ok := pinModes[v]
+	if !ok {
+		pins = pinModes[VariantUnknown]
+	}
+
+	for _, m := range pins[line] {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Pin an IO pin of a remote XBee
+type Pin struct {
+	device *RemoteDevice
+	line   api.DIO
+}
+
+// Pin IO pin of the remote XBee
+func (d *RemoteDevice) Pin(line api.DIO) *Pin {
+	return &Pin{device: d, line: line}
+}
+
+// Line the pin's IO line
+func (p *Pin) Line() api.DIO {
+	return p.line
+}
+
+// command the Dn or Pn command configuring the pin
+func (p *Pin) command() [2]byte {
+	if p.line < api.DIO10 {
+		return [2]byte{'D', '0' + byte(p.line)}
+	}
+
+	return [2]byte{'P', '0' + byte(p.line-api.DIO10)}
+}
+
+// SetMode configures the pin's mode, changes are applied immediately
+func (p *Pin) SetMode(ctx context.Context, mode PinMode) error {
+	variant, err := p.device.Variant(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !variant.Supports(p.line, mode) {
+		return ErrPinMode
+	}
+
+	_, err = p.device.xbee.remoteAT(ctx, p.device.addr64, p.command(), []byte{mode.parameter()}, tx.OptionApplyChanges)
+
+	return err
+}
+
+// ConfigurePWM configures the pin for PWM output with dutyCycle, 0 to 0x3FF,
+// only DIO10 and DIO11 have PWM outputs
+func (p *Pin) ConfigurePWM(ctx context.Context, dutyCycle uint16) error {
+	if p.line != api.DIO10 && p.line != api.DIO11 {
+		return ErrPinMode
+	}
+	if dutyCycle > maxDutyCycle {
+		return ErrDutyCycle
+	}
+
+	if err := p.SetMode(ctx, PWMOut); err != nil {
+		return err
+	}
+
+	cmd := [2]byte{'M', '0' + byte(p.line-api.DIO10)}
+	_, err := p.device.xbee.remoteAT(ctx, p.device.addr64, cmd, []byte{byte(dutyCycle >> 8), byte(dutyCycle)}, tx.OptionApplyChanges)
+
+	return err
+}
+
+// Read state of the pin, the pin must be configured as a digital input or output
+func (p *Pin) Read(ctx context.Context) (bool, error) {
+	s, err := p.device.xbee.ForceSample(ctx, p.device.addr64)
+	if err != nil {
+		return false, err
+	}
+
+	state, ok := s.Digital[p.line]
+	if !ok {
+		return false, ErrNotSampled
+	}
+
+	return state, nil
+}
+
+// ReadAnalog raw analog reading of the pin, the pin must be configured as an analog input
+func (p *Pin) ReadAnalog(ctx context.Context) (uint16, error) {
+	s, err := p.device.xbee.ForceSample(ctx, p.device.addr64)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, ok := s.Analog[api.AD(p.line)]
+	if !ok {
+		return 0, ErrNotSampled
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,206 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+)
+
+// atRemoteResponse builds the remote AT command response to request p
+func atRemoteResponse(p []byte, status byte, data ...byte) []byte {
+	f := []byte{0x97, p[1]}
+	f = append(f, p[2:10]...)
+	f = append(f, 0x12, 0x34, p[13], p[14], status)
+	return append(f, data...)
+}
+
+// remoteCommand a remote AT command request as seen by the fake radio
+type remoteCommand struct {
+	options   byte
+	command   string
+	parameter []byte
+}
+
+func parseRemoteCommand(t *testing.T, p []byte) remoteCommand {
+	if p[0] != 0x17 {
+		t.Fatalf("Expected remote AT command frame, but got API ID %#0.2x", p[0])
+	}
+
+	return remoteCommand{options: p[12], command: string(p[13:15]), parameter: p[15:]}
+}
+
+func gpioContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Second)
+}
+
+func TestPin_SetMode(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent = append(sent, parseRemoteCommand(t, p))
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	pin := xbee.Remote(0x0013A20040522BAA, HardwareVariant(VariantXBee3)).Pin(api.DIO4)
+	if err := pin.SetMode(ctx, DigitalOutHigh); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 command, but got %d", len(sent))
+	}
+	if sent[0].command != "D4" || sent[0].options != 0x02 || len(sent[0].parameter) != 1 || sent[0].parameter[0] != 5 {
+		t.Fatalf("Expected D4=5 with apply changes, but got %+v", sent[0])
+	}
+
+	if err := pin.SetMode(ctx, PWMOut); err != ErrPinMode {
+		t.Fatalf("Expected error %v, but got %v", ErrPinMode, err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Expected unsupported mode not to be sent, but got %d commands", len(sent))
+	}
+}
+
+func TestPin_SetMode_Detect_Variant(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		cmd := parseRemoteCommand(t, p)
+		sent = append(sent, cmd)
+		if cmd.command == "HV" {
+			return [][]byte{atRemoteResponse(p, 0, 0x19, 0x44)}
+		}
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	remote := xbee.Remote(0x0013A20040522BAA)
+	if err := remote.Pin(api.DIO10).ConfigurePWM(ctx, 0x0200); err != ErrPinMode {
+		t.Fatalf("Expected error %v, but got %v", ErrPinMode, err)
+	}
+
+	variant, err := remote.Variant(ctx)
+	if err != nil || variant != VariantS2 {
+		t.Fatalf("Expected variant %d, but got %d (%v)", VariantS2, variant, err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Expected hardware version to be read once, but got %d commands", len(sent))
+	}
+}
+
+func TestPin_SetMode_Unknown_Variant(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		cmd := parseRemoteCommand(t, p)
+		sent = append(sent, cmd)
+		if cmd.command == "HV" {
+			return [][]byte{atRemoteResponse(p, 0, 0xFF, 0x00)}
+		}
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	pin := xbee.Remote(0x0013A20040522BAA).Pin(api.DIO4)
+	if err := pin.ConfigurePWM(ctx, 0x0200); err != ErrPinMode {
+		t.Fatalf("Expected error %v, but got %v", ErrPinMode, err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("Expected PWM on DIO4 to be rejected before sending, but got %d commands", len(sent))
+	}
+
+	if err := pin.SetMode(ctx, AnalogIn); err != ErrPinMode {
+		t.Fatalf("Expected error %v, but got %v", ErrPinMode, err)
+	}
+	if err := pin.SetMode(ctx, DigitalIn); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if len(sent) != 2 || sent[0].command != "HV" || sent[1].command != "D4" {
+		t.Fatalf("Expected hardware version to be read once, but got %+v", sent)
+	}
+}
+
+func TestPin_ConfigurePWM(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent = append(sent, parseRemoteCommand(t, p))
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	pin := xbee.Remote(0x0013A20040522BAA, HardwareVariant(VariantXBee3)).Pin(api.DIO11)
+	if err := pin.ConfigurePWM(ctx, 0x0400); err != ErrDutyCycle {
+		t.Fatalf("Expected error %v, but got %v", ErrDutyCycle, err)
+	}
+
+	if err := pin.ConfigurePWM(ctx, 0x0200); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 commands, but got %d", len(sent))
+	}
+	if sent[0].command != "P1" || sent[0].parameter[0] != 2 {
+		t.Fatalf("Expected P1=2, but got %+v", sent[0])
+	}
+	if sent[1].command != "M1" || sent[1].parameter[0] != 0x02 || sent[1].parameter[1] != 0x00 {
+		t.Fatalf("Expected M1=0x0200, but got %+v", sent[1])
+	}
+}
+
+func TestPin_Read(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if cmd := parseRemoteCommand(t, p); cmd.command != "IS" {
+			t.Fatalf("Expected IS, but got %s", cmd.command)
+		}
+		// DIO4 high, DIO5 low, AD1
+		return [][]byte{atRemoteResponse(p, 0, 0x01, 0x00, 0x30, 0x02, 0x00, 0x10, 0x01, 0x23)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	remote := xbee.Remote(0x0013A20040522BAA, HardwareVariant(VariantXBee3))
+
+	state, err := remote.Pin(api.DIO4).Read(ctx)
+	if err != nil || !state {
+		t.Fatalf("Expected DIO4 high, but got %v (%v)", state, err)
+	}
+
+	state, err = remote.Pin(api.DIO5).Read(ctx)
+	if err != nil || state {
+		t.Fatalf("Expected DIO5 low, but got %v (%v)", state, err)
+	}
+
+	raw, err := remote.Pin(api.DIO1).ReadAnalog(ctx)
+	if err != nil || raw != 0x0123 {
+		t.Fatalf("Expected AD1=0x0123, but got %#0.4x (%v)", raw, err)
+	}
+
+	if _, err := remote.Pin(api.DIO6).Read(ctx); err != ErrNotSampled {
+		t.Fatalf("Expected error %v, but got %v", ErrNotSampled, err)
+	}
+}
+
+func TestPin_SetMode_AT_Error(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		return [][]byte{atRemoteResponse(p, 3)}
+	})
+
+	ctx, cancel := gpioContext()
+	defer cancel()
+
+	err := xbee.Remote(0x0013A20040522BAA, HardwareVariant(VariantXBee3)).Pin(api.DIO4).SetMode(ctx, DigitalIn)
+	if e, ok := err.(*ATError); !ok || e.Status != 3 || e.Command != [2]byte{'D', '4'} {
+		t.Fatalf("Expected D4 invalid parameter error, but got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Package radiotest fakes the radio behind a gobee.XBee for tests, answering
+// transmitted frames and feeding received frames as API frames, and the remote
+// ZCL cluster servers answering a zcl.Client.
+package radiotest
+
+import (
+	"sync"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+// Radio a fake radio answering the frame data of each transmitted API frame
+// with the frame data returned by reply
+type Radio struct {
+	mu    sync.Mutex
+	xbee  *gobee.XBee
+	reply func(p []byte) [][]byte
+}
+
+type nopReceiver struct{}
+
+func (r *nopReceiver) Receive(f rx.Frame) error {
+	return nil
+}
+
+// New constructs an XBee, with escaping inactive, on a fake radio
+func New(reply func(p []byte) [][]byte, options ...func(interface{})) (*gobee.XBee, *Radio) {
+	r := &Radio{reply: reply}
+	r.xbee = gobee.New(r, &nopReceiver{}, append([]func(interface{}){gobee.APIEscapeMode(api.EscapeModeInactive)}, options...)...)
+
+	return r.xbee, r
+}
+
+// Transmit satisfy gobee.XBeeTransmitter interface, replies are received asynchronously
+func (r *Radio) Transmit(p []byte) (int, error) {
+	if r.reply != nil {
+		frames := r.reply(p[3 : len(p)-1])
+		go r.Send(frames...)
+	}
+
+	return len(p), nil
+}
+
+// Send feeds frame data to the XBee as complete API frames
+func (r *Radio) Send(frames ...[]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range frames {
+		sum := byte(0)
+		for _, b := range f {
+			sum += b
+		}
+
+		r.xbee.RX(0x7E)
+		r.xbee.RX(byte(len(f) >> 8))
+		r.xbee.RX(byte(len(f)))
+		for _, b := range f {
+			r.xbee.RX(b)
+		}
+		r.xbee.RX(0xFF - sum)
+	}
+}
+
+// Explicit an explicit addressing frame
+type Explicit struct {
+	ID          byte
+	Addr64      uint64
+	Addr16      uint16
+	SrcEndpoint byte
+	DstEndpoint byte
+	ClusterID   uint16
+	ProfileID   uint16
+	Options     byte
+	Data        []byte
+}
+
+// ParseExplicit decodes transmitted explicit addressing frame data (0x11),
+// ok is false for other frames
+func ParseExplicit(p []byte) (Explicit, bool) {
+	if len(p) < 20 || p[0] != 0x11 {
+		return Explicit{}, false
+	}
+
+	var addr64 uint64
+	for _, b := range p[2:10] {
+		addr64 = addr64<<8 | uint64(b)
+	}
+
+	return Explicit{
+		ID:          p[1],
+		Addr64:      addr64,
+		Addr16:      uint16(p[10])<<8 | uint16(p[11]),
+		SrcEndpoint: p[12],
+		DstEndpoint: p[13],
+		ClusterID:   uint16(p[14])<<8 | uint16(p[15]),
+		ProfileID:   uint16(p[16])<<8 | uint16(p[17]),
+		Options:     p[19],
+		Data:        p[20:],
+	}, true
+}
+
+// Received explicit RX indicator frame data (0x91) for e, ID is ignored
+func (e Explicit) Received() []byte {
+	a := e.Addr64
+	p := []byte{0x91,
+		byte(a >> 56), byte(a >> 48), byte(a >> 40), byte(a >> 32),
+		byte(a >> 24), byte(a >> 16), byte(a >> 8), byte(a),
+		byte(e.Addr16 >> 8), byte(e.Addr16),
+		e.SrcEndpoint, e.DstEndpoint,
+		byte(e.ClusterID >> 8), byte(e.ClusterID),
+		byte(e.ProfileID >> 8), byte(e.ProfileID),
+		e.Options}
+
+	return append(p, e.Data...)
+}
+
+// Reply explicit RX indicator frame data from the destination of e back to
+// its source, with data, on cluster
+func (e Explicit) Reply(cluster uint16, data []byte) []byte {
+	return Explicit{
+		Addr64:      e.Addr64,
+		Addr16:      e.Addr16,
+		SrcEndpoint: e.DstEndpoint,
+		DstEndpoint: e.SrcEndpoint,
+		ClusterID:   cluster,
+		ProfileID:   e.ProfileID,
+		Options:     0x01,
+		Data:        data,
+	}.Received()
+}
+
+// TXStatus transmit status frame data (0x8B) for frame ID id
+func TXStatus(id byte, delivery byte) []byte {
+	return []byte{0x8B, id, 0xFF, 0xFE, 0x00, delivery, 0x00}
+}
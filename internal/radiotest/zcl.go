@@ -0,0 +1,41 @@
+package radiotest
+
+import (
+	"testing"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Destination a Home Automation endpoint on a remote device
+var Destination = zcl.Address{
+	Addr64:      0x0013A20040522BAA,
+	Addr16:      0x1234,
+	SrcEndpoint: 0xE8,
+	DstEndpoint: 0x01,
+	ProfileID:   zcl.ProfileHomeAutomation,
+}
+
+// Cluster constructs a ZCL client whose requests on cluster are answered with
+// the frame returned by response, sent back with the request's sequence number
+// from server to client
+func Cluster(t testing.TB, cluster uint16, response func(f *zcl.Frame) *zcl.Frame) *zcl.Client {
+	xbee, _ := New(func(p []byte) [][]byte {
+		e, ok := ParseExplicit(p)
+		if !ok || e.ClusterID != cluster {
+			t.Fatalf("Expected explicit frame on cluster 0x%04x, but got % x", cluster, p)
+		}
+
+		f, err := zcl.Parse(e.Data)
+		if err != nil {
+			t.Fatalf("Expected ZCL frame, but got %v", err)
+		}
+
+		r := response(f)
+		r.Seq = f.Seq
+		r.Direction = zcl.ServerToClient
+
+		return [][]byte{TXStatus(e.ID, 0), e.Reply(e.ClusterID, r.Bytes())}
+	})
+
+	return zcl.New(xbee)
+}
@@ -0,0 +1,28 @@
+// Package rxloop runs the receive loops of the packages answering frames from
+// remote nodes.
+package rxloop
+
+import (
+	"sync"
+
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+// Each calls handle for each frame received until frames is closed. Frames
+// are handled in order, the func handle returns, if not nil, runs
+// concurrently so that waiting on a transmit status does not hold up the
+// frames that follow. Each returns once every such func has returned.
+func Each(frames <-chan rx.Frame, handle func(f rx.Frame) func()) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for f := range frames {
+		if reply := handle(f); reply != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reply()
+			}()
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package gobee
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const listenerBufferSize = 16
+
+// listener receives the frames accepted by match until unlistened. A
+// buffered listener holds listenerBufferSize frames on c and drops frames
+// while it is full, a queued listener queues every frame for next.
+type listener struct {
+	match func(rx.Frame) bool
+	c     chan rx.Frame
+
+	mu     sync.Mutex
+	queue  []rx.Frame
+	queued chan struct{}
+}
+
+// listen registers a buffered listener for received frames accepted by match
+func (x *XBee) listen(match func(rx.Frame) bool) *listener {
+	return x.register(&listener{
+		match: match,
+		c:     make(chan rx.Frame, listenerBufferSize),
+	})
+}
+
+// listenAll registers a queued listener for received frames accepted by
+// match, for requests answered by a burst of frames that must not be lost
+func (x *XBee) listenAll(match func(rx.Frame) bool) *listener {
+	return x.register(&listener{
+		match:  match,
+		queued: make(chan struct{}, 1),
+	})
+}
+
+func (x *XBee) register(l *listener) *listener {
+	x.mu.Lock()
+	if x.listeners == nil {
+		x.listeners = make(map[*listener]struct{})
+	}
+	x.listeners[l] = struct{}{}
+	x.mu.Unlock()
+
+	return l
+}
+
+// unlisten removes the listener, frames are no longer delivered to it
+func (x *XBee) unlisten(l *listener) {
+	x.mu.Lock()
+	delete(x.listeners, l)
+	x.mu.Unlock()
+}
+
+// dispatch delivers a received frame to all interested listeners. Delivery
+// never blocks RX: a frame is dropped for a buffered listener whose buffer is
+// full, so a subscriber that stops reading loses frames instead of stalling
+// every other request.
+func (x *XBee) dispatch(f rx.Frame) {
+	x.mu.Lock()
+	listeners := make([]*listener, 0, len(x.listeners))
+	for l := range x.listeners {
+		listeners = append(listeners, l)
+	}
+	x.mu.Unlock()
+
+	for _, l := range listeners {
+		if l.match(f) {
+			l.deliver(f)
+		}
+	}
+}
+
+// deliver passes f to the listener, dropping it when a buffered listener is
+// full
+func (l *listener) deliver(f rx.Frame) {
+	if l.queued == nil {
+		select {
+		case l.c <- f:
+		default:
+		}
+		return
+	}
+
+	l.mu.Lock()
+	l.queue = append(l.queue, f)
+	l.mu.Unlock()
+
+	select {
+	case l.queued <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for the next frame of a queued listener
+func (l *listener) next(ctx context.Context) (rx.Frame, error) {
+	for {
+		l.mu.Lock()
+		if len(l.queue) > 0 {
+			f := l.queue[0]
+			l.queue[0] = nil
+			l.queue = l.queue[1:]
+			l.mu.Unlock()
+
+			return f, nil
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-l.queued:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// NextFrameID next frame ID for frames expecting a response, never 0
+func (x *XBee) NextFrameID() byte {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.frameID++
+	if x.frameID == 0 {
+		x.frameID++
+	}
+
+	return x.frameID
+}
+
+// Request transmits frame and waits for the first received frame accepted by
+// match. Up to listenerBufferSize frames are held until Request reads the
+// first, later frames are dropped, so match should accept only the answer.
+func (x *XBee) Request(ctx context.Context, frame tx.Frame, match func(rx.Frame) bool) (rx.Frame, error) {
+	l := x.listen(match)
+	defer x.unlisten(l)
+
+	if _, err := x.TX(frame); err != nil {
+		return nil, err
+	}
+
+	select {
+	case f := <-l.c:
+		return f, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Subscribe streams the received frames accepted by match until ctx is done,
+// the channel is closed when ctx is done. The stream buffers
+// listenerBufferSize frames on the channel and as many again while delivering
+// them, frames arriving while both are full are dropped without error, so
+// read it promptly.
+func (x *XBee) Subscribe(ctx context.Context, match func(rx.Frame) bool) <-chan rx.Frame {
+	l := x.listen(match)
+
+	frames := make(chan rx.Frame, listenerBufferSize)
+
+	go func() {
+		defer close(frames)
+		defer x.unlisten(l)
+
+		for {
+			select {
+			case f := <-l.c:
+				select {
+				case frames <- f:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return frames
+}
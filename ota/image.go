@@ -0,0 +1,170 @@
+package ota
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+)
+
+const (
+	// Magic OTA upgrade file identifier
+	Magic uint32 = 0x0BEEF11E
+
+	// headerLength OTA header length without optional fields
+	headerLength = 56
+	// headerStringLength OTA header string length
+	headerStringLength = 32
+)
+
+// Header field control bits
+const (
+	FieldSecurityCredential uint16 = 1 << 0
+	FieldDestination        uint16 = 1 << 1
+	FieldHardwareVersions   uint16 = 1 << 2
+)
+
+// ErrImage not an OTA upgrade file
+var ErrImage = errors.New("invalid OTA upgrade image")
+
+// Header an OTA upgrade file header, the optional fields are present as
+// given by FieldControl
+type Header struct {
+	Version          uint16
+	Length           uint16
+	FieldControl     uint16
+	ManufacturerCode uint16
+	ImageType        uint16
+	FileVersion      uint32
+	StackVersion     uint16
+	String           string
+	// TotalSize the file size, header included
+	TotalSize                 uint32
+	SecurityCredentialVersion byte
+	Destination               uint64
+	MinHardwareVersion        uint16
+	MaxHardwareVersion        uint16
+}
+
+// Bytes encodes the header
+func (h *Header) Bytes() []byte {
+	p := make([]byte, headerLength)
+	binary.LittleEndian.PutUint32(p, Magic)
+	binary.LittleEndian.PutUint16(p[4:], h.Version)
+	binary.LittleEndian.PutUint16(p[6:], h.Length)
+	binary.LittleEndian.PutUint16(p[8:], h.FieldControl)
+	binary.LittleEndian.PutUint16(p[10:], h.ManufacturerCode)
+	binary.LittleEndian.PutUint16(p[12:], h.ImageType)
+	binary.LittleEndian.PutUint32(p[14:], h.FileVersion)
+	binary.LittleEndian.PutUint16(p[18:], h.StackVersion)
+	copy(p[20:20+headerStringLength], h.String)
+	binary.LittleEndian.PutUint32(p[52:], h.TotalSize)
+
+	if h.FieldControl&FieldSecurityCredential != 0 {
+		p = append(p, h.SecurityCredentialVersion)
+	}
+	if h.FieldControl&FieldDestination != 0 {
+		p = append(p, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint64(p[len(p)-8:], h.Destination)
+	}
+	if h.FieldControl&FieldHardwareVersions != 0 {
+		p = append(p, byte(h.MinHardwareVersion), byte(h.MinHardwareVersion>>8),
+			byte(h.MaxHardwareVersion), byte(h.MaxHardwareVersion>>8))
+	}
+
+	return p
+}
+
+// ParseHeader decodes an OTA upgrade file header
+func ParseHeader(p []byte) (*Header, error) {
+	if len(p) < headerLength || binary.LittleEndian.Uint32(p) != Magic {
+		return nil, ErrImage
+	}
+
+	h := &Header{
+		Version:          binary.LittleEndian.Uint16(p[4:]),
+		Length:           binary.LittleEndian.Uint16(p[6:]),
+		FieldControl:     binary.LittleEndian.Uint16(p[8:]),
+		ManufacturerCode: binary.LittleEndian.Uint16(p[10:]),
+		ImageType:        binary.LittleEndian.Uint16(p[12:]),
+		FileVersion:      binary.LittleEndian.Uint32(p[14:]),
+		StackVersion:     binary.LittleEndian.Uint16(p[18:]),
+		String:           string(bytes.TrimRight(p[20:20+headerStringLength], "\x00")),
+		TotalSize:        binary.LittleEndian.Uint32(p[52:]),
+	}
+
+	if int(h.Length) < headerLength || len(p) < int(h.Length) {
+		return nil, ErrImage
+	}
+
+	o := p[headerLength:h.Length]
+	if h.FieldControl&FieldSecurityCredential != 0 {
+		if len(o) < 1 {
+			return nil, ErrImage
+		}
+		h.SecurityCredentialVersion, o = o[0], o[1:]
+	}
+	if h.FieldControl&FieldDestination != 0 {
+		if len(o) < 8 {
+			return nil, ErrImage
+		}
+		h.Destination, o = binary.LittleEndian.Uint64(o), o[8:]
+	}
+	if h.FieldControl&FieldHardwareVersions != 0 {
+		if len(o) < 4 {
+			return nil, ErrImage
+		}
+		h.MinHardwareVersion = binary.LittleEndian.Uint16(o)
+		h.MaxHardwareVersion = binary.LittleEndian.Uint16(o[2:])
+	}
+
+	return h, nil
+}
+
+// Image an OTA upgrade file
+type Image struct {
+	Header
+	// Data the whole file, header included, as served in image blocks
+	Data []byte
+}
+
+// ParseImage decodes an OTA upgrade file
+func ParseImage(p []byte) (*Image, error) {
+	h, err := ParseHeader(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(len(p)) != h.TotalSize {
+		return nil, ErrImage
+	}
+
+	return &Image{Header: *h, Data: p}, nil
+}
+
+// OpenImage reads an OTA upgrade file
+func OpenImage(name string) (*Image, error) {
+	p, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseImage(p)
+}
+
+// serves the image is an upgrade for a device running version of the image
+// type of manufacturer, with hardware version hw if present
+func (img *Image) serves(addr64 uint64, manufacturer, imageType uint16, version uint32, hw uint16, hasHW bool) bool {
+	h := img.Header
+	if h.ManufacturerCode != manufacturer || h.ImageType != imageType || h.FileVersion <= version {
+		return false
+	}
+	if h.FieldControl&FieldDestination != 0 && h.Destination != addr64 {
+		return false
+	}
+	if hasHW && h.FieldControl&FieldHardwareVersions != 0 {
+		return hw >= h.MinHardwareVersion && hw <= h.MaxHardwareVersion
+	}
+
+	return true
+}
@@ -0,0 +1,458 @@
+// Package ota serves firmware images to Zigbee devices over the ZCL OTA
+// Upgrade cluster.
+package ota
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/internal/rxloop"
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster OTA Upgrade cluster ID
+const Cluster uint16 = 0x0019
+
+// Client to server commands
+const (
+	CommandQueryNextImageRequest byte = 0x01
+	CommandImageBlockRequest     byte = 0x03
+	CommandImagePageRequest      byte = 0x04
+	CommandUpgradeEndRequest     byte = 0x06
+)
+
+// Server to client commands
+const (
+	CommandImageNotify            byte = 0x00
+	CommandQueryNextImageResponse byte = 0x02
+	CommandImageBlockResponse     byte = 0x05
+	CommandUpgradeEndResponse     byte = 0x07
+)
+
+const (
+	// DefaultEndpoint local endpoint the server is reached on
+	DefaultEndpoint byte = 0xE8
+	// DefaultMaxBlockSize largest image block sent, fitting an unfragmented
+	// explicit frame
+	DefaultMaxBlockSize byte = 48
+
+	// notifyJitter Image Notify query jitter, every device queries
+	notifyJitter byte = 100
+	// notifyPayloadVersion Image Notify payload type carrying the
+	// manufacturer code, image type and file version
+	notifyPayloadVersion byte = 0x03
+)
+
+// State a transfer state
+type State byte
+
+// Transfer states
+const (
+	Downloading = State(0)
+	Complete    = State(1)
+	Failed      = State(2)
+)
+
+var stateNames = map[State]string{
+	Downloading: "downloading",
+	Complete:    "complete",
+	Failed:      "failed",
+}
+
+func (s State) String() string {
+	return stateNames[s]
+}
+
+// Progress a device's transfer of an image
+type Progress struct {
+	Addr64           uint64
+	ManufacturerCode uint16
+	ImageType        uint16
+	FileVersion      uint32
+	// Offset the end of the last block sent
+	Offset  uint32
+	Size    uint32
+	State   State
+	Started time.Time
+	Updated time.Time
+}
+
+// EndpointSetter interface for Endpoint setters
+type EndpointSetter interface {
+	SetEndpoint(byte)
+}
+
+// Endpoint helper option function to NewServer, the local endpoint the
+// server is reached on
+func Endpoint(endpoint byte) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(EndpointSetter); ok {
+			s.SetEndpoint(endpoint)
+		}
+	}
+}
+
+// MaxBlockSizeSetter interface for MaxBlockSize setters
+type MaxBlockSizeSetter interface {
+	SetMaxBlockSize(byte)
+}
+
+// MaxBlockSize helper option function to NewServer, the largest image block
+// sent whatever the device requests, a size of 0 is ignored
+func MaxBlockSize(size byte) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(MaxBlockSizeSetter); ok {
+			s.SetMaxBlockSize(size)
+		}
+	}
+}
+
+// ErrorHandlerSetter interface for ErrorHandler setters
+type ErrorHandlerSetter interface {
+	SetErrorHandler(func(error))
+}
+
+// ErrorHandler helper option function to NewServer, receives failures to
+// answer requests
+func ErrorHandler(f func(error)) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(ErrorHandlerSetter); ok {
+			s.SetErrorHandler(f)
+		}
+	}
+}
+
+// Server serves OTA upgrade images, tracking each device's progress. Blocks
+// are served at any offset, so a device resumes an interrupted transfer by
+// requesting the image again from where it stopped. Receiving requests
+// requires the local XBee's AO set to 1.
+type Server struct {
+	xbee         *gobee.XBee
+	client       *zcl.Client
+	endpoint     byte
+	maxBlockSize byte
+	errors       func(error)
+
+	mu       sync.Mutex
+	images   []*Image
+	progress map[uint64]*Progress
+}
+
+// NewServer constructs a Server with no images
+func NewServer(xbee *gobee.XBee, options ...func(interface{})) *Server {
+	s := &Server{
+		xbee:         xbee,
+		client:       zcl.New(xbee),
+		endpoint:     DefaultEndpoint,
+		maxBlockSize: DefaultMaxBlockSize,
+		progress:     make(map[uint64]*Progress),
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(s)
+	}
+
+	return s
+}
+
+// SetEndpoint satisfy EndpointSetter interface
+func (s *Server) SetEndpoint(endpoint byte) {
+	s.endpoint = endpoint
+}
+
+// SetMaxBlockSize satisfy MaxBlockSizeSetter interface
+func (s *Server) SetMaxBlockSize(size byte) {
+	if size == 0 {
+		return
+	}
+
+	s.maxBlockSize = size
+}
+
+// SetErrorHandler satisfy ErrorHandlerSetter interface
+func (s *Server) SetErrorHandler(f func(error)) {
+	s.errors = f
+}
+
+// Add adds an image to serve
+func (s *Server) Add(img *Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.images = append(s.images, img)
+}
+
+// Progress returns the progress of the device's latest transfer
+func (s *Server) Progress(addr64 uint64) (Progress, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.progress[addr64]
+	if !ok {
+		return Progress{}, false
+	}
+
+	return *p, true
+}
+
+// Notify sends an Image Notify for img to dst, prompting it to query for
+// the next image
+func (s *Server) Notify(ctx context.Context, dst zcl.Address, img *Image) error {
+	p := []byte{notifyPayloadVersion, notifyJitter, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint16(p[2:], img.ManufacturerCode)
+	binary.LittleEndian.PutUint16(p[4:], img.ImageType)
+	binary.LittleEndian.PutUint32(p[6:], img.FileVersion)
+
+	return s.client.Send(ctx, dst, Cluster, &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   zcl.FrameCluster,
+			Direction:              zcl.ServerToClient,
+			DisableDefaultResponse: true,
+			Seq:                    s.client.NextSeq(),
+			Command:                CommandImageNotify,
+		},
+		Payload: p,
+	})
+}
+
+// Serve answers OTA requests until ctx is done
+func (s *Server) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return s.loop(ctx, s.subscribe(ctx))
+}
+
+// subscribe to the requests for the server's endpoint
+func (s *Server) subscribe(ctx context.Context) <-chan rx.Frame {
+	return s.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		e, ok := f.(*rx.ZBExplicit)
+		return ok && e.ClusterID() == Cluster && e.DstEP() == s.endpoint
+	})
+}
+
+// loop answers requests until they are closed
+func (s *Server) loop(ctx context.Context, requests <-chan rx.Frame) error {
+	rxloop.Each(requests, func(f rx.Frame) func() {
+		return func() {
+			err := s.serve(ctx, f.(*rx.ZBExplicit))
+			if err != nil && s.errors != nil && ctx.Err() == nil {
+				s.errors(err)
+			}
+		}
+	})
+
+	return ctx.Err()
+}
+
+// serve answers a request
+func (s *Server) serve(ctx context.Context, e *rx.ZBExplicit) error {
+	f, err := zcl.Parse(e.Data())
+	if err != nil || f.Type != zcl.FrameCluster || f.Direction != zcl.ClientToServer {
+		return err
+	}
+
+	src := zcl.Address{
+		Addr64:      e.Addr64(),
+		Addr16:      e.Addr16(),
+		SrcEndpoint: e.DstEP(),
+		DstEndpoint: e.SrcEP(),
+		ProfileID:   e.ProfileID(),
+	}
+
+	var command byte
+	var payload []byte
+	var status zcl.Status
+	switch f.Command {
+	case CommandQueryNextImageRequest:
+		command = CommandQueryNextImageResponse
+		payload, status = s.queryNextImage(e.Addr64(), f.Payload)
+	case CommandImageBlockRequest:
+		command = CommandImageBlockResponse
+		payload, status = s.imageBlock(e.Addr64(), f.Payload)
+	case CommandUpgradeEndRequest:
+		command = CommandUpgradeEndResponse
+		payload, status = s.upgradeEnd(e.Addr64(), f.Payload)
+	default:
+		status = zcl.StatusUnsupportedClusterCommand
+	}
+
+	if payload == nil {
+		if status == zcl.StatusSuccess && f.DisableDefaultResponse {
+			return nil
+		}
+
+		return s.reply(ctx, src, f, zcl.FrameGeneral, zcl.CommandDefaultResponse, zcl.EncodeDefaultResponse(f.Command, status))
+	}
+
+	return s.reply(ctx, src, f, zcl.FrameCluster, command, payload)
+}
+
+// reply sends a response to request f from src
+func (s *Server) reply(ctx context.Context, src zcl.Address, f *zcl.Frame, t zcl.FrameType, command byte, payload []byte) error {
+	return s.client.Send(ctx, src, Cluster, &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   t,
+			Direction:              zcl.ServerToClient,
+			DisableDefaultResponse: true,
+			Seq:                    f.Seq,
+			Command:                command,
+		},
+		Payload: payload,
+	})
+}
+
+// image the newest image upgrading the device, nil if there is none
+func (s *Server) image(addr64 uint64, manufacturer, imageType uint16, version uint32, hw uint16, hasHW bool) *Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var newest *Image
+	for _, img := range s.images {
+		if img.serves(addr64, manufacturer, imageType, version, hw, hasHW) && (newest == nil || img.FileVersion > newest.FileVersion) {
+			newest = img
+		}
+	}
+
+	return newest
+}
+
+// version the image of the manufacturer, type and file version
+func (s *Server) version(manufacturer, imageType uint16, version uint32) *Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, img := range s.images {
+		if img.ManufacturerCode == manufacturer && img.ImageType == imageType && img.FileVersion == version {
+			return img
+		}
+	}
+
+	return nil
+}
+
+// queryNextImage Query Next Image Response payload
+func (s *Server) queryNextImage(addr64 uint64, p []byte) ([]byte, zcl.Status) {
+	if len(p) < 9 {
+		return nil, zcl.StatusMalformedCommand
+	}
+
+	hasHW := p[0]&0x01 != 0
+	if hasHW && len(p) < 11 {
+		return nil, zcl.StatusMalformedCommand
+	}
+
+	var hw uint16
+	if hasHW {
+		hw = binary.LittleEndian.Uint16(p[9:])
+	}
+
+	img := s.image(addr64, binary.LittleEndian.Uint16(p[1:]), binary.LittleEndian.Uint16(p[3:]), binary.LittleEndian.Uint32(p[5:]), hw, hasHW)
+	if img == nil {
+		return []byte{byte(zcl.StatusNoImageAvailable)}, zcl.StatusSuccess
+	}
+
+	s.track(addr64, img, 0)
+
+	r := make([]byte, 13)
+	r[0] = byte(zcl.StatusSuccess)
+	binary.LittleEndian.PutUint16(r[1:], img.ManufacturerCode)
+	binary.LittleEndian.PutUint16(r[3:], img.ImageType)
+	binary.LittleEndian.PutUint32(r[5:], img.FileVersion)
+	binary.LittleEndian.PutUint32(r[9:], img.TotalSize)
+
+	return r, zcl.StatusSuccess
+}
+
+// imageBlock Image Block Response payload
+func (s *Server) imageBlock(addr64 uint64, p []byte) ([]byte, zcl.Status) {
+	if len(p) < 14 {
+		return nil, zcl.StatusMalformedCommand
+	}
+
+	img := s.version(binary.LittleEndian.Uint16(p[1:]), binary.LittleEndian.Uint16(p[3:]), binary.LittleEndian.Uint32(p[5:]))
+	offset := binary.LittleEndian.Uint32(p[9:])
+	if img == nil || offset > img.TotalSize {
+		return []byte{byte(zcl.StatusAbort)}, zcl.StatusSuccess
+	}
+
+	size := p[13]
+	if size > s.maxBlockSize {
+		size = s.maxBlockSize
+	}
+	if remaining := img.TotalSize - offset; remaining < uint32(size) {
+		size = byte(remaining)
+	}
+
+	s.track(addr64, img, offset+uint32(size))
+
+	r := make([]byte, 14, 14+int(size))
+	r[0] = byte(zcl.StatusSuccess)
+	copy(r[1:], p[1:13])
+	r[13] = size
+
+	return append(r, img.Data[offset:offset+uint32(size)]...), zcl.StatusSuccess
+}
+
+// upgradeEnd Upgrade End Response payload upgrading immediately, nil and
+// the status of a Default Response when the device failed the download
+func (s *Server) upgradeEnd(addr64 uint64, p []byte) ([]byte, zcl.Status) {
+	if len(p) < 9 {
+		return nil, zcl.StatusMalformedCommand
+	}
+
+	state := Complete
+	if zcl.Status(p[0]) != zcl.StatusSuccess {
+		state = Failed
+	}
+
+	s.mu.Lock()
+	if progress, ok := s.progress[addr64]; ok {
+		progress.State = state
+		progress.Updated = time.Now()
+	}
+	s.mu.Unlock()
+
+	if state == Failed {
+		return nil, zcl.StatusSuccess
+	}
+
+	// a current and upgrade time of zero upgrades now
+	return append(append([]byte(nil), p[1:9]...), 0, 0, 0, 0, 0, 0, 0, 0), zcl.StatusSuccess
+}
+
+// track records the device's transfer of img reaching offset, a new image
+// restarts its progress
+func (s *Server) track(addr64 uint64, img *Image, offset uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	p, ok := s.progress[addr64]
+	if !ok || p.ManufacturerCode != img.ManufacturerCode || p.ImageType != img.ImageType || p.FileVersion != img.FileVersion || p.State != Downloading {
+		p = &Progress{
+			Addr64:           addr64,
+			ManufacturerCode: img.ManufacturerCode,
+			ImageType:        img.ImageType,
+			FileVersion:      img.FileVersion,
+			Size:             img.TotalSize,
+			State:            Downloading,
+			Started:          now,
+		}
+		s.progress[addr64] = p
+	}
+
+	if offset > p.Offset {
+		p.Offset = offset
+	}
+	p.Updated = now
+}
@@ -0,0 +1,291 @@
+package ota
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/internal/radiotest"
+	"github.com/pauleyj/gobee/zcl"
+)
+
+const testAddr64 uint64 = 0x0013A20040522BAA
+
+// testImage an OTA file of the header and size bytes of body
+func testImage(t *testing.T, h Header, size int) *Image {
+	h.Length = uint16(len(h.Bytes()))
+	h.TotalSize = uint32(int(h.Length) + size)
+
+	p := append(h.Bytes(), bytes.Repeat([]byte{0xA5}, size)...)
+	img, err := ParseImage(p)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	return img
+}
+
+func TestParseHeader(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name   string
+		header Header
+	}{
+		{"Minimal", Header{Version: 0x0100, ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 0x00000102, StackVersion: 2, String: "gobee"}},
+		{"Optional", Header{
+			Version:                   0x0100,
+			FieldControl:              FieldSecurityCredential | FieldDestination | FieldHardwareVersions,
+			ManufacturerCode:          0x101E,
+			ImageType:                 0x0001,
+			FileVersion:               0x00000102,
+			SecurityCredentialVersion: 1,
+			Destination:               testAddr64,
+			MinHardwareVersion:        1,
+			MaxHardwareVersion:        3,
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			img := testImage(t, tt.header, 10)
+
+			expected := tt.header
+			expected.Length = img.Length
+			expected.TotalSize = img.TotalSize
+			if !reflect.DeepEqual(img.Header, expected) {
+				t.Fatalf("Expected %+v, but got %+v", expected, img.Header)
+			}
+		})
+	}
+}
+
+func TestParseImage_Invalid(t *testing.T) {
+	img := testImage(t, Header{Version: 0x0100}, 10)
+
+	if _, err := ParseImage(img.Data[:len(img.Data)-1]); err != ErrImage {
+		t.Fatalf("Expected ErrImage for truncated image, but got %v", err)
+	}
+	if _, err := ParseImage(append([]byte{0x00}, img.Data[1:]...)); err != ErrImage {
+		t.Fatalf("Expected ErrImage for bad magic, but got %v", err)
+	}
+}
+
+// device exchanges OTA requests with a server on a fake radio
+type device struct {
+	t       *testing.T
+	radio   *radiotest.Radio
+	replies chan *zcl.Frame
+	seq     byte
+}
+
+func serve(t *testing.T, images ...*Image) (*Server, *device) {
+	d := &device{t: t, replies: make(chan *zcl.Frame, 4)}
+
+	xbee, radio := radiotest.New(func(p []byte) [][]byte {
+		e, ok := radiotest.ParseExplicit(p)
+		if !ok || e.ClusterID != Cluster || e.DstEndpoint != 0x01 || e.SrcEndpoint != DefaultEndpoint {
+			t.Fatalf("Expected OTA frame to endpoint 1, but got % x", p)
+		}
+
+		f, err := zcl.Parse(e.Data)
+		if err != nil {
+			t.Fatalf("Expected ZCL frame, but got %v", err)
+		}
+		d.replies <- f
+
+		return [][]byte{radiotest.TXStatus(e.ID, 0)}
+	})
+	d.radio = radio
+
+	s := NewServer(xbee, MaxBlockSize(32))
+	for _, img := range images {
+		s.Add(img)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	requests := s.subscribe(ctx)
+	go s.loop(ctx, requests)
+
+	return s, d
+}
+
+// request sends an OTA command and returns the server's reply
+func (d *device) request(command byte, payload []byte) *zcl.Frame {
+	d.seq++
+	f := &zcl.Frame{Header: zcl.Header{Type: zcl.FrameCluster, Seq: d.seq, Command: command}, Payload: payload}
+
+	d.radio.Send(radiotest.Explicit{
+		Addr64:      testAddr64,
+		Addr16:      0x1234,
+		SrcEndpoint: 0x01,
+		DstEndpoint: DefaultEndpoint,
+		ClusterID:   Cluster,
+		ProfileID:   zcl.ProfileHomeAutomation,
+		Data:        f.Bytes(),
+	}.Received())
+
+	select {
+	case r := <-d.replies:
+		if r.Seq != d.seq || r.Direction != zcl.ServerToClient {
+			d.t.Fatalf("Expected reply to sequence %d, but got %+v", d.seq, r.Header)
+		}
+		return r
+	case <-time.After(time.Second):
+		d.t.Fatal("Expected reply")
+	}
+
+	return nil
+}
+
+func query(manufacturer, imageType uint16, version uint32) []byte {
+	p := make([]byte, 9)
+	binary.LittleEndian.PutUint16(p[1:], manufacturer)
+	binary.LittleEndian.PutUint16(p[3:], imageType)
+	binary.LittleEndian.PutUint32(p[5:], version)
+	return p
+}
+
+func block(img *Image, offset uint32, size byte) []byte {
+	p := make([]byte, 14)
+	binary.LittleEndian.PutUint16(p[1:], img.ManufacturerCode)
+	binary.LittleEndian.PutUint16(p[3:], img.ImageType)
+	binary.LittleEndian.PutUint32(p[5:], img.FileVersion)
+	binary.LittleEndian.PutUint32(p[9:], offset)
+	p[13] = size
+	return p
+}
+
+func TestServer_QueryNextImage(t *testing.T) {
+	older := testImage(t, Header{ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 2}, 10)
+	newer := testImage(t, Header{ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 3}, 10)
+	_, d := serve(t, older, newer)
+
+	r := d.request(CommandQueryNextImageRequest, query(0x101E, 0x0001, 1))
+	expected := []byte{0x00, 0x1E, 0x10, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, byte(newer.TotalSize), 0x00, 0x00, 0x00}
+	if r.Command != CommandQueryNextImageResponse || !reflect.DeepEqual(r.Payload, expected) {
+		t.Fatalf("Expected % x, but got %+v % x", expected, r.Header, r.Payload)
+	}
+
+	r = d.request(CommandQueryNextImageRequest, query(0x101E, 0x0001, 3))
+	if !reflect.DeepEqual(r.Payload, []byte{byte(zcl.StatusNoImageAvailable)}) {
+		t.Fatalf("Expected no image available, but got % x", r.Payload)
+	}
+}
+
+func TestServer_Transfer(t *testing.T) {
+	img := testImage(t, Header{ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 2}, 40)
+	s, d := serve(t, img)
+
+	d.request(CommandQueryNextImageRequest, query(0x101E, 0x0001, 1))
+
+	var received []byte
+	for offset := uint32(0); offset < img.TotalSize; {
+		r := d.request(CommandImageBlockRequest, block(img, offset, 64))
+		if r.Command != CommandImageBlockResponse || r.Payload[0] != byte(zcl.StatusSuccess) {
+			t.Fatalf("Expected image block, but got %+v % x", r.Header, r.Payload)
+		}
+		if size := r.Payload[13]; size > 32 || int(size) != len(r.Payload)-14 {
+			t.Fatalf("Expected block of at most 32 bytes, but got %d", size)
+		}
+
+		received = append(received, r.Payload[14:]...)
+		offset += uint32(r.Payload[13])
+
+		if p, _ := s.Progress(testAddr64); p.Offset != offset || p.State != Downloading {
+			t.Fatalf("Expected progress at %d, but got %+v", offset, p)
+		}
+	}
+
+	if !bytes.Equal(received, img.Data) {
+		t.Fatal("Expected received blocks to equal the image")
+	}
+
+	end := append([]byte{byte(zcl.StatusSuccess)}, block(img, 0, 0)[1:9]...)
+	r := d.request(CommandUpgradeEndRequest, end)
+	if expected := append(end[1:], 0, 0, 0, 0, 0, 0, 0, 0); r.Command != CommandUpgradeEndResponse || !reflect.DeepEqual(r.Payload, expected) {
+		t.Fatalf("Expected % x, but got %+v % x", expected, r.Header, r.Payload)
+	}
+
+	if p, _ := s.Progress(testAddr64); p.State != Complete || p.Offset != img.TotalSize {
+		t.Fatalf("Expected complete progress, but got %+v", p)
+	}
+}
+
+func TestServer_ImageBlock_Abort(t *testing.T) {
+	img := testImage(t, Header{ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 2}, 10)
+	_, d := serve(t)
+
+	r := d.request(CommandImageBlockRequest, block(img, 0, 32))
+	if !reflect.DeepEqual(r.Payload, []byte{byte(zcl.StatusAbort)}) {
+		t.Fatalf("Expected abort, but got % x", r.Payload)
+	}
+}
+
+func TestServer_Notify(t *testing.T) {
+	img := testImage(t, Header{ManufacturerCode: 0x101E, ImageType: 0x0001, FileVersion: 2}, 10)
+	s, d := serve(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dst := zcl.Address{Addr64: testAddr64, Addr16: 0x1234, SrcEndpoint: DefaultEndpoint, DstEndpoint: 0x01, ProfileID: zcl.ProfileHomeAutomation}
+	if err := s.Notify(ctx, dst, img); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	r := <-d.replies
+	expected := []byte{0x03, 100, 0x1E, 0x10, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00}
+	if r.Command != CommandImageNotify || !reflect.DeepEqual(r.Payload, expected) {
+		t.Fatalf("Expected % x, but got %+v % x", expected, r.Header, r.Payload)
+	}
+}
+
+func TestServer_ErrorHandler(t *testing.T) {
+	xbee, radio := radiotest.New(func(p []byte) [][]byte {
+		e, _ := radiotest.ParseExplicit(p)
+		return [][]byte{radiotest.TXStatus(e.ID, 0x21)}
+	})
+
+	errs := make(chan error, 1)
+	s := NewServer(xbee, MaxBlockSize(0), ErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if s.maxBlockSize != DefaultMaxBlockSize {
+		t.Fatalf("Expected block size 0 ignored, but got %d", s.maxBlockSize)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := s.subscribe(ctx)
+	go s.loop(ctx, requests)
+
+	f := &zcl.Frame{Header: zcl.Header{Type: zcl.FrameCluster, Seq: 1, Command: CommandQueryNextImageRequest}, Payload: query(0x101E, 0x0001, 1)}
+	radio.Send(radiotest.Explicit{
+		Addr64:      testAddr64,
+		Addr16:      0x1234,
+		SrcEndpoint: 0x01,
+		DstEndpoint: DefaultEndpoint,
+		ClusterID:   Cluster,
+		ProfileID:   zcl.ProfileHomeAutomation,
+		Data:        f.Bytes(),
+	}.Received())
+
+	select {
+	case err := <-errs:
+		if e, ok := err.(*gobee.DeliveryError); !ok || e.Status != 0x21 {
+			t.Fatalf("Expected delivery error 0x21, but got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected error")
+	}
+}
@@ -0,0 +1,132 @@
+package rangetest
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const (
+	// LinkTestEndpoint Digi device object endpoint, where the link test runs
+	LinkTestEndpoint byte = 0xE6
+	// LinkTestCluster asks a node to test its link to another node
+	LinkTestCluster uint16 = 0x0014
+	// LinkTestResultCluster reports a link test's result
+	LinkTestResultCluster uint16 = 0x0094
+
+	// linkTestResultLength bytes in a link test result
+	linkTestResultLength = 21
+)
+
+// link test result codes
+const (
+	linkTestSuccess           byte = 0x00
+	linkTestInvalidParameters byte = 0x03
+)
+
+var (
+	// ErrLinkTestResult malformed link test result
+	ErrLinkTestResult = errors.New("invalid link test result")
+	// ErrLinkTestParameters the node rejected the link test's parameters
+	ErrLinkTestParameters = errors.New("link test invalid parameters")
+)
+
+// LinkTestResult a node's report of the link test it ran to Destination
+type LinkTestResult struct {
+	Destination uint64
+	PayloadSize uint16
+	Iterations  uint16
+	Success     uint16
+	Retries     uint16
+	// MaxRetries most MAC retries any one packet needed
+	MaxRetries byte
+	// MaxRSSI, MinRSSI and AvgRSSI of the acknowledgements in dBm
+	MaxRSSI int
+	MinRSSI int
+	AvgRSSI int
+}
+
+// ParseLinkTestResult decodes a link test result
+func ParseLinkTestResult(p []byte) (*LinkTestResult, error) {
+	if len(p) != linkTestResultLength {
+		return nil, ErrLinkTestResult
+	}
+
+	switch p[16] {
+	case linkTestSuccess:
+	case linkTestInvalidParameters:
+		return nil, ErrLinkTestParameters
+	default:
+		return nil, ErrLinkTestResult
+	}
+
+	return &LinkTestResult{
+		Destination: binary.BigEndian.Uint64(p),
+		PayloadSize: binary.BigEndian.Uint16(p[8:]),
+		Iterations:  binary.BigEndian.Uint16(p[10:]),
+		Success:     binary.BigEndian.Uint16(p[12:]),
+		Retries:     binary.BigEndian.Uint16(p[14:]),
+		MaxRetries:  p[17],
+		MaxRSSI:     -int(p[18]),
+		MinRSSI:     -int(p[19]),
+		AvgRSSI:     -int(p[20]),
+	}, nil
+}
+
+// Stats the result as test statistics, the link test does not time packets
+func (r *LinkTestResult) Stats() Stats {
+	s := Stats{Sent: int(r.Iterations), Received: int(r.Success)}
+	if s.Sent > 0 {
+		s.Success = 100 * float64(s.Received) / float64(s.Sent)
+	}
+	if s.Received > 0 {
+		s.MinRSSI, s.AvgRSSI, s.MaxRSSI = r.MinRSSI, r.AvgRSSI, r.MaxRSSI
+	}
+
+	return s
+}
+
+// LinkTest asks the node at addr64/addr16 to send the Tester's count of
+// packets of its payload size to dst, and waits for the node's result. Large
+// counts take a while, ctx should allow for them.
+func (t *Tester) LinkTest(ctx context.Context, addr64 uint64, addr16 uint16, dst uint64) (*LinkTestResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := t.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		r, ok := f.(*rx.ZBExplicit)
+		return ok && r.Addr64() == addr64 &&
+			r.ProfileID() == Profile && r.ClusterID() == LinkTestResultCluster &&
+			len(r.Data()) >= 8 && binary.BigEndian.Uint64(r.Data()) == dst
+	})
+
+	data := make([]byte, 12)
+	binary.BigEndian.PutUint64(data, dst)
+	binary.BigEndian.PutUint16(data[8:], uint16(t.size))
+	binary.BigEndian.PutUint16(data[10:], uint16(t.count))
+
+	err := t.xbee.SendExplicit(ctx,
+		tx.Addr64(addr64),
+		tx.Addr16(addr16),
+		tx.SrcEP(LinkTestEndpoint),
+		tx.DstEP(LinkTestEndpoint),
+		tx.ClusterID(LinkTestCluster),
+		tx.ProfileID(Profile),
+		tx.Data(data))
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case f, ok := <-results:
+		if !ok {
+			return nil, ctx.Err()
+		}
+		return ParseLinkTestResult(f.(*rx.ZBExplicit).Data())
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
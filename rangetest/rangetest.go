@@ -0,0 +1,327 @@
+// Package rangetest measures the link between the local XBee and a remote
+// node for site surveys, with the XBee loopback and link test clusters.
+// Receiving their replies requires the local XBee's AO set to 1.
+package rangetest
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const (
+	// Profile Digi's profile for the loopback and link test clusters
+	Profile uint16 = 0xC105
+	// LoopbackEndpoint Digi data endpoint, where the loopback cluster echoes
+	LoopbackEndpoint byte = 0xE8
+	// LoopbackCluster echoes received data back to its source
+	LoopbackCluster uint16 = 0x0012
+
+	// DefaultCount packets sent by a test
+	DefaultCount = 100
+	// DefaultPayloadSize bytes in each packet
+	DefaultPayloadSize = 32
+	// DefaultTimeout time each packet waits for its echo
+	DefaultTimeout = 2 * time.Second
+
+	// minPayloadSize room for the sequence number
+	minPayloadSize = 2
+)
+
+// CountSetter interface for Count setters
+type CountSetter interface {
+	SetCount(int)
+}
+
+// Count helper option function to New, packets sent by a test
+func Count(n int) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(CountSetter); ok {
+			s.SetCount(n)
+		}
+	}
+}
+
+// PayloadSizeSetter interface for PayloadSize setters
+type PayloadSizeSetter interface {
+	SetPayloadSize(int)
+}
+
+// PayloadSize helper option function to New, bytes in each packet
+func PayloadSize(size int) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(PayloadSizeSetter); ok {
+			s.SetPayloadSize(size)
+		}
+	}
+}
+
+// IntervalSetter interface for Interval setters
+type IntervalSetter interface {
+	SetInterval(time.Duration)
+}
+
+// Interval helper option function to New, pause between loopback packets
+func Interval(interval time.Duration) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(IntervalSetter); ok {
+			s.SetInterval(interval)
+		}
+	}
+}
+
+// TimeoutSetter interface for Timeout setters
+type TimeoutSetter interface {
+	SetTimeout(time.Duration)
+}
+
+// Timeout helper option function to New, time each loopback packet waits for
+// its echo
+func Timeout(timeout time.Duration) func(interface{}) {
+	return func(i interface{}) {
+		if s, ok := i.(TimeoutSetter); ok {
+			s.SetTimeout(timeout)
+		}
+	}
+}
+
+// Packet the outcome of one loopback packet
+type Packet struct {
+	Seq     uint16
+	Success bool
+	// RSSI of the echo's last hop in dBm, read with DB
+	RSSI int
+	// RTT from transmitting the packet to receiving its echo
+	RTT time.Duration
+	// Err the packet was not delivered or its echo did not arrive
+	Err error
+}
+
+// Stats summarizes a test
+type Stats struct {
+	Sent     int
+	Received int
+	// Success percentage of packets received
+	Success float64
+	// MinRSSI, AvgRSSI and MaxRSSI in dBm, zero when nothing was received
+	MinRSSI int
+	AvgRSSI int
+	MaxRSSI int
+	// RTT percentiles of the packets received
+	RTT50 time.Duration
+	RTT90 time.Duration
+	RTT99 time.Duration
+}
+
+// Result a loopback test's packets and their statistics
+type Result struct {
+	Packets []Packet
+	Stats
+}
+
+// Summarize computes the statistics of packets
+func Summarize(packets []Packet) Stats {
+	s := Stats{Sent: len(packets)}
+
+	var rssi int
+	rtts := make([]time.Duration, 0, len(packets))
+	for _, p := range packets {
+		if !p.Success {
+			continue
+		}
+
+		if s.Received == 0 || p.RSSI < s.MinRSSI {
+			s.MinRSSI = p.RSSI
+		}
+		if s.Received == 0 || p.RSSI > s.MaxRSSI {
+			s.MaxRSSI = p.RSSI
+		}
+		rssi += p.RSSI
+		rtts = append(rtts, p.RTT)
+		s.Received++
+	}
+
+	if s.Sent > 0 {
+		s.Success = 100 * float64(s.Received) / float64(s.Sent)
+	}
+	if s.Received > 0 {
+		s.AvgRSSI = int(math.Round(float64(rssi) / float64(s.Received)))
+	}
+
+	sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+	s.RTT50 = percentile(rtts, 50)
+	s.RTT90 = percentile(rtts, 90)
+	s.RTT99 = percentile(rtts, 99)
+
+	return s
+}
+
+// percentile nearest rank percentile p of sorted
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// Tester runs range tests through the local XBee
+type Tester struct {
+	xbee     *gobee.XBee
+	count    int
+	size     int
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// New constructs a Tester
+func New(xbee *gobee.XBee, options ...func(interface{})) *Tester {
+	t := &Tester{
+		xbee:    xbee,
+		count:   DefaultCount,
+		size:    DefaultPayloadSize,
+		timeout: DefaultTimeout,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(t)
+	}
+
+	if t.size < minPayloadSize {
+		t.size = minPayloadSize
+	}
+
+	return t
+}
+
+// SetCount satisfy CountSetter interface
+func (t *Tester) SetCount(n int) {
+	t.count = n
+}
+
+// SetPayloadSize satisfy PayloadSizeSetter interface
+func (t *Tester) SetPayloadSize(size int) {
+	t.size = size
+}
+
+// SetInterval satisfy IntervalSetter interface
+func (t *Tester) SetInterval(interval time.Duration) {
+	t.interval = interval
+}
+
+// SetTimeout satisfy TimeoutSetter interface
+func (t *Tester) SetTimeout(timeout time.Duration) {
+	t.timeout = timeout
+}
+
+// Loopback sends packets to the loopback cluster of the node at
+// addr64/addr16, one at a time, timing each echo and reading its RSSI. Lost
+// packets are recorded and the test continues; if ctx is done or the local
+// XBee fails, the packets sent so far are returned along with the error.
+func (t *Tester) Loopback(ctx context.Context, addr64 uint64, addr16 uint16) (*Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	echoes := t.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		e, ok := f.(*rx.ZBExplicit)
+		return ok && e.Addr64() == addr64 &&
+			e.ProfileID() == Profile && e.ClusterID() == LoopbackCluster &&
+			e.SrcEP() == LoopbackEndpoint && len(e.Data()) >= minPayloadSize
+	})
+
+	r := &Result{}
+	for i := 0; i < t.count; i++ {
+		if i > 0 && t.interval > 0 {
+			select {
+			case <-time.After(t.interval):
+			case <-ctx.Done():
+				r.Stats = Summarize(r.Packets)
+				return r, ctx.Err()
+			}
+		}
+
+		p, err := t.loopback(ctx, echoes, addr64, addr16, uint16(i))
+		if err != nil {
+			r.Stats = Summarize(r.Packets)
+			return r, err
+		}
+		r.Packets = append(r.Packets, p)
+	}
+
+	r.Stats = Summarize(r.Packets)
+
+	return r, nil
+}
+
+// loopback sends packet seq and waits for its echo, echoes of earlier
+// packets that arrive late are discarded
+func (t *Tester) loopback(ctx context.Context, echoes <-chan rx.Frame, addr64 uint64, addr16 uint16, seq uint16) (Packet, error) {
+	p := Packet{Seq: seq}
+
+	data := make([]byte, t.size)
+	binary.BigEndian.PutUint16(data, seq)
+	for i := minPayloadSize; i < len(data); i++ {
+		data[i] = byte(i)
+	}
+
+	wait, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := t.xbee.SendExplicit(wait,
+		tx.Addr64(addr64),
+		tx.Addr16(addr16),
+		tx.SrcEP(LoopbackEndpoint),
+		tx.DstEP(LoopbackEndpoint),
+		tx.ClusterID(LoopbackCluster),
+		tx.ProfileID(Profile),
+		tx.Data(data))
+
+	for err == nil {
+		select {
+		case f, ok := <-echoes:
+			if !ok {
+				return p, ctx.Err()
+			}
+			if binary.BigEndian.Uint16(f.(*rx.ZBExplicit).Data()) != seq {
+				continue
+			}
+
+			p.RTT = time.Since(start)
+			p.Success = true
+
+			v, err := t.xbee.Get(ctx, at.RSSI)
+			if err != nil {
+				return p, err
+			}
+			p.RSSI = -int(v.(uint8))
+
+			return p, nil
+		case <-wait.Done():
+			err = wait.Err()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return p, ctx.Err()
+	}
+	p.Err = err
+
+	return p, nil
+}
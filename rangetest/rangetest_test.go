@@ -0,0 +1,155 @@
+package rangetest
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/internal/radiotest"
+)
+
+const testAddr64 uint64 = 0x0013A20040522BAA
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	ms := time.Millisecond
+	var tests = []struct {
+		name     string
+		packets  []Packet
+		expected Stats
+	}{
+		{"Empty", nil, Stats{}},
+		{"Lost", []Packet{{Seq: 0}, {Seq: 1}}, Stats{Sent: 2}},
+		{"Mixed", []Packet{
+			{Seq: 0, Success: true, RSSI: -40, RTT: 30 * ms},
+			{Seq: 1, Success: true, RSSI: -55, RTT: 10 * ms},
+			{Seq: 2},
+			{Seq: 3, Success: true, RSSI: -48, RTT: 20 * ms},
+		}, Stats{
+			Sent: 4, Received: 3, Success: 75,
+			MinRSSI: -55, AvgRSSI: -48, MaxRSSI: -40,
+			RTT50: 20 * ms, RTT90: 30 * ms, RTT99: 30 * ms,
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if s := Summarize(tt.packets); !reflect.DeepEqual(s, tt.expected) {
+				t.Fatalf("Expected %+v, but got %+v", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestTester_Loopback(t *testing.T) {
+	xbee, _ := radiotest.New(func(p []byte) [][]byte {
+		if p[0] == 0x08 {
+			if string(p[2:4]) != "DB" {
+				t.Fatalf("Expected DB, but got %s", p[2:4])
+			}
+			return [][]byte{{0x88, p[1], p[2], p[3], 0x00, 0x2D}}
+		}
+
+		e, ok := radiotest.ParseExplicit(p)
+		if !ok || e.Addr64 != testAddr64 || e.ClusterID != LoopbackCluster || e.ProfileID != Profile ||
+			e.SrcEndpoint != LoopbackEndpoint || e.DstEndpoint != LoopbackEndpoint || len(e.Data) != 8 {
+			t.Fatalf("Expected loopback frame, but got % x", p)
+		}
+
+		// lose the echo of packet 2
+		if binary.BigEndian.Uint16(e.Data) == 2 {
+			return [][]byte{radiotest.TXStatus(e.ID, 0)}
+		}
+		return [][]byte{radiotest.TXStatus(e.ID, 0), e.Reply(LoopbackCluster, e.Data)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r, err := New(xbee, Count(4), PayloadSize(8), Timeout(50*time.Millisecond)).Loopback(ctx, testAddr64, 0x1234)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if len(r.Packets) != 4 {
+		t.Fatalf("Expected 4 packets, but got %d", len(r.Packets))
+	}
+	for i, p := range r.Packets {
+		if lost := i == 2; p.Seq != uint16(i) || p.Success == lost || (p.Err != nil) != lost {
+			t.Fatalf("Expected packet %d lost %t, but got %+v", i, lost, p)
+		}
+	}
+
+	if r.Sent != 4 || r.Received != 3 || r.Success != 75 || r.MinRSSI != -45 || r.AvgRSSI != -45 || r.MaxRSSI != -45 || r.RTT99 == 0 {
+		t.Fatalf("Expected 3 of 4 received at -45 dBm, but got %+v", r.Stats)
+	}
+}
+
+func TestTester_LinkTest(t *testing.T) {
+	const dst uint64 = 0x0013A20040A1B2C3
+
+	request := make(chan radiotest.Explicit, 1)
+	xbee, radio := radiotest.New(func(p []byte) [][]byte {
+		e, ok := radiotest.ParseExplicit(p)
+		if !ok {
+			t.Fatalf("Expected explicit frame, but got % x", p)
+		}
+		request <- e
+		return [][]byte{radiotest.TXStatus(e.ID, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go func() {
+		e := <-request
+		result := append(append([]byte(nil), e.Data...),
+			0x00, 0x09, // success
+			0x00, 0x02, // retries
+			0x00,             // success
+			0x01,             // max retries
+			0x28, 0x3C, 0x30) // max, min, avg RSSI
+		radio.Send(e.Reply(LinkTestResultCluster, result))
+	}()
+
+	r, err := New(xbee, Count(10), PayloadSize(20)).LinkTest(ctx, testAddr64, 0x1234, dst)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := &LinkTestResult{
+		Destination: dst,
+		PayloadSize: 20,
+		Iterations:  10,
+		Success:     9,
+		Retries:     2,
+		MaxRetries:  1,
+		MaxRSSI:     -40,
+		MinRSSI:     -60,
+		AvgRSSI:     -48,
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, r)
+	}
+	if s := r.Stats(); s.Success != 90 || s.AvgRSSI != -48 {
+		t.Fatalf("Expected 90%% success at -48 dBm, but got %+v", s)
+	}
+}
+
+func TestParseLinkTestResult_Invalid(t *testing.T) {
+	p := make([]byte, linkTestResultLength)
+	p[16] = linkTestInvalidParameters
+
+	if _, err := ParseLinkTestResult(p); err != ErrLinkTestParameters {
+		t.Fatalf("Expected error %v, but got %v", ErrLinkTestParameters, err)
+	}
+	if _, err := ParseLinkTestResult(p[:20]); err != ErrLinkTestResult {
+		t.Fatalf("Expected error %v, but got %v", ErrLinkTestResult, err)
+	}
+}
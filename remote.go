@@ -0,0 +1,73 @@
+package gobee
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// RemoteDevice a remote XBee reached through the local XBee
+type RemoteDevice struct {
+	xbee   *XBee
+	addr64 uint64
+
+	mu      sync.Mutex
+	variant Variant
+	// known the variant was given or read, even if unknown
+	known bool
+}
+
+// Remote constructs a RemoteDevice for the remote XBee at addr, options such
+// as HardwareVariant apply to the remote device
+func (x *XBee) Remote(addr uint64, options ...func(interface{})) *RemoteDevice {
+	d := &RemoteDevice{
+		xbee:   x,
+		addr64: addr,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(d)
+	}
+
+	return d
+}
+
+// SetVariant satisfy VariantSetter interface
+func (d *RemoteDevice) SetVariant(variant Variant) {
+	d.mu.Lock()
+	d.variant = variant
+	d.known = true
+	d.mu.Unlock()
+}
+
+// Variant hardware variant of the remote XBee, read once from its hardware
+// version when not given with the HardwareVariant option. Unrecognised
+// hardware versions are VariantUnknown.
+func (d *RemoteDevice) Variant(ctx context.Context) (Variant, error) {
+	d.mu.Lock()
+	variant, known := d.variant, d.known
+	d.mu.Unlock()
+
+	if known {
+		return variant, nil
+	}
+
+	at, err := d.xbee.remoteAT(ctx, d.addr64, tx.HV, nil, 0)
+	if err != nil {
+		return VariantUnknown, err
+	}
+
+	if len(at.Data()) == 2 {
+		variant = variantForHardwareVersion(binary.BigEndian.Uint16(at.Data()))
+	}
+
+	d.SetVariant(variant)
+
+	return variant, nil
+}
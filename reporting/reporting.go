@@ -0,0 +1,307 @@
+// Package reporting subscribes to ZCL attribute reports, configuring
+// reporting on devices and binding their clusters to the local XBee.
+package reporting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/internal/rxloop"
+	"github.com/pauleyj/gobee/zcl"
+	"github.com/pauleyj/gobee/zdo"
+)
+
+const reportBufferSize = 16
+
+// Key identifies an attribute reported by a device's endpoint
+type Key struct {
+	Addr64    uint64
+	Endpoint  byte
+	Cluster   uint16
+	Attribute uint16
+}
+
+// Report a received attribute report
+type Report struct {
+	Key
+	Type  zcl.DataType
+	Value interface{}
+	Time  time.Time
+}
+
+// Handler receives reports
+type Handler func(Report)
+
+// BindSetter interface for Bind setters
+type BindSetter interface {
+	SetBind(bool)
+}
+
+// Bind helper option function to New, whether Subscribe binds the cluster
+// to the local XBee, true by default
+func Bind(bind bool) func(interface{}) {
+	return func(i interface{}) {
+		if b, ok := i.(BindSetter); ok {
+			b.SetBind(bind)
+		}
+	}
+}
+
+// Subscriber configures attribute reporting on devices and delivers the
+// reports they send to handlers and channels by Key. Receiving reports
+// requires the local XBee's AO set to 1.
+type Subscriber struct {
+	xbee  *gobee.XBee
+	zcl   *zcl.Client
+	zdo   *zdo.Client
+	local *gobee.LocalDevice
+	bind  bool
+
+	mu       sync.Mutex
+	handlers map[Key][]Handler
+	streams  map[*stream]struct{}
+}
+
+// stream a channel of reports for a key
+type stream struct {
+	key Key
+	c   chan Report
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// New constructs a Subscriber
+func New(xbee *gobee.XBee, options ...func(interface{})) *Subscriber {
+	s := &Subscriber{
+		xbee:     xbee,
+		zcl:      zcl.New(xbee),
+		zdo:      zdo.New(xbee),
+		local:    xbee.Local(),
+		bind:     true,
+		handlers: make(map[Key][]Handler),
+		streams:  make(map[*stream]struct{}),
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(s)
+	}
+
+	return s
+}
+
+// SetBind satisfy BindSetter interface
+func (s *Subscriber) SetBind(bind bool) {
+	s.bind = bind
+}
+
+// Subscribe binds cluster on dst's endpoint to the local XBee's endpoint
+// dst.SrcEndpoint and configures reporting of its attributes. When binding,
+// a dst.Addr64 of zdo.Addr64Unknown is resolved from dst.Addr16 first. A
+// rejected configuration returns a *zcl.StatusError.
+func (s *Subscriber) Subscribe(ctx context.Context, dst zcl.Address, cluster uint16, configs ...zcl.ReportingConfig) error {
+	if s.bind {
+		if dst.Addr64 == zdo.Addr64Unknown {
+			addr64, err := s.zdo.IEEEAddr(ctx, dst.Addr16)
+			if err != nil {
+				return err
+			}
+			dst.Addr64 = addr64
+		}
+
+		local, err := s.local.Addr64(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = s.zdo.Bind(ctx, dst.Addr64, dst.Addr16, &zdo.Binding{
+			SrcAddr64:   dst.Addr64,
+			SrcEndpoint: dst.DstEndpoint,
+			ClusterID:   cluster,
+			DstAddrMode: zdo.AddrModeAddr64,
+			DstAddr64:   local,
+			DstEndpoint: dst.SrcEndpoint,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := s.zcl.ConfigureReporting(ctx, dst, cluster, configs...)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if r.Status != zcl.StatusSuccess {
+			return &zcl.StatusError{Cluster: cluster, Command: zcl.CommandConfigureReporting, Status: r.Status}
+		}
+	}
+
+	return nil
+}
+
+// Handle adds a handler of reports for key
+func (s *Subscriber) Handle(key Key, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[key] = append(s.handlers[key], h)
+}
+
+// Reports streams reports for key until ctx is done, reports are dropped
+// while the stream's buffer is full
+func (s *Subscriber) Reports(ctx context.Context, key Key) <-chan Report {
+	st := &stream{key: key, c: make(chan Report, reportBufferSize)}
+
+	s.mu.Lock()
+	s.streams[st] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.mu.Lock()
+		delete(s.streams, st)
+		s.mu.Unlock()
+
+		st.close()
+	}()
+
+	return st.c
+}
+
+// Run receives reports, delivering them to handlers and streams, until ctx
+// is done
+func (s *Subscriber) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return s.loop(ctx, s.subscribe(ctx))
+}
+
+// subscribe to received Report Attributes
+func (s *Subscriber) subscribe(ctx context.Context) <-chan rx.Frame {
+	return s.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		e, ok := f.(*rx.ZBExplicit)
+		if !ok || e.ProfileID() == zdo.Profile {
+			return false
+		}
+
+		z, err := zcl.Parse(e.Data())
+		return err == nil && z.Type == zcl.FrameGeneral && !z.ManufacturerSpecific && z.Command == zcl.CommandReportAttributes
+	})
+}
+
+// loop delivers reports in the order received and acknowledges them until
+// they are closed
+func (s *Subscriber) loop(ctx context.Context, frames <-chan rx.Frame) error {
+	rxloop.Each(frames, func(f rx.Frame) func() {
+		e := f.(*rx.ZBExplicit)
+		z, _ := zcl.Parse(e.Data())
+
+		status := zcl.StatusSuccess
+		if attributes, err := zcl.DecodeAttributes(z.Payload); err != nil {
+			status = zcl.StatusMalformedCommand
+		} else {
+			s.deliver(e, attributes)
+		}
+
+		if z.DisableDefaultResponse && status == zcl.StatusSuccess {
+			return nil
+		}
+
+		return func() {
+			s.defaultResponse(ctx, e, z, status)
+		}
+	})
+
+	return ctx.Err()
+}
+
+// deliver passes each reported attribute to the handlers and streams of its key
+func (s *Subscriber) deliver(e *rx.ZBExplicit, attributes []zcl.Attribute) {
+	now := time.Now()
+
+	for _, a := range attributes {
+		r := Report{
+			Key:   Key{Addr64: e.Addr64(), Endpoint: e.SrcEP(), Cluster: e.ClusterID(), Attribute: a.ID},
+			Type:  a.Type,
+			Value: a.Value,
+			Time:  now,
+		}
+
+		s.mu.Lock()
+		handlers := append([]Handler(nil), s.handlers[r.Key]...)
+		s.mu.Unlock()
+
+		for _, h := range handlers {
+			h(r)
+		}
+
+		s.mu.Lock()
+		var streams []*stream
+		for st := range s.streams {
+			if st.key == r.Key {
+				streams = append(streams, st)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, st := range streams {
+			st.send(r)
+		}
+	}
+}
+
+// send passes r to the stream unless it is closed or its buffer is full
+func (st *stream) send(r Report) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.closed {
+		return
+	}
+
+	select {
+	case st.c <- r:
+	default:
+	}
+}
+
+func (st *stream) close() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.closed = true
+	close(st.c)
+}
+
+// defaultResponse acknowledges a report
+func (s *Subscriber) defaultResponse(ctx context.Context, e *rx.ZBExplicit, z *zcl.Frame, status zcl.Status) {
+	src := zcl.Address{
+		Addr64:      e.Addr64(),
+		Addr16:      e.Addr16(),
+		SrcEndpoint: e.DstEP(),
+		DstEndpoint: e.SrcEP(),
+		ProfileID:   e.ProfileID(),
+	}
+
+	_ = s.zcl.Send(ctx, src, e.ClusterID(), &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   zcl.FrameGeneral,
+			Direction:              zcl.ClientToServer,
+			DisableDefaultResponse: true,
+			Seq:                    z.Seq,
+			Command:                zcl.CommandDefaultResponse,
+		},
+		Payload: zcl.EncodeDefaultResponse(z.Command, status),
+	})
+}
@@ -0,0 +1,227 @@
+package reporting
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/internal/radiotest"
+	"github.com/pauleyj/gobee/zcl"
+	"github.com/pauleyj/gobee/zdo"
+)
+
+var dst = zcl.Address{
+	Addr64:      0x0013A20040522BAA,
+	Addr16:      0x1234,
+	SrcEndpoint: 0xE8,
+	DstEndpoint: 0x01,
+	ProfileID:   zcl.ProfileHomeAutomation,
+}
+
+// device answers the local SH/SL, IEEE_addr_req, Bind_req and Configure
+// Reporting, sending the requests it receives on requests
+func device(t *testing.T, status zcl.Status, requests chan<- radiotest.Explicit) func(p []byte) [][]byte {
+	return func(p []byte) [][]byte {
+		if p[0] == 0x08 {
+			data := map[string][]byte{"SH": {0x00, 0x13, 0xA2, 0x00}, "SL": {0x40, 0xA1, 0xB2, 0xC3}}[string(p[2:4])]
+			return [][]byte{append([]byte{0x88, p[1], p[2], p[3], 0x00}, data...)}
+		}
+
+		e, ok := radiotest.ParseExplicit(p)
+		if !ok {
+			t.Fatalf("Expected explicit frame, but got % x", p)
+		}
+		e.Data = append([]byte(nil), e.Data...)
+		requests <- e
+
+		if e.ClusterID == zdo.IEEEAddrReq {
+			return [][]byte{radiotest.TXStatus(e.ID, 0), e.Reply(zdo.IEEEAddrReq|zdo.ResponseCluster, []byte{e.Data[0], byte(zdo.StatusSuccess), 0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x34, 0x12})}
+		}
+		if e.ProfileID == zdo.Profile {
+			return [][]byte{radiotest.TXStatus(e.ID, 0), e.Reply(e.ClusterID|zdo.ResponseCluster, []byte{e.Data[0], byte(zdo.StatusSuccess)})}
+		}
+
+		f, _ := zcl.Parse(e.Data)
+		r := &zcl.Frame{
+			Header:  zcl.Header{Direction: zcl.ServerToClient, Seq: f.Seq, Command: zcl.CommandConfigureReportingResponse},
+			Payload: zcl.EncodeConfigureReportingResponse(zcl.ReportingRecord{Status: status, ID: 0x0000}),
+		}
+
+		return [][]byte{radiotest.TXStatus(e.ID, 0), e.Reply(e.ClusterID, r.Bytes())}
+	}
+}
+
+func TestSubscriber_Subscribe(t *testing.T) {
+	requests := make(chan radiotest.Explicit, 4)
+	xbee, _ := radiotest.New(device(t, zcl.StatusSuccess, requests))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := New(xbee).Subscribe(ctx, dst, 0x0402, zcl.ReportingConfig{
+		ID:               0x0000,
+		Type:             zcl.TypeInt16,
+		MinInterval:      30,
+		MaxInterval:      600,
+		ReportableChange: int16(50),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	bind := <-requests
+	expected := (&zdo.Binding{
+		SrcAddr64:   dst.Addr64,
+		SrcEndpoint: 0x01,
+		ClusterID:   0x0402,
+		DstAddrMode: zdo.AddrModeAddr64,
+		DstAddr64:   0x0013A20040A1B2C3,
+		DstEndpoint: 0xE8,
+	}).Bytes()
+	if bind.ClusterID != zdo.BindReq || !reflect.DeepEqual(bind.Data[1:], expected) {
+		t.Fatalf("Expected Bind_req % x, but got %+v", expected, bind)
+	}
+
+	configure := <-requests
+	f, _ := zcl.Parse(configure.Data)
+	configs, _ := zcl.DecodeConfigureReporting(f.Payload)
+	if f.Command != zcl.CommandConfigureReporting || len(configs) != 1 || configs[0].MaxInterval != 600 {
+		t.Fatalf("Expected Configure Reporting, but got %+v %+v", f.Header, configs)
+	}
+}
+
+func TestSubscriber_Subscribe_Addr64Unknown(t *testing.T) {
+	requests := make(chan radiotest.Explicit, 4)
+	xbee, _ := radiotest.New(device(t, zcl.StatusSuccess, requests))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	unknown := dst
+	unknown.Addr64 = zdo.Addr64Unknown
+	err := New(xbee).Subscribe(ctx, unknown, 0x0402, zcl.ReportingConfig{ID: 0x0000, Type: zcl.TypeInt16, MaxInterval: 600, ReportableChange: int16(50)})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if resolve := <-requests; resolve.ClusterID != zdo.IEEEAddrReq || resolve.Addr16 != dst.Addr16 {
+		t.Fatalf("Expected IEEE_addr_req to 0x%04x, but got %+v", dst.Addr16, resolve)
+	}
+
+	bind := <-requests
+	b, _, err := zdo.ParseBinding(bind.Data[1:])
+	if bind.ClusterID != zdo.BindReq || bind.Addr64 != dst.Addr64 || err != nil || b.SrcAddr64 != dst.Addr64 {
+		t.Fatalf("Expected Bind_req to %#0.16x, but got %+v", dst.Addr64, bind)
+	}
+}
+
+func TestSubscriber_Subscribe_Status(t *testing.T) {
+	xbee, _ := radiotest.New(device(t, zcl.StatusUnreportableAttribute, make(chan radiotest.Explicit, 4)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := New(xbee, Bind(false)).Subscribe(ctx, dst, 0x0402, zcl.ReportingConfig{ID: 0x0000, Type: zcl.TypeInt16, MaxInterval: 600, ReportableChange: int16(50)})
+	if s, ok := err.(*zcl.StatusError); !ok || s.Status != zcl.StatusUnreportableAttribute {
+		t.Fatalf("Expected unreportable attribute, but got %v", err)
+	}
+}
+
+func TestSubscriber_Run(t *testing.T) {
+	requests := make(chan radiotest.Explicit, 4)
+	xbee, radio := radiotest.New(device(t, zcl.StatusSuccess, requests))
+	s := New(xbee)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := Key{Addr64: dst.Addr64, Endpoint: 0x01, Cluster: 0x0402, Attribute: 0x0000}
+	handled := make(chan Report, 1)
+	s.Handle(key, func(r Report) {
+		handled <- r
+	})
+	reports := s.Reports(ctx, key)
+
+	frames := s.subscribe(ctx)
+	go s.loop(ctx, frames)
+
+	payload, _ := zcl.EncodeAttributes(zcl.Attribute{ID: 0x0000, Type: zcl.TypeInt16, Value: int16(2150)})
+	report := &zcl.Frame{Header: zcl.Header{Direction: zcl.ServerToClient, Seq: 0x21, Command: zcl.CommandReportAttributes}, Payload: payload}
+	radio.Send(radiotest.Explicit{
+		Addr64:      dst.Addr64,
+		Addr16:      dst.Addr16,
+		SrcEndpoint: 0x01,
+		DstEndpoint: 0xE8,
+		ClusterID:   0x0402,
+		ProfileID:   zcl.ProfileHomeAutomation,
+		Data:        report.Bytes(),
+	}.Received())
+
+	for _, c := range []<-chan Report{handled, reports} {
+		select {
+		case r := <-c:
+			if r.Key != key || r.Type != zcl.TypeInt16 || r.Value != int16(2150) {
+				t.Fatalf("Expected report of 2150, but got %+v", r)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected report")
+		}
+	}
+
+	select {
+	case e := <-requests:
+		f, _ := zcl.Parse(e.Data)
+		if command, status, _ := zcl.DecodeDefaultResponse(f.Payload); f.Command != zcl.CommandDefaultResponse || f.Seq != 0x21 || command != zcl.CommandReportAttributes || status != zcl.StatusSuccess {
+			t.Fatalf("Expected Default Response, but got %+v % x", f.Header, f.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Default Response")
+	}
+}
+
+func TestSubscriber_Run_Full(t *testing.T) {
+	xbee, radio := radiotest.New(nil)
+	s := New(xbee)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := Key{Addr64: dst.Addr64, Endpoint: 0x01, Cluster: 0x0402, Attribute: 0x0000}
+	handled := make(chan Report)
+	s.Handle(key, func(r Report) {
+		handled <- r
+	})
+	reports := s.Reports(ctx, key)
+
+	frames := s.subscribe(ctx)
+	go s.loop(ctx, frames)
+
+	// the stream is never read, reports beyond its buffer are dropped
+	for i := 0; i < 2*reportBufferSize; i++ {
+		payload, _ := zcl.EncodeAttributes(zcl.Attribute{ID: 0x0000, Type: zcl.TypeInt16, Value: int16(i)})
+		report := &zcl.Frame{Header: zcl.Header{Direction: zcl.ServerToClient, DisableDefaultResponse: true, Command: zcl.CommandReportAttributes}, Payload: payload}
+		radio.Send(radiotest.Explicit{
+			Addr64:      dst.Addr64,
+			Addr16:      dst.Addr16,
+			SrcEndpoint: 0x01,
+			DstEndpoint: 0xE8,
+			ClusterID:   0x0402,
+			ProfileID:   zcl.ProfileHomeAutomation,
+			Data:        report.Bytes(),
+		}.Received())
+
+		select {
+		case r := <-handled:
+			if r.Value != int16(i) {
+				t.Fatalf("Expected report of %d, but got %+v", i, r)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected report %d to be handled", i)
+		}
+	}
+
+	if len(reports) != reportBufferSize {
+		t.Fatalf("Expected %d buffered reports, but got %d", reportBufferSize, len(reports))
+	}
+}
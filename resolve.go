@@ -0,0 +1,174 @@
+package gobee
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/at"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const (
+	// DefaultResolveTTL how long resolved node identifiers are cached
+	DefaultResolveTTL = 10 * time.Minute
+
+	// deliveryAddressNotFound transmit status delivery status, the destination could not be found
+	deliveryAddressNotFound byte = 0x24
+
+	dnAddr16Offset = 0
+	dnAddr64Offset = 2
+	dnLength       = 10
+)
+
+// ErrNodeNotFound no node responded to DN with the node identifier
+var ErrNodeNotFound = errors.New("node not found")
+
+// resolution a resolved node identifier
+type resolution struct {
+	addr64  uint64
+	addr16  uint16
+	expires time.Time
+}
+
+// ResolveTTLSetter interface for ResolveTTL setters
+type ResolveTTLSetter interface {
+	SetResolveTTL(time.Duration)
+}
+
+// ResolveTTL helper option function to gobee.New, how long node identifiers
+// resolved by Resolve are cached
+func ResolveTTL(ttl time.Duration) func(interface{}) {
+	return func(i interface{}) {
+		if t, ok := i.(ResolveTTLSetter); ok {
+			t.SetResolveTTL(ttl)
+		}
+	}
+}
+
+// SetResolveTTL satisfy ResolveTTLSetter interface
+func (x *XBee) SetResolveTTL(ttl time.Duration) {
+	x.mu.Lock()
+	x.resolveTTL = ttl
+	x.mu.Unlock()
+}
+
+// Resolve resolves a node identifier to the node's 64-bit and 16-bit
+// addresses with DN, resolutions are cached for the resolve TTL
+func (x *XBee) Resolve(ctx context.Context, name string) (uint64, uint16, error) {
+	x.mu.Lock()
+	r, ok := x.resolved[name]
+	x.mu.Unlock()
+
+	if ok && time.Now().Before(r.expires) {
+		return r.addr64, r.addr16, nil
+	}
+
+	p, err := at.DestinationNode.Encode(name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	response, err := x.localAT(ctx, at.DestinationNode.Name, p)
+	if e, ok := err.(*ATError); ok && e.Status == 1 {
+		return 0, 0, ErrNodeNotFound
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	data := response.Data()
+	if len(data) < dnLength {
+		return 0, 0, ErrNodeNotFound
+	}
+
+	r = resolution{
+		addr16: binary.BigEndian.Uint16(data[dnAddr16Offset:]),
+		addr64: binary.BigEndian.Uint64(data[dnAddr64Offset:]),
+	}
+
+	x.mu.Lock()
+	ttl := x.resolveTTL
+	if x.resolved == nil {
+		x.resolved = make(map[string]resolution)
+	}
+	r.expires = time.Now().Add(ttl)
+	x.resolved[name] = r
+	x.mu.Unlock()
+
+	return r.addr64, r.addr16, nil
+}
+
+// forget removes a cached resolution
+func (x *XBee) forget(name string) {
+	x.mu.Lock()
+	delete(x.resolved, name)
+	x.mu.Unlock()
+}
+
+// SendTo resolves the node identifier and transmits payload to the node,
+// waiting for the transmit status. When delivery fails because the address
+// was not found the name is resolved again and the payload resent once.
+func (x *XBee) SendTo(ctx context.Context, name string, payload []byte) error {
+	addr64, addr16, err := x.Resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	err = x.send(ctx, addr64, addr16, payload)
+	if e, ok := err.(*DeliveryError); !ok || e.Status != deliveryAddressNotFound {
+		return err
+	}
+
+	x.forget(name)
+
+	addr64, addr16, err = x.Resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return x.send(ctx, addr64, addr16, payload)
+}
+
+// send transmits payload with the protocol family's transmit request and waits
+// for the transmit status
+func (x *XBee) send(ctx context.Context, addr64 uint64, addr16 uint16, payload []byte) error {
+	id := x.NextFrameID()
+
+	if x.protocol == api.Protocol802154 {
+		f, err := x.Request(ctx,
+			tx.NewTX64(tx.FrameID(id), tx.Addr64(addr64), tx.Data(payload)),
+			func(f rx.Frame) bool {
+				s, ok := f.(*rx.LegacyTXStatus)
+				return ok && s.ID() == id
+			})
+		if err != nil {
+			return err
+		}
+
+		if status := f.(*rx.LegacyTXStatus).Status(); status != rx.LegacyTXStatusSuccess {
+			return &DeliveryError{Status: status}
+		}
+
+		return nil
+	}
+
+	f, err := x.Request(ctx,
+		tx.NewZB(tx.FrameID(id), tx.Addr64(addr64), tx.Addr16(addr16), tx.Data(payload)),
+		func(f rx.Frame) bool {
+			s, ok := f.(*rx.TXStatus)
+			return ok && s.ID() == id
+		})
+	if err != nil {
+		return err
+	}
+
+	if status := f.(*rx.TXStatus).Delivery(); status != 0 {
+		return &DeliveryError{Status: status}
+	}
+
+	return nil
+}
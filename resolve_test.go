@@ -0,0 +1,115 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// txStatus builds a transmit status frame answering the transmit request p
+func txStatus(p []byte, delivery byte) []byte {
+	return []byte{0x8B, p[1], 0xFF, 0xFE, 0x00, delivery, 0x00}
+}
+
+func TestXBee_Resolve(t *testing.T) {
+	sent := 0
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent++
+		if string(p[2:4]) != "DN" || string(p[4:]) != "PUMP-3" {
+			t.Fatalf("Expected DN PUMP-3, but got %s % x", p[2:4], p[4:])
+		}
+		return [][]byte{atResponse(p, 0, 0x12, 0x34, 0x00, 0x13, 0xA2, 0x00, 0x40, 0x52, 0x2B, 0xAA)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		addr64, addr16, err := xbee.Resolve(ctx, "PUMP-3")
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if addr64 != 0x0013A20040522BAA || addr16 != 0x1234 {
+			t.Fatalf("Expected 0x0013A20040522BAA/0x1234, but got %#x/%#x", addr64, addr16)
+		}
+	}
+
+	if sent != 1 {
+		t.Fatalf("Expected resolution to be cached, but sent %d DN commands", sent)
+	}
+}
+
+func TestXBee_Resolve_TTL(t *testing.T) {
+	sent := 0
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent++
+		return [][]byte{atResponse(p, 0, 0x12, 0x34, 0x00, 0x13, 0xA2, 0x00, 0x40, 0x52, 0x2B, 0xAA)}
+	})
+	ResolveTTL(0)(xbee)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := xbee.Resolve(ctx, "PUMP-3"); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+	}
+
+	if sent != 2 {
+		t.Fatalf("Expected expired resolution to be resolved again, but sent %d DN commands", sent)
+	}
+}
+
+func TestXBee_Resolve_Not_Found(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		return [][]byte{atResponse(p, 1)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, _, err := xbee.Resolve(ctx, "PUMP-9"); err != ErrNodeNotFound {
+		t.Fatalf("Expected error %v, but got %v", ErrNodeNotFound, err)
+	}
+}
+
+func TestXBee_SendTo(t *testing.T) {
+	addrs := [][]byte{
+		{0x12, 0x34, 0x00, 0x13, 0xA2, 0x00, 0x40, 0x52, 0x2B, 0xAA},
+		{0x56, 0x78, 0x00, 0x13, 0xA2, 0x00, 0x40, 0x52, 0x2B, 0xBB},
+	}
+	resolves, sends := 0, 0
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		switch p[0] {
+		case 0x08:
+			r := atResponse(p, 0, addrs[resolves]...)
+			resolves++
+			return [][]byte{r}
+		case 0x10:
+			sends++
+			if string(p[14:]) != "on" {
+				t.Fatalf("Expected payload on, but got % x", p[14:])
+			}
+			if p[9] == 0xAA {
+				return [][]byte{txStatus(p, deliveryAddressNotFound)}
+			}
+			if p[9] != 0xBB || p[10] != 0x56 || p[11] != 0x78 {
+				t.Fatalf("Expected send to re-resolved address, but got % x", p[2:12])
+			}
+			return [][]byte{txStatus(p, 0)}
+		}
+		t.Fatalf("Unexpected frame % x", p)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := xbee.SendTo(ctx, "PUMP-3", []byte("on")); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if resolves != 2 || sends != 2 {
+		t.Fatalf("Expected 2 resolves and 2 sends, but got %d and %d", resolves, sends)
+	}
+}
@@ -0,0 +1,237 @@
+package gobee
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+const (
+	// maxSamplePeriod longest periodic sample rate, IR is in milliseconds
+	maxSamplePeriod = 0xFFFF * time.Millisecond
+	// maxChangeDetectLine highest line in the 16-bit IC mask
+	maxChangeDetectLine = api.DIO(15)
+)
+
+var (
+	// ErrSamplePeriod sample period out of range
+	ErrSamplePeriod = errors.New("sample period out of range")
+	// ErrChangeDetect change detect line above DIO15
+	ErrChangeDetect = errors.New("change detect line out of range")
+)
+
+// SamplingConfig IO sampling configuration of a remote XBee, the pins sampled
+// are those configured as inputs
+type SamplingConfig struct {
+	// Period between periodic samples, 0 disables periodic sampling
+	Period time.Duration
+	// ChangeDetect digital lines sampled whenever their state changes
+	ChangeDetect []api.DIO
+}
+
+// Edge defines digital state changes
+type Edge byte
+
+// Digital state changes
+const (
+	EdgeNone    = Edge(0)
+	EdgeRising  = Edge(1)
+	EdgeFalling = Edge(2)
+)
+
+// PinEvent a single pin's reading from a sample reported by a remote XBee
+type PinEvent struct {
+	// Addr64 64-bit address of the XBee reporting the sample
+	Addr64 uint64
+	// Timestamp time the sample was received
+	Timestamp time.Time
+	// Analog the event is an analog reading of AD, otherwise a digital reading of DIO
+	Analog bool
+	DIO    api.DIO
+	AD     api.AD
+	// Value raw analog reading, or 1 for digital high and 0 for low
+	Value uint16
+	// Edge digital state change since the previous sample
+	Edge Edge
+}
+
+// ConfigureSampling configures periodic and change detect sampling on the
+// remote XBee and applies the changes
+func (d *RemoteDevice) ConfigureSampling(ctx context.Context, cfg SamplingConfig) error {
+	if cfg.Period < 0 || cfg.Period > maxSamplePeriod {
+		return ErrSamplePeriod
+	}
+	for _, line := range cfg.ChangeDetect {
+		if line > maxChangeDetectLine {
+			return ErrChangeDetect
+		}
+	}
+
+	period := uint16(cfg.Period / time.Millisecond)
+	_, err := d.xbee.remoteAT(ctx, d.addr64, tx.IR, []byte{byte(period >> 8), byte(period)}, 0)
+	if err != nil {
+		return err
+	}
+
+	var mask uint16
+	for _, line := range cfg.ChangeDetect {
+		mask |= 1 << line
+	}
+
+	_, err = d.xbee.remoteAT(ctx, d.addr64, tx.IC, []byte{byte(mask >> 8), byte(mask)}, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.xbee.remoteAT(ctx, d.addr64, tx.AC, nil, tx.OptionApplyChanges)
+
+	return err
+}
+
+// ForceSample forces the XBee at addr, or the local XBee when addr is
+// api.LocalAddr64, to sample its enabled IO lines and returns the sample
+// decoded as for the IO sample frames of the XBee's protocol
+func (x *XBee) ForceSample(ctx context.Context, addr uint64) (rx.Sample, error) {
+	var data []byte
+	if addr == api.LocalAddr64 {
+		at, err := x.localAT(ctx, tx.IS, nil)
+		if err != nil {
+			return rx.Sample{}, err
+		}
+		data = at.Data()
+	} else {
+		at, err := x.remoteAT(ctx, addr, tx.IS, nil, 0)
+		if err != nil {
+			return rx.Sample{}, err
+		}
+		data = at.Data()
+	}
+
+	if x.Protocol() == api.Protocol802154 {
+		return rx.DecodeLegacySample(data)
+	}
+
+	return rx.DecodeSample(data)
+}
+
+// Samples streams a PinEvent for each pin of every sample the remote XBee
+// reports until ctx is done, the channel is closed when ctx is done
+func (d *RemoteDevice) Samples(ctx context.Context) <-chan PinEvent {
+	return d.stream(ctx, false)
+}
+
+// Edges streams a PinEvent for each digital state change reported by the
+// remote XBee until ctx is done, the channel is closed when ctx is done
+func (d *RemoteDevice) Edges(ctx context.Context) <-chan PinEvent {
+	return d.stream(ctx, true)
+}
+
+func (d *RemoteDevice) stream(ctx context.Context, edgesOnly bool) <-chan PinEvent {
+	l := d.xbee.listen(func(f rx.Frame) bool {
+		switch f := f.(type) {
+		case *rx.IOSample:
+			return f.Addr64() == d.addr64
+		case *rx.RX64IO:
+			return f.Addr64() == d.addr64
+		}
+		return false
+	})
+
+	events := make(chan PinEvent, listenerBufferSize)
+
+	go func() {
+		defer close(events)
+		defer d.xbee.unlisten(l)
+
+		previous := make(map[api.DIO]bool)
+		for {
+			select {
+			case f := <-l.c:
+				for _, s := range frameSamples(f) {
+					for _, e := range pinEvents(d.addr64, time.Now(), s, previous) {
+						if edgesOnly && e.Edge == EdgeNone {
+							continue
+						}
+
+						select {
+						case events <- e:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// frameSamples decoded samples of an IO frame, undecodable frames yield none
+func frameSamples(f rx.Frame) []rx.Sample {
+	switch f := f.(type) {
+	case *rx.IOSample:
+		s, err := f.Sample()
+		if err != nil {
+			return nil
+		}
+		return []rx.Sample{s}
+	case *rx.RX64IO:
+		samples, _ := f.Samples()
+		return samples
+	}
+
+	return nil
+}
+
+// pinEvents splits a sample into per pin events, ordered digital lines then
+// analog lines, and records digital states in previous to detect edges
+func pinEvents(addr uint64, timestamp time.Time, s rx.Sample, previous map[api.DIO]bool) []PinEvent {
+	events := make([]PinEvent, 0, len(s.Digital)+len(s.Analog))
+
+	lines := make([]int, 0, len(s.Digital))
+	for line := range s.Digital {
+		lines = append(lines, int(line))
+	}
+	sort.Ints(lines)
+
+	for _, line := range lines {
+		dio := api.DIO(line)
+		state := s.Digital[dio]
+
+		e := PinEvent{Addr64: addr, Timestamp: timestamp, DIO: dio}
+		if state {
+			e.Value = 1
+		}
+
+		if last, ok := previous[dio]; ok && last != state {
+			e.Edge = EdgeFalling
+			if state {
+				e.Edge = EdgeRising
+			}
+		}
+		previous[dio] = state
+
+		events = append(events, e)
+	}
+
+	lines = lines[:0]
+	for line := range s.Analog {
+		lines = append(lines, int(line))
+	}
+	sort.Ints(lines)
+
+	for _, line := range lines {
+		ad := api.AD(line)
+		events = append(events, PinEvent{Addr64: addr, Timestamp: timestamp, Analog: true, AD: ad, Value: s.Analog[ad]})
+	}
+
+	return events
+}
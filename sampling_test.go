@@ -0,0 +1,202 @@
+package gobee
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+func TestRemoteDevice_ConfigureSampling(t *testing.T) {
+	var sent []remoteCommand
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		sent = append(sent, parseRemoteCommand(t, p))
+		return [][]byte{atRemoteResponse(p, 0)}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := xbee.Remote(0x0013A20040522BAA).ConfigureSampling(ctx, SamplingConfig{
+		Period:       2 * time.Second,
+		ChangeDetect: []api.DIO{api.DIO4, api.DIO11},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []remoteCommand{
+		{0x00, "IR", []byte{0x07, 0xD0}},
+		{0x00, "IC", []byte{0x08, 0x10}},
+		{0x02, "AC", []byte{}},
+	}
+	if len(sent) != len(expected) {
+		t.Fatalf("Expected %d commands, but got %d", len(expected), len(sent))
+	}
+	for i, e := range expected {
+		if sent[i].command != e.command || sent[i].options != e.options || string(sent[i].parameter) != string(e.parameter) {
+			t.Fatalf("Expected command %+v, but got %+v", e, sent[i])
+		}
+	}
+
+	err = xbee.Remote(0x0013A20040522BAA).ConfigureSampling(ctx, SamplingConfig{Period: 70 * time.Second})
+	if err != ErrSamplePeriod {
+		t.Fatalf("Expected error %v, but got %v", ErrSamplePeriod, err)
+	}
+
+	err = xbee.Remote(0x0013A20040522BAA).ConfigureSampling(ctx, SamplingConfig{ChangeDetect: []api.DIO{api.DIO(16)}})
+	if err != ErrChangeDetect {
+		t.Fatalf("Expected error %v, but got %v", ErrChangeDetect, err)
+	}
+	if len(sent) != len(expected) {
+		t.Fatalf("Expected rejected configurations not to be sent, but got %d commands", len(sent))
+	}
+}
+
+// ioSample builds an IO data sample frame from addr with DIO4 in state and AD1
+func ioSample(addr uint64, state bool, ad1 byte) []byte {
+	digital := byte(0x00)
+	if state {
+		digital = 0x10
+	}
+
+	return []byte{
+		0x92,
+		byte(addr >> 56), byte(addr >> 48), byte(addr >> 40), byte(addr >> 32),
+		byte(addr >> 24), byte(addr >> 16), byte(addr >> 8), byte(addr),
+		0x7D, 0x84, 0x01, 0x01,
+		0x00, 0x10, 0x02, 0x00,
+		digital, 0x00, ad1}
+}
+
+func TestRemoteDevice_Samples(t *testing.T) {
+	const addr = uint64(0x0013A20040522BAA)
+
+	xbee, radio := newRadio(func(p []byte) [][]byte {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	remote := xbee.Remote(addr)
+	samples := remote.Samples(ctx)
+	edges := remote.Edges(ctx)
+
+	radio.send(
+		ioSample(addr, false, 0x10),
+		ioSample(0x0013A20040522BBB, true, 0x20),
+		ioSample(addr, true, 0x11))
+
+	expected := []PinEvent{
+		{Addr64: addr, DIO: api.DIO4, Value: 0},
+		{Addr64: addr, Analog: true, AD: api.AD1, Value: 0x10},
+		{Addr64: addr, DIO: api.DIO4, Value: 1, Edge: EdgeRising},
+		{Addr64: addr, Analog: true, AD: api.AD1, Value: 0x11},
+	}
+	for _, e := range expected {
+		actual := <-samples
+		if actual.Timestamp.IsZero() {
+			t.Fatal("Expected timestamp")
+		}
+
+		actual.Timestamp = time.Time{}
+		if actual != e {
+			t.Fatalf("Expected event %+v, but got %+v", e, actual)
+		}
+	}
+
+	edge := <-edges
+	if edge.DIO != api.DIO4 || edge.Edge != EdgeRising {
+		t.Fatalf("Expected DIO4 rising edge, but got %+v", edge)
+	}
+
+	cancel()
+	for range samples {
+	}
+	for range edges {
+	}
+}
+
+// atResponse builds the local AT command response to request p
+func atResponse(p []byte, status byte, data ...byte) []byte {
+	f := []byte{0x88, p[1], p[2], p[3], status}
+	return append(f, data...)
+}
+
+func TestXBee_ForceSample_802154(t *testing.T) {
+	// DIO0 high and DIO2 low, AD0 and AD1
+	sample := []byte{0x01, 0x06, 0x05, 0x00, 0x01, 0x02, 0x00, 0x00, 0x04}
+
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		switch p[0] {
+		case 0x08:
+			return [][]byte{atResponse(p, 0, sample...)}
+		case 0x17:
+			return [][]byte{atRemoteResponse(p, 0, sample...)}
+		}
+		t.Fatalf("Unexpected API ID %#0.2x", p[0])
+		return nil
+	}, APIProtocol(api.Protocol802154))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	expected := rx.Sample{
+		Digital: map[api.DIO]bool{api.DIO0: true, api.DIO2: false},
+		Analog:  map[api.AD]uint16{api.AD0: 0x0200, api.AD1: 0x0004},
+	}
+
+	for _, addr := range []uint64{api.LocalAddr64, 0x0013A20040522BAA} {
+		s, err := xbee.ForceSample(ctx, addr)
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if !reflect.DeepEqual(s, expected) {
+			t.Fatalf("Expected sample %+v, but got %+v", expected, s)
+		}
+	}
+}
+
+func TestXBee_ForceSample(t *testing.T) {
+	// DIO4 high, AD1 and supply voltage
+	sample := []byte{0x01, 0x00, 0x10, 0x82, 0x00, 0x10, 0x01, 0x23, 0x0B, 0xB8}
+
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		switch p[0] {
+		case 0x08:
+			if string(p[2:4]) != "IS" {
+				t.Fatalf("Expected IS, but got %s", p[2:4])
+			}
+			return [][]byte{atResponse(p, 0, sample...)}
+		case 0x17:
+			if cmd := parseRemoteCommand(t, p); cmd.command != "IS" {
+				t.Fatalf("Expected IS, but got %s", cmd.command)
+			}
+			return [][]byte{atRemoteResponse(p, 0, sample...)}
+		}
+		t.Fatalf("Unexpected API ID %#0.2x", p[0])
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	expected := rx.Sample{
+		Digital: map[api.DIO]bool{api.DIO4: true},
+		Analog:  map[api.AD]uint16{api.AD1: 0x0123, api.SupplyVoltage: 0x0BB8},
+	}
+
+	for _, addr := range []uint64{api.LocalAddr64, 0x0013A20040522BAA} {
+		s, err := xbee.ForceSample(ctx, addr)
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if !reflect.DeepEqual(s, expected) {
+			t.Fatalf("Expected sample %+v, but got %+v", expected, s)
+		}
+	}
+}
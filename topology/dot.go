@@ -0,0 +1,58 @@
+package topology
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/pauleyj/gobee/zdo"
+)
+
+var shapes = map[zdo.DeviceType]string{
+	zdo.DeviceCoordinator: "doublecircle",
+	zdo.DeviceRouter:      "circle",
+	zdo.DeviceEndDevice:   "box",
+}
+
+// WriteDOT writes the graph in Graphviz DOT, neighbor links are solid edges
+// labeled with their LQI and routes dashed edges to the next hop labeled with
+// their destination
+func (g *Graph) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "digraph zigbee {")
+
+	byAddr16 := make(map[uint16]uint64, len(g.Nodes))
+	for _, n := range g.Nodes {
+		byAddr16[n.Addr16] = n.Addr64
+
+		shape, ok := shapes[n.DeviceType]
+		if !ok {
+			shape = "ellipse"
+		}
+
+		label := fmt.Sprintf("%#0.16x\n%#0.4x", n.Addr64, n.Addr16)
+		if n.Err != "" {
+			label += "\n" + n.Err
+		}
+
+		fmt.Fprintf(b, "\t\"%#0.16x\" [shape=%s, label=%q];\n", n.Addr64, shape, label)
+	}
+
+	for _, l := range g.Links {
+		fmt.Fprintf(b, "\t\"%#0.16x\" -> \"%#0.16x\" [label=\"%d\"];\n", l.From, l.To, l.LQI)
+	}
+
+	for _, r := range g.Routes {
+		next, ok := byAddr16[r.NextHop]
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(b, "\t\"%#0.16x\" -> \"%#0.16x\" [style=dashed, label=\"%#0.4x\"];\n", r.Node, next, r.Destination)
+	}
+
+	fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
@@ -0,0 +1,158 @@
+// Package topology crawls a Zigbee network's neighbor and routing tables with
+// ZDO Mgmt_Lqi and Mgmt_Rtg requests, building a graph of the mesh that
+// exports as JSON and Graphviz DOT.
+package topology
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zdo"
+)
+
+// CoordinatorAddr16 16-bit address of the coordinator, where crawls usually start
+const CoordinatorAddr16 uint16 = 0x0000
+
+// Client the ZDO requests a crawl makes, satisfied by *zdo.Client
+type Client interface {
+	IEEEAddr(ctx context.Context, addr16 uint16) (uint64, error)
+	LQITable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*zdo.NeighborTable, error)
+	RoutingTable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*zdo.RoutingTable, error)
+}
+
+// Node a device in the network
+type Node struct {
+	Addr64     uint64         `json:"addr64" yaml:"addr64"`
+	Addr16     uint16         `json:"addr16" yaml:"addr16"`
+	DeviceType zdo.DeviceType `json:"device_type" yaml:"device_type"`
+	Depth      byte           `json:"depth" yaml:"depth"`
+	// Err the node's tables could not be read
+	Err string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Link a neighbor table entry, To is a neighbor of From
+type Link struct {
+	From         uint64           `json:"from" yaml:"from"`
+	To           uint64           `json:"to" yaml:"to"`
+	LQI          byte             `json:"lqi" yaml:"lqi"`
+	Relationship zdo.Relationship `json:"relationship" yaml:"relationship"`
+}
+
+// Route a routing table entry of the router Node
+type Route struct {
+	Node        uint64          `json:"node" yaml:"node"`
+	Destination uint16          `json:"destination" yaml:"destination"`
+	NextHop     uint16          `json:"next_hop" yaml:"next_hop"`
+	Status      zdo.RouteStatus `json:"status" yaml:"status"`
+}
+
+// Graph the crawled network
+type Graph struct {
+	Nodes  []*Node `json:"nodes" yaml:"nodes"`
+	Links  []Link  `json:"links" yaml:"links"`
+	Routes []Route `json:"routes" yaml:"routes"`
+}
+
+// Node the node with the 64-bit address, nil if not crawled
+func (g *Graph) Node(addr64 uint64) *Node {
+	for _, n := range g.Nodes {
+		if n.Addr64 == addr64 {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// Crawl walks the network from the router or coordinator at addr16, reading
+// every reachable router's neighbor and routing tables page by page. End
+// devices keep no tables and are only added as neighbors. Nodes whose tables
+// cannot be read are recorded with Err and the crawl continues; only failing
+// to address the starting node, or ctx ending, fails the crawl.
+func Crawl(ctx context.Context, c Client, addr16 uint16) (*Graph, error) {
+	addr64, err := c.IEEEAddr(ctx, addr16)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{}
+	start := &Node{Addr64: addr64, Addr16: addr16}
+	if addr16 == CoordinatorAddr16 {
+		start.DeviceType = zdo.DeviceCoordinator
+	} else {
+		start.DeviceType = zdo.DeviceRouter
+	}
+	g.Nodes = append(g.Nodes, start)
+
+	queue := []*Node{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		neighbors, err := neighborTable(ctx, c, n)
+		if err == nil {
+			err = g.addRoutes(ctx, c, n)
+		}
+		if ctx.Err() != nil {
+			return g, ctx.Err()
+		}
+		if err != nil {
+			n.Err = err.Error()
+			continue
+		}
+
+		for _, nb := range neighbors {
+			g.Links = append(g.Links, Link{From: n.Addr64, To: nb.Addr64, LQI: nb.LQI, Relationship: nb.Relationship})
+
+			if g.Node(nb.Addr64) != nil {
+				continue
+			}
+
+			node := &Node{Addr64: nb.Addr64, Addr16: nb.Addr16, DeviceType: nb.DeviceType, Depth: nb.Depth}
+			g.Nodes = append(g.Nodes, node)
+
+			if nb.DeviceType == zdo.DeviceRouter || nb.DeviceType == zdo.DeviceCoordinator {
+				queue = append(queue, node)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// neighborTable reads every page of the node's neighbor table
+func neighborTable(ctx context.Context, c Client, n *Node) ([]zdo.Neighbor, error) {
+	var neighbors []zdo.Neighbor
+	for {
+		t, err := c.LQITable(ctx, n.Addr64, n.Addr16, byte(len(neighbors)))
+		if err != nil {
+			return nil, err
+		}
+
+		neighbors = append(neighbors, t.Neighbors...)
+		if len(t.Neighbors) == 0 || len(neighbors) >= int(t.Entries) {
+			return neighbors, nil
+		}
+	}
+}
+
+// addRoutes reads every page of the node's routing table into the graph
+func (g *Graph) addRoutes(ctx context.Context, c Client, n *Node) error {
+	var routes []Route
+	for {
+		t, err := c.RoutingTable(ctx, n.Addr64, n.Addr16, byte(len(routes)))
+		if e, ok := err.(*zdo.StatusError); ok && e.Status == zdo.StatusNotSupported {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, r := range t.Routes {
+			routes = append(routes, Route{Node: n.Addr64, Destination: r.Destination, NextHop: r.NextHop, Status: r.Status})
+		}
+		if len(t.Routes) == 0 || len(routes) >= int(t.Entries) {
+			g.Routes = append(g.Routes, routes...)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package topology
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pauleyj/gobee/zdo"
+)
+
+const (
+	coordinator = uint64(0x0013A20040000001)
+	router      = uint64(0x0013A20040000002)
+	endDevice   = uint64(0x0013A20040000003)
+	unreachable = uint64(0x0013A20040000004)
+)
+
+// client a fake ZDO client serving one neighbor table entry per page
+type client struct {
+	neighbors map[uint64][]zdo.Neighbor
+	routes    map[uint64][]zdo.Route
+	requests  int
+}
+
+func (c *client) IEEEAddr(ctx context.Context, addr16 uint16) (uint64, error) {
+	return coordinator, nil
+}
+
+func (c *client) LQITable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*zdo.NeighborTable, error) {
+	c.requests++
+
+	neighbors, ok := c.neighbors[addr64]
+	if !ok {
+		return nil, context.DeadlineExceeded
+	}
+
+	t := &zdo.NeighborTable{Entries: byte(len(neighbors)), StartIndex: start}
+	if int(start) < len(neighbors) {
+		t.Neighbors = neighbors[start : start+1]
+	}
+
+	return t, nil
+}
+
+func (c *client) RoutingTable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*zdo.RoutingTable, error) {
+	routes, ok := c.routes[addr64]
+	if !ok {
+		return nil, &zdo.StatusError{Cluster: zdo.MgmtRtgReq, Status: zdo.StatusNotSupported}
+	}
+
+	return &zdo.RoutingTable{Entries: byte(len(routes)), StartIndex: start, Routes: routes[start:]}, nil
+}
+
+func testClient() *client {
+	return &client{
+		neighbors: map[uint64][]zdo.Neighbor{
+			coordinator: {
+				{Addr64: router, Addr16: 0x1111, DeviceType: zdo.DeviceRouter, Relationship: zdo.RelationshipChild, Depth: 1, LQI: 200},
+				{Addr64: unreachable, Addr16: 0x4444, DeviceType: zdo.DeviceRouter, Relationship: zdo.RelationshipChild, Depth: 1, LQI: 20},
+			},
+			router: {
+				{Addr64: coordinator, Addr16: 0x0000, DeviceType: zdo.DeviceCoordinator, Relationship: zdo.RelationshipParent, LQI: 190},
+				{Addr64: endDevice, Addr16: 0x3333, DeviceType: zdo.DeviceEndDevice, Relationship: zdo.RelationshipChild, Depth: 2, LQI: 150},
+			},
+		},
+		routes: map[uint64][]zdo.Route{
+			coordinator: {{Destination: 0x3333, Status: zdo.RouteActive, NextHop: 0x1111}},
+		},
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	c := testClient()
+
+	g, err := Crawl(context.Background(), c, CoordinatorAddr16)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if len(g.Nodes) != 4 {
+		t.Fatalf("Expected 4 nodes, but got %d", len(g.Nodes))
+	}
+	if n := g.Node(endDevice); n == nil || n.DeviceType != zdo.DeviceEndDevice || n.Addr16 != 0x3333 {
+		t.Fatalf("Expected end device 0x3333, but got %+v", n)
+	}
+	if n := g.Node(unreachable); n == nil || n.Err == "" {
+		t.Fatalf("Expected unreachable router with error, but got %+v", n)
+	}
+	if len(g.Links) != 4 {
+		t.Fatalf("Expected 4 links, but got %+v", g.Links)
+	}
+	if len(g.Routes) != 1 || g.Routes[0].Node != coordinator || g.Routes[0].NextHop != 0x1111 {
+		t.Fatalf("Expected coordinator route via 0x1111, but got %+v", g.Routes)
+	}
+
+	// two pages each for the coordinator and router, one failure
+	if c.requests != 5 {
+		t.Fatalf("Expected 5 Mgmt_Lqi requests, but got %d", c.requests)
+	}
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(b, &decoded); err != nil || len(decoded.Nodes) != 4 {
+		t.Fatalf("Expected graph to round trip JSON, but got %v %+v", err, decoded)
+	}
+}
+
+func TestCrawl_Start_Failure(t *testing.T) {
+	c := testClient()
+	c.neighbors = nil
+
+	g, err := Crawl(context.Background(), c, CoordinatorAddr16)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(g.Nodes) != 1 || !strings.Contains(g.Nodes[0].Err, "deadline") {
+		t.Fatalf("Expected coordinator with error, but got %+v", g.Nodes)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Crawl(ctx, c, CoordinatorAddr16); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected error %v, but got %v", context.Canceled, err)
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	g, _ := Crawl(context.Background(), testClient(), CoordinatorAddr16)
+
+	var b bytes.Buffer
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	dot := b.String()
+	for _, expected := range []string{
+		"digraph zigbee {",
+		`"0x0013a20040000001" [shape=doublecircle, label="0x0013a20040000001\n0x0000"];`,
+		`"0x0013a20040000003" [shape=box`,
+		`"0x0013a20040000001" -> "0x0013a20040000002" [label="200"];`,
+		`"0x0013a20040000001" -> "0x0013a20040000002" [style=dashed, label="0x3333"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Fatalf("Expected DOT to contain %s, but got\n%s", expected, dot)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package gobee
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// Traceroute sends a DigiMesh unicast to addr with the trace route option and
+// collects the route information reported by each hop, ordered from source to
+// destination. Traceroute returns once every hop from the source to addr has
+// reported. If ctx is done or delivery fails before the destination reports,
+// the hops collected so far are returned along with the error.
+func (x *XBee) Traceroute(ctx context.Context, addr uint64) ([]*rx.RouteInformation, error) {
+	id := x.NextFrameID()
+
+	// every hop is queued, a burst of route records must not be lost
+	l := x.listenAll(func(f rx.Frame) bool {
+		switch f := f.(type) {
+		case *rx.RouteInformation:
+			return f.Event() == rx.RouteEventTraceRoute && f.Destination() == addr
+		case *rx.TXStatus:
+			return f.ID() == id
+		}
+		return false
+	})
+	defer x.unlisten(l)
+
+	_, err := x.TX(tx.NewZB(
+		tx.FrameID(id),
+		tx.Addr64(addr),
+		tx.Addr16(api.BroadcastAddr16),
+		tx.Options(tx.OptionTraceRoute)))
+	if err != nil {
+		return nil, err
+	}
+
+	var hops []*rx.RouteInformation
+	for {
+		f, err := l.next(ctx)
+		if err != nil {
+			return orderRoute(hops), err
+		}
+
+		switch f := f.(type) {
+		case *rx.RouteInformation:
+			hops = append(hops, f)
+			if route := orderRoute(hops); complete(route, addr) {
+				return route, nil
+			}
+		case *rx.TXStatus:
+			if f.Delivery() != 0 {
+				return orderRoute(hops), &DeliveryError{Status: f.Delivery()}
+			}
+		}
+	}
+}
+
+// complete does the ordered route lead, without gaps, from the source to addr
+func complete(route []*rx.RouteInformation, addr uint64) bool {
+	if len(route) == 0 || route[0].Responder() != route[0].Source() {
+		return false
+	}
+
+	for i := 1; i < len(route); i++ {
+		if route[i-1].Receiver() != route[i].Responder() {
+			return false
+		}
+	}
+
+	return route[len(route)-1].Receiver() == addr
+}
+
+// orderRoute chains hops by matching each hop's receiver to the next hop's
+// responder, hops that do not fit the chain are appended in arrival order
+func orderRoute(hops []*rx.RouteInformation) []*rx.RouteInformation {
+	byResponder := make(map[uint64]*rx.RouteInformation, len(hops))
+	receivers := make(map[uint64]struct{}, len(hops))
+	for _, hop := range hops {
+		byResponder[hop.Responder()] = hop
+		receivers[hop.Receiver()] = struct{}{}
+	}
+
+	ordered := make([]*rx.RouteInformation, 0, len(hops))
+	used := make(map[*rx.RouteInformation]struct{}, len(hops))
+	for _, hop := range hops {
+		if _, ok := receivers[hop.Responder()]; ok {
+			continue
+		}
+
+		for next := hop; next != nil; next = byResponder[next.Receiver()] {
+			if _, ok := used[next]; ok {
+				break
+			}
+			ordered = append(ordered, next)
+			used[next] = struct{}{}
+		}
+	}
+
+	for _, hop := range hops {
+		if _, ok := used[hop]; !ok {
+			ordered = append(ordered, hop)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,72 @@
+package gobee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api/tx/util"
+)
+
+func routeInformation(dst, src, responder, receiver uint64) []byte {
+	p := []byte{0x8D, 0x12, 0x27, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	p = append(p, util.Uint64ToBytes(dst)...)
+	p = append(p, util.Uint64ToBytes(src)...)
+	p = append(p, util.Uint64ToBytes(responder)...)
+	return append(p, util.Uint64ToBytes(receiver)...)
+}
+
+func TestXBee_Traceroute(t *testing.T) {
+	const (
+		src = uint64(0x0013A20040000001)
+		hop = uint64(0x0013A20040000002)
+		dst = uint64(0x0013A20040000003)
+	)
+
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		if p[0] != 0x10 {
+			t.Fatalf("Expected ZB frame, but got API ID %#0.2x", p[0])
+		}
+		if p[13] != 0x08 {
+			t.Fatalf("Expected trace route option, but got %#0.2x", p[13])
+		}
+
+		// hops reported out of order
+		return [][]byte{
+			routeInformation(dst, src, hop, dst),
+			routeInformation(dst, src, src, hop),
+			{0x8B, p[1], 0xFF, 0xFE, 0x00, 0x00, 0x00},
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	route, err := xbee.Traceroute(ctx, dst)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(route) != 2 {
+		t.Fatalf("Expected 2 hops, but got %d", len(route))
+	}
+	if route[0].Responder() != src || route[0].Receiver() != hop {
+		t.Fatalf("Expected first hop %#0.16x -> %#0.16x, but got %#0.16x -> %#0.16x", src, hop, route[0].Responder(), route[0].Receiver())
+	}
+	if route[1].Responder() != hop || route[1].Receiver() != dst {
+		t.Fatalf("Expected second hop %#0.16x -> %#0.16x, but got %#0.16x -> %#0.16x", hop, dst, route[1].Responder(), route[1].Receiver())
+	}
+}
+
+func TestXBee_Traceroute_Delivery_Failure(t *testing.T) {
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		return [][]byte{{0x8B, p[1], 0xFF, 0xFE, 0x00, 0x25, 0x00}}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := xbee.Traceroute(ctx, 0x0013A20040000003)
+	if e, ok := err.(*DeliveryError); !ok || e.Status != 0x25 {
+		t.Fatalf("Expected delivery error 0x25, but got %v", err)
+	}
+}
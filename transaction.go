@@ -0,0 +1,151 @@
+package gobee
+
+import (
+	"context"
+	"time"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+// rollbackTimeout bounds restoring the previous values, which continues when
+// the ctx given to Commit is done
+const rollbackTimeout = 10 * time.Second
+
+// ConfigTransaction stages parameters for a device and applies them together
+// with AC, restoring the previous values if any step fails
+type ConfigTransaction struct {
+	device  Device
+	persist bool
+	staged  []parameter
+}
+
+// parameter an AT command parameter value
+type parameter struct {
+	cmd   *at.Command
+	value interface{}
+}
+
+// PersistSetter interface for Persist setters
+type PersistSetter interface {
+	SetPersist(bool)
+}
+
+// Persist helper option function to NewConfigTransaction, write the applied
+// parameters to non-volatile memory with WR
+func Persist(persist bool) func(interface{}) {
+	return func(i interface{}) {
+		if t, ok := i.(PersistSetter); ok {
+			t.SetPersist(persist)
+		}
+	}
+}
+
+// NewConfigTransaction constructs a ConfigTransaction for device
+func NewConfigTransaction(device Device, options ...func(interface{})) *ConfigTransaction {
+	t := &ConfigTransaction{device: device}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		option(t)
+	}
+
+	return t
+}
+
+// SetPersist satisfy PersistSetter interface
+func (t *ConfigTransaction) SetPersist(persist bool) {
+	t.persist = persist
+}
+
+// Stage validates v against cmd on the device's protocol family and stages
+// it, nothing is sent until Commit
+func (t *ConfigTransaction) Stage(cmd *at.Command, v interface{}) error {
+	c, ok := cmd.For(t.device.Protocol())
+	if !ok {
+		return at.ErrProtocol
+	}
+	if _, err := encodeParameter(c, v); err != nil {
+		return err
+	}
+
+	t.staged = append(t.staged, parameter{cmd: cmd, value: v})
+
+	return nil
+}
+
+// Commit reads the previous values of the staged parameters, queues each
+// staged parameter, applies them with AC and, with Persist, writes them with
+// WR. If any step fails the previous values of the parameters queued so far,
+// including one that failed to queue, are queued, applied and, with Persist,
+// written again and a *TransactionError is returned. Write-only parameters
+// cannot be read and are not restored.
+func (t *ConfigTransaction) Commit(ctx context.Context) error {
+	previous := make(map[*at.Command]interface{})
+	for _, p := range t.staged {
+		if c, _ := p.cmd.For(t.device.Protocol()); !c.Readable() {
+			continue
+		}
+
+		v, err := t.device.GetParameter(ctx, p.cmd)
+		if err != nil {
+			return err
+		}
+
+		previous[p.cmd] = v
+	}
+
+	var queued []*at.Command
+	for _, p := range t.staged {
+		// a failed write may still have been queued
+		if err := t.device.QueueParameter(ctx, p.cmd, p.value); err != nil {
+			return t.rollback(ctx, previous, append(queued, p.cmd), err)
+		}
+
+		queued = append(queued, p.cmd)
+	}
+
+	if err := t.device.ApplyChanges(ctx); err != nil {
+		return t.rollback(ctx, previous, queued, err)
+	}
+
+	if t.persist {
+		if err := t.device.WriteChanges(ctx); err != nil {
+			return t.rollback(ctx, previous, queued, err)
+		}
+	}
+
+	return nil
+}
+
+// rollback queues the previous values of the queued parameters and applies
+// them, with Persist writing them with WR, even if ctx is done
+func (t *ConfigTransaction) rollback(ctx context.Context, previous map[*at.Command]interface{}, queued []*at.Command, err error) error {
+	e := &TransactionError{Err: err}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	for _, cmd := range queued {
+		v, ok := previous[cmd]
+		if !ok {
+			continue
+		}
+
+		if e.Rollback = t.device.QueueParameter(ctx, cmd, v); e.Rollback != nil {
+			return e
+		}
+	}
+
+	if e.Rollback = t.device.ApplyChanges(ctx); e.Rollback != nil {
+		return e
+	}
+
+	if t.persist {
+		e.Rollback = t.device.WriteChanges(ctx)
+	}
+
+	return e
+}
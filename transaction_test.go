@@ -0,0 +1,194 @@
+package gobee
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/api/at"
+)
+
+// configRadio a fake local XBee holding parameters, queued parameters are
+// applied by AC, fail is the command whose first write is answered with an
+// invalid parameter status
+func configRadio(t *testing.T, fail string) (*XBee, map[string]string, *[]string) {
+	applied := map[string]string{"ID": "\x00\x00\x00\x00\x00\x00\x00\x01", "CH": "\x0B", "NI": "OLD"}
+	queued := map[string]string{}
+	var sent []string
+
+	xbee, _ := newRadio(func(p []byte) [][]byte {
+		cmd := string(p[2:4])
+		sent = append(sent, cmd)
+
+		switch {
+		case cmd == fail && len(p) > 4:
+			fail = ""
+			return [][]byte{atResponse(p, 3)}
+		case cmd == "AC" || cmd == "WR":
+			for k, v := range queued {
+				applied[k] = v
+			}
+			queued = map[string]string{}
+		case len(p) > 4:
+			queued[cmd] = string(p[4:])
+		default:
+			return [][]byte{atResponse(p, 0, []byte(applied[cmd])...)}
+		}
+		return [][]byte{atResponse(p, 0)}
+	})
+
+	return xbee, applied, &sent
+}
+
+func TestConfigTransaction_Commit(t *testing.T) {
+	xbee, applied, sent := configRadio(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tr := NewConfigTransaction(xbee.Local(), Persist(true))
+	if err := tr.Stage(at.Channel, 0x0F); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := tr.Stage(at.NodeIdentifier, "NEW"); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if err := tr.Stage(at.Channel, 0x30); err != at.ErrRange {
+		t.Fatalf("Expected error %v, but got %v", at.ErrRange, err)
+	}
+
+	if err := tr.Commit(ctx); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	if applied["CH"] != "\x0F" || applied["NI"] != "NEW" {
+		t.Fatalf("Expected CH and NI applied, but got %q", applied)
+	}
+
+	expected := []string{"CH", "NI", "CH", "NI", "AC", "WR"}
+	if len(*sent) != len(expected) {
+		t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+	}
+	for i := range expected {
+		if (*sent)[i] != expected[i] {
+			t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+		}
+	}
+}
+
+func TestConfigTransaction_Rollback(t *testing.T) {
+	xbee, applied, sent := configRadio(t, "NI")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tr := NewConfigTransaction(xbee.Local())
+	tr.Stage(at.Channel, 0x0F)
+	tr.Stage(at.PanID, 0x1234)
+	tr.Stage(at.NodeIdentifier, "NEW")
+
+	err := tr.Commit(ctx)
+
+	var e *TransactionError
+	if !errors.As(err, &e) || e.Rollback != nil {
+		t.Fatalf("Expected rolled back transaction error, but got %v", err)
+	}
+	var atErr *ATError
+	if !errors.As(err, &atErr) || atErr.Status != 3 {
+		t.Fatalf("Expected AT invalid parameter error, but got %v", err)
+	}
+
+	if applied["CH"] != "\x0B" || applied["ID"] != "\x00\x00\x00\x00\x00\x00\x00\x01" || applied["NI"] != "OLD" {
+		t.Fatalf("Expected previous values restored, but got %q", applied)
+	}
+
+	expected := []string{"CH", "ID", "NI", "CH", "ID", "NI", "CH", "ID", "NI", "AC"}
+	if len(*sent) != len(expected) {
+		t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+	}
+	for i := range expected {
+		if (*sent)[i] != expected[i] {
+			t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+		}
+	}
+}
+
+// cancelDevice cancels the commit's context when cmd is queued
+type cancelDevice struct {
+	Device
+	cmd    *at.Command
+	cancel context.CancelFunc
+}
+
+func (d *cancelDevice) QueueParameter(ctx context.Context, cmd *at.Command, v interface{}) error {
+	if cmd == d.cmd {
+		d.cmd = nil
+		d.cancel()
+		return ctx.Err()
+	}
+
+	return d.Device.QueueParameter(ctx, cmd, v)
+}
+
+// writeFailDevice fails its first WriteChanges
+type writeFailDevice struct {
+	Device
+	failed bool
+}
+
+func (d *writeFailDevice) WriteChanges(ctx context.Context) error {
+	if !d.failed {
+		d.failed = true
+		return errors.New("write failed")
+	}
+
+	return d.Device.WriteChanges(ctx)
+}
+
+func TestConfigTransaction_Rollback_Persist(t *testing.T) {
+	xbee, applied, sent := configRadio(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tr := NewConfigTransaction(&writeFailDevice{Device: xbee.Local()}, Persist(true))
+	tr.Stage(at.Channel, 0x0F)
+
+	var e *TransactionError
+	if err := tr.Commit(ctx); !errors.As(err, &e) || e.Rollback != nil {
+		t.Fatalf("Expected rolled back transaction error, but got %v", err)
+	}
+	if applied["CH"] != "\x0B" {
+		t.Fatalf("Expected previous values restored, but got %q", applied)
+	}
+
+	expected := []string{"CH", "CH", "AC", "CH", "AC", "WR"}
+	if len(*sent) != len(expected) {
+		t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+	}
+	for i := range expected {
+		if (*sent)[i] != expected[i] {
+			t.Fatalf("Expected commands %v, but got %v", expected, *sent)
+		}
+	}
+}
+
+func TestConfigTransaction_Rollback_Canceled(t *testing.T) {
+	xbee, applied, _ := configRadio(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tr := NewConfigTransaction(&cancelDevice{Device: xbee.Local(), cmd: at.NodeIdentifier, cancel: cancel})
+	tr.Stage(at.Channel, 0x0F)
+	tr.Stage(at.NodeIdentifier, "NEW")
+
+	var e *TransactionError
+	if err := tr.Commit(ctx); !errors.As(err, &e) || e.Err != context.Canceled || e.Rollback != nil {
+		t.Fatalf("Expected canceled transaction rolled back, but got %v", err)
+	}
+	if applied["CH"] != "\x0B" || applied["NI"] != "OLD" {
+		t.Fatalf("Expected previous values restored, but got %q", applied)
+	}
+}
@@ -1,6 +1,9 @@
 package gobee
 
 import (
+	"sync"
+	"time"
+
 	"github.com/pauleyj/gobee/api"
 	"github.com/pauleyj/gobee/api/rx"
 	"github.com/pauleyj/gobee/api/tx"
@@ -25,12 +28,23 @@ func APIEscapeMode(mode api.EscapeMode) func(interface{}) {
 	}
 }
 
+// APIProtocol helper option function to gobee.New, selects the protocol family
+// of the XBee's firmware, defaults to api.ProtocolZigbee
+func APIProtocol(protocol api.Protocol) func(interface{}) {
+	return func(i interface{}) {
+		if t, ok := i.(api.APIProtocolSetter); ok {
+			t.SetAPIProtocol(protocol)
+		}
+	}
+}
+
 // New constructor of XBee's
 func New(transmitter XBeeTransmitter, receiver XBeeReceiver, options ...func(interface{})) *XBee {
 	xbee := &XBee{
 		transmitter: transmitter,
 		receiver:    receiver,
 		frame:       rx.New(options...),
+		resolveTTL:  DefaultResolveTTL,
 	}
 
 	if options == nil || len(options) == 0 {
@@ -53,7 +67,14 @@ type XBee struct {
 	transmitter XBeeTransmitter
 	receiver    XBeeReceiver
 	apiMode     api.EscapeMode
+	protocol    api.Protocol
 	frame       *rx.APIFrame
+
+	mu         sync.Mutex
+	frameID    byte
+	listeners  map[*listener]struct{}
+	resolveTTL time.Duration
+	resolved   map[string]resolution
 }
 
 // SetAPIEscapeMode satisfy APIEscapeModeSetter interface
@@ -61,6 +82,16 @@ func (x *XBee) SetAPIEscapeMode(mode api.EscapeMode) {
 	x.apiMode = mode
 }
 
+// SetAPIProtocol satisfy APIProtocolSetter interface
+func (x *XBee) SetAPIProtocol(protocol api.Protocol) {
+	x.protocol = protocol
+}
+
+// Protocol protocol family of the XBee's firmware
+func (x *XBee) Protocol() api.Protocol {
+	return x.protocol
+}
+
 // RX bytes received from the serial communications port are sent here
 func (x *XBee) RX(b byte) error {
 	f, err := x.frame.RX(b)
@@ -70,6 +101,7 @@ func (x *XBee) RX(b byte) error {
 
 	if f != nil {
 		x.receiver.Receive(f)
+		x.dispatch(f)
 	}
 
 	return nil
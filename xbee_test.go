@@ -2,6 +2,7 @@ package gobee
 
 import (
 	"fmt"
+	"sync"
 
 	"testing"
 
@@ -64,6 +65,49 @@ func (f *dummyFrame) Bytes() ([]byte, error) {
 	return f.data, nil
 }
 
+// radio fakes the XBee at the other end of the serial port, reply returns the
+// frame data the radio answers a transmitted frame's data with
+type radio struct {
+	mu    sync.Mutex
+	xbee  *XBee
+	reply func(p []byte) [][]byte
+}
+
+// newRadio constructs an XBee, with escaping inactive, talking to a fake radio
+func newRadio(reply func(p []byte) [][]byte, options ...func(interface{})) (*XBee, *radio) {
+	r := &radio{reply: reply}
+	r.xbee = New(r, &nopReceiver{}, append([]func(interface{}){APIEscapeMode(api.EscapeModeInactive)}, options...)...)
+
+	return r.xbee, r
+}
+
+func (r *radio) Transmit(p []byte) (int, error) {
+	frames := r.reply(p[3 : len(p)-1])
+	go r.send(frames...)
+
+	return len(p), nil
+}
+
+// send feeds frame data to the XBee as complete API frames
+func (r *radio) send(frames ...[]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := tx.New(api.APIEscapeMode(api.EscapeModeInactive))
+	for _, frame := range frames {
+		p, _ := f.Bytes(&dummyFrame{frame})
+		for _, b := range p {
+			r.xbee.RX(b)
+		}
+	}
+}
+
+type nopReceiver struct{}
+
+func (r *nopReceiver) Receive(f rx.Frame) error {
+	return nil
+}
+
 type xbeeTXTest struct {
 	name     string
 	xbeeFunc func(*testing.T) *XBee
@@ -247,3 +291,39 @@ func TestXBee_RX_Invalid_Checksum(t *testing.T) {
 		}
 	}
 }
+
+type frameReceiver struct {
+	frames []rx.Frame
+}
+
+func (r *frameReceiver) Receive(f rx.Frame) error {
+	r.frames = append(r.frames, f)
+
+	return nil
+}
+
+func TestXBee_RX_802154(t *testing.T) {
+	transmitter := &Transmitter{t: t}
+	receiver := &frameReceiver{}
+	xbee := New(transmitter, receiver, APIProtocol(api.Protocol802154))
+
+	if xbee.Protocol() != api.Protocol802154 {
+		t.Fatalf("Expected protocol %d, but got %d", api.Protocol802154, xbee.Protocol())
+	}
+
+	// an 802.15.4 TX status
+	status := []byte{0x7E, 0x00, 0x03, 0x89, 0x01, 0x00, 0x75}
+	for _, b := range status {
+		err := xbee.RX(b)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	}
+
+	if len(receiver.frames) != 1 {
+		t.Fatalf("Expected 1 frame, but got %d", len(receiver.frames))
+	}
+	if _, ok := receiver.frames[0].(*rx.LegacyTXStatus); !ok {
+		t.Fatalf("Expected *rx.LegacyTXStatus, but got %T", receiver.frames[0])
+	}
+}
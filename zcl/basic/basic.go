@@ -0,0 +1,105 @@
+// Package basic is a client of the ZCL Basic cluster.
+package basic
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Basic cluster ID
+const Cluster uint16 = 0x0000
+
+// Attributes
+const (
+	AttrZCLVersion         uint16 = 0x0000
+	AttrApplicationVersion uint16 = 0x0001
+	AttrStackVersion       uint16 = 0x0002
+	AttrHWVersion          uint16 = 0x0003
+	AttrManufacturerName   uint16 = 0x0004
+	AttrModelIdentifier    uint16 = 0x0005
+	AttrDateCode           uint16 = 0x0006
+	AttrPowerSource        uint16 = 0x0007
+	AttrSWBuildID          uint16 = 0x4000
+)
+
+// Commands
+const (
+	CommandResetToFactoryDefaults byte = 0x00
+)
+
+// Power sources, the high bit set indicates a secondary battery backup
+const (
+	PowerUnknown         byte = 0x00
+	PowerMains           byte = 0x01
+	PowerMainsThreePhase byte = 0x02
+	PowerBattery         byte = 0x03
+	PowerDC              byte = 0x04
+	PowerEmergency       byte = 0x05
+)
+
+// Info the Basic cluster attributes identifying a device, attributes the
+// device does not support are left zero
+type Info struct {
+	ZCLVersion         uint8
+	ApplicationVersion uint8
+	StackVersion       uint8
+	HWVersion          uint8
+	ManufacturerName   string
+	ModelIdentifier    string
+	DateCode           string
+	PowerSource        uint8
+	SWBuildID          string
+}
+
+// Read reads the endpoint's identifying attributes
+func Read(ctx context.Context, c *zcl.Client, dst zcl.Address) (*Info, error) {
+	records, err := c.ReadAttributes(ctx, dst, Cluster,
+		AttrZCLVersion, AttrApplicationVersion, AttrStackVersion, AttrHWVersion,
+		AttrManufacturerName, AttrModelIdentifier, AttrDateCode, AttrPowerSource, AttrSWBuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	for _, r := range records {
+		if r.Status != zcl.StatusSuccess {
+			continue
+		}
+
+		switch v := r.Value.(type) {
+		case uint8:
+			switch r.ID {
+			case AttrZCLVersion:
+				info.ZCLVersion = v
+			case AttrApplicationVersion:
+				info.ApplicationVersion = v
+			case AttrStackVersion:
+				info.StackVersion = v
+			case AttrHWVersion:
+				info.HWVersion = v
+			case AttrPowerSource:
+				info.PowerSource = v
+			}
+		case string:
+			switch r.ID {
+			case AttrManufacturerName:
+				info.ManufacturerName = v
+			case AttrModelIdentifier:
+				info.ModelIdentifier = v
+			case AttrDateCode:
+				info.DateCode = v
+			case AttrSWBuildID:
+				info.SWBuildID = v
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// ResetToFactoryDefaults resets the endpoint's clusters to their factory defaults
+func ResetToFactoryDefaults(ctx context.Context, c *zcl.Client, dst zcl.Address) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandResetToFactoryDefaults, nil)
+	return err
+}
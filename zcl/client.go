@@ -0,0 +1,307 @@
+package zcl
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// ProfileHomeAutomation Zigbee Home Automation profile ID
+const ProfileHomeAutomation uint16 = 0x0104
+
+// Addr64Unknown 64-bit address used when addressing by 16-bit address only
+const Addr64Unknown uint64 = 0xFFFFFFFFFFFFFFFF
+
+var (
+	// ErrResponse response is not the expected command
+	ErrResponse = errors.New("unexpected ZCL response")
+	// ErrMulticast multicast commands are not answered
+	ErrMulticast = errors.New("no response to multicast")
+)
+
+// Address an endpoint reached with explicit addressing frames
+type Address struct {
+	Addr64 uint64
+	Addr16 uint16
+	// SrcEndpoint local endpoint
+	SrcEndpoint byte
+	// DstEndpoint remote endpoint
+	DstEndpoint byte
+	ProfileID   uint16
+	// Multicast Addr16 is a group ID
+	Multicast bool
+}
+
+// Group the address of group id, reached by multicast from the local endpoint
+func Group(id uint16, srcEndpoint byte, profileID uint16) Address {
+	return Address{
+		Addr64:      Addr64Unknown,
+		Addr16:      id,
+		SrcEndpoint: srcEndpoint,
+		DstEndpoint: 0xFF,
+		ProfileID:   profileID,
+		Multicast:   true,
+	}
+}
+
+// Options tx.NewZBExplicit options sending data on cluster to the address
+func (a Address) Options(cluster uint16, data []byte) []func(interface{}) {
+	options := []func(interface{}){
+		tx.Addr64(a.Addr64),
+		tx.Addr16(a.Addr16),
+		tx.SrcEP(a.SrcEndpoint),
+		tx.DstEP(a.DstEndpoint),
+		tx.ClusterID(cluster),
+		tx.ProfileID(a.ProfileID),
+		tx.Data(data),
+	}
+	if a.Multicast {
+		options = append(options, tx.Options(tx.OptionMulticast))
+	}
+
+	return options
+}
+
+// from is the explicit frame from the address' remote endpoint
+func (a Address) from(f *rx.ZBExplicit) bool {
+	if f.SrcEP() != a.DstEndpoint || f.DstEP() != a.SrcEndpoint || f.ProfileID() != a.ProfileID {
+		return false
+	}
+	if a.Addr64 != Addr64Unknown {
+		return f.Addr64() == a.Addr64
+	}
+
+	return f.Addr16() == a.Addr16
+}
+
+// Client sends ZCL frames through the local XBee, correlating responses by
+// sequence number. Receiving responses requires the local XBee's AO set to 1.
+type Client struct {
+	xbee *gobee.XBee
+
+	mu  sync.Mutex
+	seq byte
+}
+
+// New constructs a ZCL Client
+func New(xbee *gobee.XBee) *Client {
+	return &Client{xbee: xbee}
+}
+
+// NextSeq next ZCL transaction sequence number
+func (c *Client) NextSeq() byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+
+	return c.seq
+}
+
+// Send transmits the frame on cluster to dst and waits for the transmit status
+func (c *Client) Send(ctx context.Context, dst Address, cluster uint16, f *Frame) error {
+	return c.xbee.SendExplicit(ctx, dst.Options(cluster, f.Bytes())...)
+}
+
+// Request gives the frame the next sequence number, transmits it on cluster to
+// dst and waits for the response with the same sequence number. A Default
+// Response reporting failure returns a *StatusError. Multicast destinations
+// return ErrMulticast.
+func (c *Client) Request(ctx context.Context, dst Address, cluster uint16, f *Frame) (*Frame, error) {
+	if dst.Multicast {
+		return nil, ErrMulticast
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f.Seq = c.NextSeq()
+
+	responses := c.xbee.Subscribe(ctx, func(frame rx.Frame) bool {
+		e, ok := frame.(*rx.ZBExplicit)
+		if !ok || e.ClusterID() != cluster || !dst.from(e) {
+			return false
+		}
+
+		r, err := Parse(e.Data())
+		return err == nil && r.Seq == f.Seq && r.Direction != f.Direction
+	})
+
+	if err := c.Send(ctx, dst, cluster, f); err != nil {
+		return nil, err
+	}
+
+	select {
+	case frame, ok := <-responses:
+		if !ok {
+			return nil, ctx.Err()
+		}
+
+		r, _ := Parse(frame.(*rx.ZBExplicit).Data())
+		if r.Type == FrameGeneral && r.Command == CommandDefaultResponse {
+			command, status, err := DecodeDefaultResponse(r.Payload)
+			if err != nil {
+				return nil, err
+			}
+			if status != StatusSuccess {
+				return nil, &StatusError{Cluster: cluster, Command: command, Status: status}
+			}
+		}
+
+		return r, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// general sends a general command and returns the payload of the expected response
+func (c *Client) general(ctx context.Context, dst Address, cluster uint16, command, response byte, payload []byte) ([]byte, error) {
+	r, err := c.Request(ctx, dst, cluster, &Frame{
+		Header:  Header{Type: FrameGeneral, Direction: ClientToServer, Command: command},
+		Payload: payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Type != FrameGeneral || r.Command != response {
+		return nil, ErrResponse
+	}
+
+	return r.Payload, nil
+}
+
+// ReadAttributes reads the attributes of cluster on dst
+func (c *Client) ReadAttributes(ctx context.Context, dst Address, cluster uint16, ids ...uint16) ([]ReadRecord, error) {
+	p, err := c.general(ctx, dst, cluster, CommandReadAttributes, CommandReadAttributesResponse, EncodeReadAttributes(ids...))
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeReadAttributesResponse(p)
+}
+
+// WriteAttributes writes the attributes of cluster on dst, returning the
+// records of attributes that failed to write
+func (c *Client) WriteAttributes(ctx context.Context, dst Address, cluster uint16, attributes ...Attribute) ([]WriteRecord, error) {
+	payload, err := EncodeAttributes(attributes...)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.general(ctx, dst, cluster, CommandWriteAttributes, CommandWriteAttributesResponse, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeWriteAttributesResponse(p)
+}
+
+// ConfigureReporting configures reporting of the attributes of cluster on
+// dst, returning the records of attributes that failed to configure
+func (c *Client) ConfigureReporting(ctx context.Context, dst Address, cluster uint16, configs ...ReportingConfig) ([]ReportingRecord, error) {
+	payload, err := EncodeConfigureReporting(configs...)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.general(ctx, dst, cluster, CommandConfigureReporting, CommandConfigureReportingResponse, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeConfigureReportingResponse(p)
+}
+
+// DiscoverAttributes discovers up to max attributes of cluster on dst from
+// attribute start, complete reports whether every attribute has been discovered
+func (c *Client) DiscoverAttributes(ctx context.Context, dst Address, cluster uint16, start uint16, max byte) (bool, []AttributeInfo, error) {
+	p, err := c.general(ctx, dst, cluster, CommandDiscoverAttributes, CommandDiscoverAttributesResponse, EncodeDiscoverAttributes(start, max))
+	if err != nil {
+		return false, nil, err
+	}
+
+	return DecodeDiscoverAttributesResponse(p)
+}
+
+// ReadAttribute reads a single attribute of cluster on dst, an attribute that
+// cannot be read returns a *StatusError
+func (c *Client) ReadAttribute(ctx context.Context, dst Address, cluster uint16, id uint16) (interface{}, error) {
+	records, err := c.ReadAttributes(ctx, dst, cluster, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range records {
+		if r.ID != id {
+			continue
+		}
+
+		if r.Status != StatusSuccess {
+			return nil, &StatusError{Cluster: cluster, Command: CommandReadAttributes, Status: r.Status}
+		}
+
+		return r.Value, nil
+	}
+
+	return nil, ErrResponse
+}
+
+// WriteAttribute writes a single attribute of cluster on dst, a failed write
+// returns a *StatusError
+func (c *Client) WriteAttribute(ctx context.Context, dst Address, cluster uint16, a Attribute) error {
+	records, err := c.WriteAttributes(ctx, dst, cluster, a)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if r.Status != StatusSuccess {
+			return &StatusError{Cluster: cluster, Command: CommandWriteAttributes, Status: r.Status}
+		}
+	}
+
+	return nil
+}
+
+// Command sends a cluster specific command to dst and waits for the
+// response, either the cluster's response command or a Default Response.
+// Commands to a multicast destination only wait for the transmit status and
+// return no response.
+func (c *Client) Command(ctx context.Context, dst Address, cluster uint16, command byte, payload []byte) (*Frame, error) {
+	f := &Frame{
+		Header:  Header{Type: FrameCluster, Direction: ClientToServer, Command: command},
+		Payload: payload,
+	}
+
+	if dst.Multicast {
+		f.DisableDefaultResponse = true
+		f.Seq = c.NextSeq()
+		return nil, c.Send(ctx, dst, cluster, f)
+	}
+
+	return c.Request(ctx, dst, cluster, f)
+}
+
+// CommandResponse sends a cluster specific command to dst and returns the
+// payload of the cluster's response command
+func (c *Client) CommandResponse(ctx context.Context, dst Address, cluster uint16, command, response byte, payload []byte) ([]byte, error) {
+	if dst.Multicast {
+		return nil, ErrMulticast
+	}
+
+	r, err := c.Command(ctx, dst, cluster, command, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Type != FrameCluster || r.Command != response {
+		return nil, ErrResponse
+	}
+
+	return r.Payload, nil
+}
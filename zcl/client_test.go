@@ -0,0 +1,178 @@
+package zcl
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+var testAddress = Address{
+	Addr64:      0x0013A20040522BAA,
+	Addr16:      0x1234,
+	SrcEndpoint: 0xE8,
+	DstEndpoint: 0x01,
+	ProfileID:   ProfileHomeAutomation,
+}
+
+// radio a fake XBee answering transmitted frame data with reply
+type radio struct {
+	mu    sync.Mutex
+	xbee  *gobee.XBee
+	reply func(p []byte) [][]byte
+}
+
+type nopReceiver struct{}
+
+func (r *nopReceiver) Receive(f rx.Frame) error {
+	return nil
+}
+
+func newRadio(reply func(p []byte) [][]byte) *gobee.XBee {
+	r := &radio{reply: reply}
+	r.xbee = gobee.New(r, &nopReceiver{}, gobee.APIEscapeMode(api.EscapeModeInactive))
+
+	return r.xbee
+}
+
+func (r *radio) Transmit(p []byte) (int, error) {
+	frames := r.reply(p[3 : len(p)-1])
+	go r.send(frames...)
+
+	return len(p), nil
+}
+
+func (r *radio) send(frames ...[]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range frames {
+		sum := byte(0)
+		for _, b := range f {
+			sum += b
+		}
+
+		r.xbee.RX(0x7E)
+		r.xbee.RX(byte(len(f) >> 8))
+		r.xbee.RX(byte(len(f)))
+		for _, b := range f {
+			r.xbee.RX(b)
+		}
+		r.xbee.RX(0xFF - sum)
+	}
+}
+
+// explicitRequest an explicit addressing frame as seen by the fake radio
+type explicitRequest struct {
+	id      byte
+	cluster uint16
+	frame   *Frame
+}
+
+func parseRequest(t *testing.T, p []byte) explicitRequest {
+	if p[0] != 0x11 || p[12] != 0xE8 || p[13] != 0x01 || p[16] != 0x01 || p[17] != 0x04 {
+		t.Fatalf("Expected explicit frame to endpoint 1, but got % x", p)
+	}
+
+	f, err := Parse(p[20:])
+	if err != nil {
+		t.Fatalf("Expected ZCL frame, but got %v", err)
+	}
+
+	return explicitRequest{id: p[1], cluster: uint16(p[14])<<8 | uint16(p[15]), frame: f}
+}
+
+// replies transmit status and ZCL response frame from testAddress to r
+func replies(r explicitRequest, response *Frame) [][]byte {
+	a := testAddress.Addr64
+	e := []byte{0x91,
+		byte(a >> 56), byte(a >> 48), byte(a >> 40), byte(a >> 32),
+		byte(a >> 24), byte(a >> 16), byte(a >> 8), byte(a),
+		0x12, 0x34, 0x01, 0xE8, byte(r.cluster >> 8), byte(r.cluster), 0x01, 0x04, 0x01}
+
+	response.Seq = r.frame.Seq
+	response.Direction = ServerToClient
+
+	return [][]byte{
+		{0x8B, r.id, 0x12, 0x34, 0x00, 0x00, 0x00},
+		append(e, response.Bytes()...),
+	}
+}
+
+func TestClient_ReadAttributes(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != 0x0402 || r.frame.Command != CommandReadAttributes || !reflect.DeepEqual(r.frame.Payload, []byte{0x00, 0x00}) {
+			t.Fatalf("Expected Read Attributes of 0x0000, but got %+v", r)
+		}
+
+		payload, _ := EncodeReadAttributesResponse(ReadRecord{ID: 0x0000, Type: TypeInt16, Value: int16(2150)})
+		return replies(r, &Frame{Header: Header{Command: CommandReadAttributesResponse}, Payload: payload})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	records, err := New(xbee).ReadAttributes(ctx, testAddress, 0x0402, 0x0000)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []ReadRecord{{ID: 0x0000, Status: StatusSuccess, Type: TypeInt16, Value: int16(2150)}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, records)
+	}
+}
+
+func TestClient_Request_Default_Response(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		return replies(r, &Frame{
+			Header:  Header{Command: CommandDefaultResponse},
+			Payload: EncodeDefaultResponse(r.frame.Command, StatusUnsupportedGeneralCommand),
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, _, err := New(xbee).DiscoverAttributes(ctx, testAddress, 0x0402, 0x0000, 10)
+	if e, ok := err.(*StatusError); !ok || e.Status != StatusUnsupportedGeneralCommand || e.Command != CommandDiscoverAttributes {
+		t.Fatalf("Expected unsupported general command status error, but got %v", err)
+	}
+}
+
+func TestClient_Command_Multicast(t *testing.T) {
+	group := Group(0x0010, 0xE8, ProfileHomeAutomation)
+
+	xbee := newRadio(func(p []byte) [][]byte {
+		if p[0] != 0x11 || !bytes.Equal(p[2:10], []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}) ||
+			p[10] != 0x00 || p[11] != 0x10 || p[19]&0x08 == 0 {
+			t.Fatalf("Expected multicast to group 0x0010, but got % x", p)
+		}
+
+		f, err := Parse(p[20:])
+		if err != nil || f.Type != FrameCluster || f.Command != 0x02 || !f.DisableDefaultResponse {
+			t.Fatalf("Expected cluster command without default response, but got %+v %v", f, err)
+		}
+
+		return [][]byte{{0x8B, p[1], 0xFF, 0xFE, 0x00, 0x00, 0x00}}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	c := New(xbee)
+	if f, err := c.Command(ctx, group, 0x0006, 0x02, nil); f != nil || err != nil {
+		t.Fatalf("Expected no response and no error, but got %+v, %v", f, err)
+	}
+	if _, err := c.ReadAttributes(ctx, group, 0x0006, 0x0000); err != ErrMulticast {
+		t.Fatalf("Expected ErrMulticast, but got %v", err)
+	}
+}
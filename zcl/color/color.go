@@ -0,0 +1,146 @@
+// Package color is a client of the ZCL Color Control cluster.
+package color
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Color Control cluster ID
+const Cluster uint16 = 0x0300
+
+// Attributes
+const (
+	AttrCurrentHue        uint16 = 0x0000
+	AttrCurrentSaturation uint16 = 0x0001
+	AttrRemainingTime     uint16 = 0x0002
+	AttrCurrentX          uint16 = 0x0003
+	AttrCurrentY          uint16 = 0x0004
+	AttrColorTemperature  uint16 = 0x0007
+	AttrColorMode         uint16 = 0x0008
+)
+
+// Commands
+const (
+	CommandMoveToHue              byte = 0x00
+	CommandMoveToSaturation       byte = 0x03
+	CommandMoveToHueAndSaturation byte = 0x06
+	CommandMoveToColor            byte = 0x07
+	CommandMoveToColorTemperature byte = 0x0A
+)
+
+// Direction defines hue move directions
+type Direction byte
+
+// Hue move directions
+const (
+	ShortestDistance = Direction(0x00)
+	LongestDistance  = Direction(0x01)
+	Up               = Direction(0x02)
+	Down             = Direction(0x03)
+)
+
+// Color modes
+const (
+	ModeHueSaturation    byte = 0x00
+	ModeXY               byte = 0x01
+	ModeColorTemperature byte = 0x02
+)
+
+// MoveToHue moves the endpoint's hue, 0 to 254, over transition tenths of a second
+func MoveToHue(ctx context.Context, c *zcl.Client, dst zcl.Address, hue byte, direction Direction, transition uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandMoveToHue, []byte{hue, byte(direction), byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// MoveToSaturation moves the endpoint's saturation, 0 to 254, over transition
+// tenths of a second
+func MoveToSaturation(ctx context.Context, c *zcl.Client, dst zcl.Address, saturation byte, transition uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandMoveToSaturation, []byte{saturation, byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// MoveToHueAndSaturation moves the endpoint's hue and saturation over
+// transition tenths of a second
+func MoveToHueAndSaturation(ctx context.Context, c *zcl.Client, dst zcl.Address, hue, saturation byte, transition uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandMoveToHueAndSaturation, []byte{hue, saturation, byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// MoveToColor moves the endpoint to the CIE 1931 color x, y, each scaled by
+// 65536, over transition tenths of a second
+func MoveToColor(ctx context.Context, c *zcl.Client, dst zcl.Address, x, y uint16, transition uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandMoveToColor, []byte{byte(x), byte(x >> 8), byte(y), byte(y >> 8), byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// MoveToColorTemperature moves the endpoint to the color temperature in
+// mireds over transition tenths of a second
+func MoveToColorTemperature(ctx context.Context, c *zcl.Client, dst zcl.Address, mireds uint16, transition uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandMoveToColorTemperature, []byte{byte(mireds), byte(mireds >> 8), byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// HueSaturation reads the endpoint's current hue and saturation
+func HueSaturation(ctx context.Context, c *zcl.Client, dst zcl.Address) (byte, byte, error) {
+	hue, err := readUint8(ctx, c, dst, AttrCurrentHue)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	saturation, err := readUint8(ctx, c, dst, AttrCurrentSaturation)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return hue, saturation, nil
+}
+
+// XY reads the endpoint's current CIE 1931 color x, y
+func XY(ctx context.Context, c *zcl.Client, dst zcl.Address) (uint16, uint16, error) {
+	x, err := readUint16(ctx, c, dst, AttrCurrentX)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	y, err := readUint16(ctx, c, dst, AttrCurrentY)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return x, y, nil
+}
+
+// ColorTemperature reads the endpoint's color temperature in mireds
+func ColorTemperature(ctx context.Context, c *zcl.Client, dst zcl.Address) (uint16, error) {
+	return readUint16(ctx, c, dst, AttrColorTemperature)
+}
+
+func readUint8(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16) (byte, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, id)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := v.(uint8)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return n, nil
+}
+
+func readUint16(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16) (uint16, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, id)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := v.(uint16)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return n, nil
+}
@@ -0,0 +1,375 @@
+package zcl
+
+import "encoding/binary"
+
+// General command IDs
+const (
+	CommandReadAttributes             byte = 0x00
+	CommandReadAttributesResponse     byte = 0x01
+	CommandWriteAttributes            byte = 0x02
+	CommandWriteAttributesUndivided   byte = 0x03
+	CommandWriteAttributesResponse    byte = 0x04
+	CommandWriteAttributesNoResponse  byte = 0x05
+	CommandConfigureReporting         byte = 0x06
+	CommandConfigureReportingResponse byte = 0x07
+	CommandReportAttributes           byte = 0x0A
+	CommandDefaultResponse            byte = 0x0B
+	CommandDiscoverAttributes         byte = 0x0C
+	CommandDiscoverAttributesResponse byte = 0x0D
+)
+
+// Attribute an attribute value
+type Attribute struct {
+	ID    uint16
+	Type  DataType
+	Value interface{}
+}
+
+// ReadRecord a Read Attributes Response record, Type and Value are only
+// present when Status is success
+type ReadRecord struct {
+	ID     uint16
+	Status Status
+	Type   DataType
+	Value  interface{}
+}
+
+// WriteRecord a Write Attributes Response record
+type WriteRecord struct {
+	Status Status
+	ID     uint16
+}
+
+// ReportingConfig a Configure Reporting record for an attribute reported by
+// the server. ReportableChange is only sent for analog data types.
+type ReportingConfig struct {
+	ID   uint16
+	Type DataType
+	// MinInterval and MaxInterval in seconds, a MaxInterval of 0xFFFF stops reporting
+	MinInterval      uint16
+	MaxInterval      uint16
+	ReportableChange interface{}
+}
+
+// ReportingRecord a Configure Reporting Response record
+type ReportingRecord struct {
+	Status    Status
+	Direction Direction
+	ID        uint16
+}
+
+// AttributeInfo a Discover Attributes Response record
+type AttributeInfo struct {
+	ID   uint16
+	Type DataType
+}
+
+// EncodeReadAttributes encodes a Read Attributes payload
+func EncodeReadAttributes(ids ...uint16) []byte {
+	p := make([]byte, 0, 2*len(ids))
+	for _, id := range ids {
+		p = appendUint16(p, id)
+	}
+
+	return p
+}
+
+// DecodeReadAttributes decodes a Read Attributes payload
+func DecodeReadAttributes(p []byte) ([]uint16, error) {
+	if len(p)%2 != 0 {
+		return nil, ErrLength
+	}
+
+	ids := make([]uint16, len(p)/2)
+	for i := range ids {
+		ids[i] = binary.LittleEndian.Uint16(p[2*i:])
+	}
+
+	return ids, nil
+}
+
+// EncodeReadAttributesResponse encodes a Read Attributes Response payload
+func EncodeReadAttributesResponse(records ...ReadRecord) ([]byte, error) {
+	var p []byte
+	for _, r := range records {
+		p = append(appendUint16(p, r.ID), byte(r.Status))
+		if r.Status != StatusSuccess {
+			continue
+		}
+
+		v, err := EncodeValue(r.Type, r.Value)
+		if err != nil {
+			return nil, err
+		}
+		p = append(append(p, byte(r.Type)), v...)
+	}
+
+	return p, nil
+}
+
+// DecodeReadAttributesResponse decodes a Read Attributes Response payload
+func DecodeReadAttributesResponse(p []byte) ([]ReadRecord, error) {
+	var records []ReadRecord
+	for len(p) > 0 {
+		if len(p) < 3 {
+			return nil, ErrLength
+		}
+
+		r := ReadRecord{ID: binary.LittleEndian.Uint16(p), Status: Status(p[2])}
+		p = p[3:]
+
+		if r.Status == StatusSuccess {
+			if len(p) < 1 {
+				return nil, ErrLength
+			}
+
+			r.Type = DataType(p[0])
+			v, n, err := DecodeValue(r.Type, p[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			r.Value = v
+			p = p[1+n:]
+		}
+
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// EncodeAttributes encodes the attribute records of Write Attributes and
+// Report Attributes payloads
+func EncodeAttributes(attributes ...Attribute) ([]byte, error) {
+	var p []byte
+	for _, a := range attributes {
+		v, err := EncodeValue(a.Type, a.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		p = append(append(appendUint16(p, a.ID), byte(a.Type)), v...)
+	}
+
+	return p, nil
+}
+
+// DecodeAttributes decodes the attribute records of Write Attributes and
+// Report Attributes payloads
+func DecodeAttributes(p []byte) ([]Attribute, error) {
+	var attributes []Attribute
+	for len(p) > 0 {
+		if len(p) < 3 {
+			return nil, ErrLength
+		}
+
+		a := Attribute{ID: binary.LittleEndian.Uint16(p), Type: DataType(p[2])}
+		v, n, err := DecodeValue(a.Type, p[3:])
+		if err != nil {
+			return nil, err
+		}
+
+		a.Value = v
+		attributes = append(attributes, a)
+		p = p[3+n:]
+	}
+
+	return attributes, nil
+}
+
+// EncodeWriteAttributesResponse encodes a Write Attributes Response payload,
+// records for successful writes are omitted and a single success status sent
+// when every write succeeded
+func EncodeWriteAttributesResponse(records ...WriteRecord) []byte {
+	var p []byte
+	for _, r := range records {
+		if r.Status == StatusSuccess {
+			continue
+		}
+
+		p = appendUint16(append(p, byte(r.Status)), r.ID)
+	}
+
+	if len(p) == 0 {
+		return []byte{byte(StatusSuccess)}
+	}
+
+	return p
+}
+
+// DecodeWriteAttributesResponse decodes a Write Attributes Response payload,
+// a single success status decodes to no records
+func DecodeWriteAttributesResponse(p []byte) ([]WriteRecord, error) {
+	if len(p) == 1 && Status(p[0]) == StatusSuccess {
+		return nil, nil
+	}
+	if len(p)%3 != 0 {
+		return nil, ErrLength
+	}
+
+	records := make([]WriteRecord, len(p)/3)
+	for i := range records {
+		records[i] = WriteRecord{Status: Status(p[3*i]), ID: binary.LittleEndian.Uint16(p[3*i+1:])}
+	}
+
+	return records, nil
+}
+
+// EncodeConfigureReporting encodes a Configure Reporting payload of records
+// for attributes reported by the server
+func EncodeConfigureReporting(configs ...ReportingConfig) ([]byte, error) {
+	var p []byte
+	for _, c := range configs {
+		p = append(appendUint16(append(p, byte(ClientToServer)), c.ID), byte(c.Type))
+		p = appendUint16(appendUint16(p, c.MinInterval), c.MaxInterval)
+
+		if c.Type.IsAnalog() {
+			v, err := EncodeValue(c.Type, c.ReportableChange)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, v...)
+		}
+	}
+
+	return p, nil
+}
+
+// DecodeConfigureReporting decodes a Configure Reporting payload, records for
+// attributes received by the server are not supported
+func DecodeConfigureReporting(p []byte) ([]ReportingConfig, error) {
+	var configs []ReportingConfig
+	for len(p) > 0 {
+		if len(p) < 8 {
+			return nil, ErrLength
+		}
+		if Direction(p[0]) != ClientToServer {
+			return nil, ErrValue
+		}
+
+		c := ReportingConfig{
+			ID:          binary.LittleEndian.Uint16(p[1:]),
+			Type:        DataType(p[3]),
+			MinInterval: binary.LittleEndian.Uint16(p[4:]),
+			MaxInterval: binary.LittleEndian.Uint16(p[6:]),
+		}
+		p = p[8:]
+
+		if c.Type.IsAnalog() {
+			v, n, err := DecodeValue(c.Type, p)
+			if err != nil {
+				return nil, err
+			}
+
+			c.ReportableChange = v
+			p = p[n:]
+		}
+
+		configs = append(configs, c)
+	}
+
+	return configs, nil
+}
+
+// EncodeConfigureReportingResponse encodes a Configure Reporting Response
+// payload, a single success status is sent when every record succeeded
+func EncodeConfigureReportingResponse(records ...ReportingRecord) []byte {
+	var p []byte
+	for _, r := range records {
+		if r.Status == StatusSuccess {
+			continue
+		}
+
+		p = appendUint16(append(p, byte(r.Status), byte(r.Direction)), r.ID)
+	}
+
+	if len(p) == 0 {
+		return []byte{byte(StatusSuccess)}
+	}
+
+	return p
+}
+
+// DecodeConfigureReportingResponse decodes a Configure Reporting Response
+// payload, a single success status decodes to no records
+func DecodeConfigureReportingResponse(p []byte) ([]ReportingRecord, error) {
+	if len(p) == 1 && Status(p[0]) == StatusSuccess {
+		return nil, nil
+	}
+	if len(p)%4 != 0 {
+		return nil, ErrLength
+	}
+
+	records := make([]ReportingRecord, len(p)/4)
+	for i := range records {
+		records[i] = ReportingRecord{
+			Status:    Status(p[4*i]),
+			Direction: Direction(p[4*i+1]),
+			ID:        binary.LittleEndian.Uint16(p[4*i+2:]),
+		}
+	}
+
+	return records, nil
+}
+
+// EncodeDefaultResponse encodes a Default Response payload
+func EncodeDefaultResponse(command byte, status Status) []byte {
+	return []byte{command, byte(status)}
+}
+
+// DecodeDefaultResponse decodes a Default Response payload
+func DecodeDefaultResponse(p []byte) (byte, Status, error) {
+	if len(p) < 2 {
+		return 0, 0, ErrLength
+	}
+
+	return p[0], Status(p[1]), nil
+}
+
+// EncodeDiscoverAttributes encodes a Discover Attributes payload
+func EncodeDiscoverAttributes(start uint16, max byte) []byte {
+	return append(appendUint16(nil, start), max)
+}
+
+// DecodeDiscoverAttributes decodes a Discover Attributes payload
+func DecodeDiscoverAttributes(p []byte) (uint16, byte, error) {
+	if len(p) < 3 {
+		return 0, 0, ErrLength
+	}
+
+	return binary.LittleEndian.Uint16(p), p[2], nil
+}
+
+// EncodeDiscoverAttributesResponse encodes a Discover Attributes Response payload
+func EncodeDiscoverAttributesResponse(complete bool, attributes ...AttributeInfo) []byte {
+	p := []byte{0}
+	if complete {
+		p[0] = 1
+	}
+
+	for _, a := range attributes {
+		p = append(appendUint16(p, a.ID), byte(a.Type))
+	}
+
+	return p
+}
+
+// DecodeDiscoverAttributesResponse decodes a Discover Attributes Response
+// payload, complete reports whether every attribute has been discovered
+func DecodeDiscoverAttributesResponse(p []byte) (bool, []AttributeInfo, error) {
+	if len(p) < 1 || (len(p)-1)%3 != 0 {
+		return false, nil, ErrLength
+	}
+
+	attributes := make([]AttributeInfo, (len(p)-1)/3)
+	for i := range attributes {
+		attributes[i] = AttributeInfo{ID: binary.LittleEndian.Uint16(p[1+3*i:]), Type: DataType(p[3+3*i])}
+	}
+
+	return p[0] != 0, attributes, nil
+}
+
+func appendUint16(p []byte, n uint16) []byte {
+	return append(p, byte(n), byte(n>>8))
+}
@@ -0,0 +1,125 @@
+package zcl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrame(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		frame    *Frame
+		expected []byte
+	}{
+		{"General", &Frame{Header: Header{Type: FrameGeneral, Seq: 0x01, Command: CommandReadAttributes}, Payload: []byte{0x00, 0x00}},
+			[]byte{0x00, 0x01, 0x00, 0x00, 0x00}},
+		{"Cluster Server To Client", &Frame{Header: Header{Type: FrameCluster, Direction: ServerToClient, DisableDefaultResponse: true, Seq: 0x02, Command: 0x01}},
+			[]byte{0x19, 0x02, 0x01}},
+		{"Manufacturer Specific", &Frame{Header: Header{Type: FrameCluster, ManufacturerSpecific: true, ManufacturerCode: 0x101E, Seq: 0x03, Command: 0x02}, Payload: []byte{0xFF}},
+			[]byte{0x05, 0x1E, 0x10, 0x03, 0x02, 0xFF}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := tt.frame.Bytes()
+			if !reflect.DeepEqual(p, tt.expected) {
+				t.Fatalf("Expected % x, but got % x", tt.expected, p)
+			}
+
+			f, err := Parse(p)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if !reflect.DeepEqual(f, tt.frame) {
+				t.Fatalf("Expected %+v, but got %+v", tt.frame, f)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte{0x04, 0x1E, 0x10}); err != ErrLength {
+		t.Fatalf("Expected error %v, but got %v", ErrLength, err)
+	}
+}
+
+func TestReadAttributesResponse(t *testing.T) {
+	records := []ReadRecord{
+		{ID: 0x0000, Status: StatusSuccess, Type: TypeInt16, Value: int16(2150)},
+		{ID: 0x0001, Status: StatusUnsupportedAttribute},
+		{ID: 0x0005, Status: StatusSuccess, Type: TypeCharString, Value: "sensor"},
+	}
+
+	p, err := EncodeReadAttributesResponse(records...)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	decoded, err := DecodeReadAttributesResponse(p)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(decoded, records) {
+		t.Fatalf("Expected %+v, but got %+v", records, decoded)
+	}
+
+	if _, err := DecodeReadAttributesResponse(p[:len(p)-1]); err != ErrLength {
+		t.Fatalf("Expected error %v, but got %v", ErrLength, err)
+	}
+}
+
+func TestConfigureReporting(t *testing.T) {
+	configs := []ReportingConfig{
+		{ID: 0x0000, Type: TypeInt16, MinInterval: 10, MaxInterval: 300, ReportableChange: int16(50)},
+		{ID: 0x0000, Type: TypeBool, MinInterval: 0, MaxInterval: 600},
+	}
+
+	p, err := EncodeConfigureReporting(configs...)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []byte{
+		0x00, 0x00, 0x00, 0x29, 0x0A, 0x00, 0x2C, 0x01, 0x32, 0x00,
+		0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x58, 0x02,
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Fatalf("Expected % x, but got % x", expected, p)
+	}
+
+	decoded, err := DecodeConfigureReporting(p)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(decoded, configs) {
+		t.Fatalf("Expected %+v, but got %+v", configs, decoded)
+	}
+}
+
+func TestStatusResponses(t *testing.T) {
+	if p := EncodeWriteAttributesResponse(WriteRecord{Status: StatusSuccess, ID: 1}); !reflect.DeepEqual(p, []byte{0x00}) {
+		t.Fatalf("Expected single success status, but got % x", p)
+	}
+
+	records := []WriteRecord{{Status: StatusReadOnly, ID: 0x0004}}
+	decoded, err := DecodeWriteAttributesResponse(EncodeWriteAttributesResponse(records...))
+	if err != nil || !reflect.DeepEqual(decoded, records) {
+		t.Fatalf("Expected %+v, but got %+v, %v", records, decoded, err)
+	}
+
+	reporting := []ReportingRecord{{Status: StatusUnreportableAttribute, ID: 0x0005}}
+	decodedReporting, err := DecodeConfigureReportingResponse(EncodeConfigureReportingResponse(reporting...))
+	if err != nil || !reflect.DeepEqual(decodedReporting, reporting) {
+		t.Fatalf("Expected %+v, but got %+v, %v", reporting, decodedReporting, err)
+	}
+
+	attributes := []AttributeInfo{{ID: 0x0000, Type: TypeInt16}, {ID: 0x0001, Type: TypeInt16}}
+	complete, decodedAttributes, err := DecodeDiscoverAttributesResponse(EncodeDiscoverAttributesResponse(true, attributes...))
+	if err != nil || !complete || !reflect.DeepEqual(decodedAttributes, attributes) {
+		t.Fatalf("Expected complete %+v, but got %v %+v, %v", attributes, complete, decodedAttributes, err)
+	}
+}
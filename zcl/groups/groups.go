@@ -0,0 +1,116 @@
+// Package groups is a client of the ZCL Groups cluster.
+package groups
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Groups cluster ID
+const Cluster uint16 = 0x0004
+
+// Attributes
+const (
+	AttrNameSupport uint16 = 0x0000
+)
+
+// Commands, responses share the ID of their request
+const (
+	CommandAddGroup           byte = 0x00
+	CommandViewGroup          byte = 0x01
+	CommandGetGroupMembership byte = 0x02
+	CommandRemoveGroup        byte = 0x03
+	CommandRemoveAllGroups    byte = 0x04
+	CommandAddGroupIfIdentify byte = 0x05
+)
+
+// AddGroup adds the endpoint to group id named name
+func AddGroup(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16, name string) error {
+	p := []byte{byte(id), byte(id >> 8), byte(len(name))}
+	p = append(p, name...)
+
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandAddGroup, CommandAddGroup, p)
+	if err != nil {
+		return err
+	}
+
+	return status(CommandAddGroup, r, 3)
+}
+
+// ViewGroup returns the name of group id on the endpoint
+func ViewGroup(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16) (string, error) {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandViewGroup, CommandViewGroup, []byte{byte(id), byte(id >> 8)})
+	if err != nil {
+		return "", err
+	}
+
+	if err := status(CommandViewGroup, r, 4); err != nil {
+		return "", err
+	}
+
+	n := int(r[3])
+	if len(r) < 4+n {
+		return "", zcl.ErrLength
+	}
+
+	return string(r[4 : 4+n]), nil
+}
+
+// GetGroupMembership returns which of ids the endpoint is a member of, all of
+// its groups for no ids, and its remaining group table capacity
+func GetGroupMembership(ctx context.Context, c *zcl.Client, dst zcl.Address, ids ...uint16) ([]uint16, byte, error) {
+	p := []byte{byte(len(ids))}
+	for _, id := range ids {
+		p = append(p, byte(id), byte(id>>8))
+	}
+
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandGetGroupMembership, CommandGetGroupMembership, p)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(r) < 2 || len(r) < 2+2*int(r[1]) {
+		return nil, 0, zcl.ErrLength
+	}
+
+	groups := make([]uint16, r[1])
+	for i := range groups {
+		groups[i] = uint16(r[2+2*i]) | uint16(r[3+2*i])<<8
+	}
+
+	return groups, r[0], nil
+}
+
+// RemoveGroup removes the endpoint from group id
+func RemoveGroup(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16) error {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandRemoveGroup, CommandRemoveGroup, []byte{byte(id), byte(id >> 8)})
+	if err != nil {
+		return err
+	}
+
+	return status(CommandRemoveGroup, r, 3)
+}
+
+// RemoveAllGroups removes the endpoint from all of its groups
+func RemoveAllGroups(ctx context.Context, c *zcl.Client, dst zcl.Address) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandRemoveAllGroups, nil)
+	return err
+}
+
+// status checks a response of at least size bytes leading with a status
+func status(command byte, r []byte, size int) error {
+	if len(r) < 1 {
+		return zcl.ErrLength
+	}
+
+	if s := zcl.Status(r[0]); s != zcl.StatusSuccess {
+		return &zcl.StatusError{Cluster: Cluster, Command: command, Status: s}
+	}
+
+	if len(r) < size {
+		return zcl.ErrLength
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/internal/radiotest"
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// respond answers each Groups command with the response payload returned by
+// response
+func respond(t *testing.T, response func(command byte, payload []byte) []byte) *zcl.Client {
+	return radiotest.Cluster(t, Cluster, func(f *zcl.Frame) *zcl.Frame {
+		return &zcl.Frame{Header: zcl.Header{Type: zcl.FrameCluster, Command: f.Command}, Payload: response(f.Command, f.Payload)}
+	})
+}
+
+func TestAddGroup(t *testing.T) {
+	c := respond(t, func(command byte, payload []byte) []byte {
+		expected := []byte{0x01, 0x00, 0x07, 'K', 'i', 't', 'c', 'h', 'e', 'n'}
+		if command != CommandAddGroup || !reflect.DeepEqual(payload, expected) {
+			t.Fatalf("Expected Add Group % x, but got 0x%02x % x", expected, command, payload)
+		}
+
+		return []byte{byte(zcl.StatusSuccess), 0x01, 0x00}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := AddGroup(ctx, c, radiotest.Destination, 0x0001, "Kitchen"); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+}
+
+func TestViewGroup(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name     string
+		response []byte
+		expected string
+		status   zcl.Status
+	}{
+		{"Found", []byte{0x00, 0x01, 0x00, 0x03, 'H', 'a', 'l'}, "Hal", zcl.StatusSuccess},
+		{"Not Found", []byte{byte(zcl.StatusNotFound), 0x01, 0x00}, "", zcl.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := respond(t, func(command byte, payload []byte) []byte {
+				return tt.response
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			name, err := ViewGroup(ctx, c, radiotest.Destination, 0x0001)
+			if tt.status != zcl.StatusSuccess {
+				var s *zcl.StatusError
+				if !errors.As(err, &s) || s.Status != tt.status {
+					t.Fatalf("Expected status %v, but got %v", tt.status, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if name != tt.expected {
+				t.Fatalf("Expected %q, but got %q", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestGetGroupMembership(t *testing.T) {
+	c := respond(t, func(command byte, payload []byte) []byte {
+		return []byte{0x0A, 0x02, 0x01, 0x00, 0x02, 0x00}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	groups, capacity, err := GetGroupMembership(ctx, c, radiotest.Destination)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if capacity != 0x0A || !reflect.DeepEqual(groups, []uint16{1, 2}) {
+		t.Fatalf("Expected groups [1 2] with capacity 10, but got %v with %d", groups, capacity)
+	}
+}
@@ -0,0 +1,50 @@
+// Package humidity is a client of the ZCL Relative Humidity Measurement cluster.
+package humidity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Relative Humidity Measurement cluster ID
+const Cluster uint16 = 0x0405
+
+// Attributes, values in hundredths of a percent
+const (
+	AttrMeasuredValue    uint16 = 0x0000
+	AttrMinMeasuredValue uint16 = 0x0001
+	AttrMaxMeasuredValue uint16 = 0x0002
+	AttrTolerance        uint16 = 0x0003
+)
+
+// invalid measured value
+const invalid uint16 = 0xFFFF
+
+// ErrInvalid the measurement is invalid
+var ErrInvalid = errors.New("invalid humidity measurement")
+
+// MeasuredValue reads the endpoint's measured relative humidity in percent
+func MeasuredValue(ctx context.Context, c *zcl.Client, dst zcl.Address) (float64, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, AttrMeasuredValue)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := v.(uint16)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return Percent(n)
+}
+
+// Percent converts a measured value attribute to percent relative humidity
+func Percent(v uint16) (float64, error) {
+	if v == invalid {
+		return 0, ErrInvalid
+	}
+
+	return float64(v) / 100, nil
+}
@@ -0,0 +1,190 @@
+// Package ias is a client of the ZCL IAS Zone cluster.
+package ias
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster IAS Zone cluster ID
+const Cluster uint16 = 0x0500
+
+// Attributes
+const (
+	AttrZoneState  uint16 = 0x0000
+	AttrZoneType   uint16 = 0x0001
+	AttrZoneStatus uint16 = 0x0002
+	AttrCIEAddress uint16 = 0x0010
+	AttrZoneID     uint16 = 0x0011
+)
+
+// Client to server commands
+const (
+	CommandEnrollResponse byte = 0x00
+)
+
+// Server to client commands
+const (
+	CommandStatusChangeNotification byte = 0x00
+	CommandEnrollRequest            byte = 0x01
+)
+
+// Zone states
+const (
+	NotEnrolled byte = 0x00
+	Enrolled    byte = 0x01
+)
+
+// Zone types
+const (
+	ZoneStandardCIE       uint16 = 0x0000
+	ZoneMotion            uint16 = 0x000D
+	ZoneContact           uint16 = 0x0015
+	ZoneFire              uint16 = 0x0028
+	ZoneWater             uint16 = 0x002A
+	ZoneCarbonMonoxide    uint16 = 0x002B
+	ZonePersonalEmergency uint16 = 0x002C
+	ZoneVibration         uint16 = 0x002D
+	ZoneRemoteControl     uint16 = 0x010F
+	ZoneKeyFob            uint16 = 0x0115
+	ZoneKeypad            uint16 = 0x021D
+	ZoneWarningDevice     uint16 = 0x0225
+)
+
+// Enroll response codes
+const (
+	EnrollSuccess        byte = 0x00
+	EnrollNotSupported   byte = 0x01
+	EnrollNoEnrollPermit byte = 0x02
+	EnrollTooManyZones   byte = 0x03
+)
+
+// ZoneStatus the zone status bitmap
+type ZoneStatus uint16
+
+// Zone status bits
+const (
+	Alarm1             = ZoneStatus(1 << 0)
+	Alarm2             = ZoneStatus(1 << 1)
+	Tamper             = ZoneStatus(1 << 2)
+	Battery            = ZoneStatus(1 << 3)
+	SupervisionReports = ZoneStatus(1 << 4)
+	RestoreReports     = ZoneStatus(1 << 5)
+	Trouble            = ZoneStatus(1 << 6)
+	ACMains            = ZoneStatus(1 << 7)
+	Test               = ZoneStatus(1 << 8)
+	BatteryDefect      = ZoneStatus(1 << 9)
+)
+
+// Has returns true if all bits of b are set
+func (s ZoneStatus) Has(b ZoneStatus) bool {
+	return s&b == b
+}
+
+// StatusChangeNotification a zone's status change
+type StatusChangeNotification struct {
+	Status ZoneStatus
+	// Extended reserved extended status
+	Extended byte
+	ZoneID   byte
+	// Delay quarter seconds since the status changed
+	Delay uint16
+}
+
+// ParseStatusChangeNotification decodes a Zone Status Change Notification
+// payload, the zone ID and delay are absent from older devices
+func ParseStatusChangeNotification(p []byte) (*StatusChangeNotification, error) {
+	if len(p) < 3 {
+		return nil, zcl.ErrLength
+	}
+
+	n := &StatusChangeNotification{
+		Status:   ZoneStatus(binary.LittleEndian.Uint16(p)),
+		Extended: p[2],
+	}
+	if len(p) >= 6 {
+		n.ZoneID = p[3]
+		n.Delay = binary.LittleEndian.Uint16(p[4:])
+	}
+
+	return n, nil
+}
+
+// EnrollRequest a zone's request to enroll with its CIE
+type EnrollRequest struct {
+	ZoneType     uint16
+	Manufacturer uint16
+}
+
+// ParseEnrollRequest decodes a Zone Enroll Request payload
+func ParseEnrollRequest(p []byte) (*EnrollRequest, error) {
+	if len(p) < 4 {
+		return nil, zcl.ErrLength
+	}
+
+	return &EnrollRequest{
+		ZoneType:     binary.LittleEndian.Uint16(p),
+		Manufacturer: binary.LittleEndian.Uint16(p[2:]),
+	}, nil
+}
+
+// EnrollResponse answers a zone's Enroll Request with code, assigning it
+// zoneID, the zone does not respond
+func EnrollResponse(ctx context.Context, c *zcl.Client, dst zcl.Address, code, zoneID byte) error {
+	return c.Send(ctx, dst, Cluster, &zcl.Frame{
+		Header: zcl.Header{
+			Type:                   zcl.FrameCluster,
+			Direction:              zcl.ClientToServer,
+			DisableDefaultResponse: true,
+			Seq:                    c.NextSeq(),
+			Command:                CommandEnrollResponse,
+		},
+		Payload: []byte{code, zoneID},
+	})
+}
+
+// SetCIEAddress writes the IEEE address of the CIE the zone reports to
+func SetCIEAddress(ctx context.Context, c *zcl.Client, dst zcl.Address, addr64 uint64) error {
+	return c.WriteAttribute(ctx, dst, Cluster, zcl.Attribute{ID: AttrCIEAddress, Type: zcl.TypeIEEEAddr, Value: addr64})
+}
+
+// Zone the zone's state, type and status
+type Zone struct {
+	State  byte
+	Type   uint16
+	Status ZoneStatus
+}
+
+// Read reads the zone's state, type and status
+func Read(ctx context.Context, c *zcl.Client, dst zcl.Address) (*Zone, error) {
+	records, err := c.ReadAttributes(ctx, dst, Cluster, AttrZoneState, AttrZoneType, AttrZoneStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	z := &Zone{}
+	for _, r := range records {
+		if r.Status != zcl.StatusSuccess {
+			return nil, &zcl.StatusError{Cluster: Cluster, Command: zcl.CommandReadAttributes, Status: r.Status}
+		}
+
+		var ok bool
+		switch r.ID {
+		case AttrZoneState:
+			z.State, ok = r.Value.(uint8)
+		case AttrZoneType:
+			z.Type, ok = r.Value.(uint16)
+		case AttrZoneStatus:
+			var n uint16
+			n, ok = r.Value.(uint16)
+			z.Status = ZoneStatus(n)
+		}
+		if !ok {
+			return nil, zcl.ErrResponse
+		}
+	}
+
+	return z, nil
+}
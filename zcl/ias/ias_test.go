@@ -0,0 +1,57 @@
+package ias
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+func TestParseStatusChangeNotification(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name     string
+		p        []byte
+		expected *StatusChangeNotification
+		err      error
+	}{
+		{"Full", []byte{0x05, 0x00, 0x00, 0x03, 0x08, 0x00}, &StatusChangeNotification{Status: Alarm1 | Tamper, ZoneID: 3, Delay: 8}, nil},
+		{"Legacy", []byte{0x01, 0x01, 0x00}, &StatusChangeNotification{Status: Alarm1 | Test}, nil},
+		{"Short", []byte{0x01}, nil, zcl.ErrLength},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := ParseStatusChangeNotification(tt.p)
+			if err != tt.err {
+				t.Fatalf("Expected error %v, but got %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(n, tt.expected) {
+				t.Fatalf("Expected %+v, but got %+v", tt.expected, n)
+			}
+		})
+	}
+}
+
+func TestParseEnrollRequest(t *testing.T) {
+	r, err := ParseEnrollRequest([]byte{0x15, 0x00, 0x5F, 0x11})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := &EnrollRequest{ZoneType: ZoneContact, Manufacturer: 0x115F}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, r)
+	}
+}
+
+func TestZoneStatus_Has(t *testing.T) {
+	s := Alarm1 | Battery
+	if !s.Has(Battery) || s.Has(Tamper) {
+		t.Fatalf("Expected battery and not tamper in %04x", s)
+	}
+}
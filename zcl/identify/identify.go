@@ -0,0 +1,47 @@
+// Package identify is a client of the ZCL Identify cluster.
+package identify
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Identify cluster ID
+const Cluster uint16 = 0x0003
+
+// Attributes
+const (
+	AttrIdentifyTime uint16 = 0x0000
+)
+
+// Commands
+const (
+	CommandIdentify      byte = 0x00
+	CommandIdentifyQuery byte = 0x01
+	// CommandIdentifyQueryResponse received from the server
+	CommandIdentifyQueryResponse byte = 0x00
+)
+
+// Identify makes the endpoint identify itself, by flashing a light for
+// example, for seconds, 0 stops identifying
+func Identify(ctx context.Context, c *zcl.Client, dst zcl.Address, seconds uint16) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandIdentify, []byte{byte(seconds), byte(seconds >> 8)})
+	return err
+}
+
+// Query returns the seconds the endpoint will continue identifying itself,
+// an endpoint not identifying returns a *zcl.StatusError or does not respond
+func Query(ctx context.Context, c *zcl.Client, dst zcl.Address) (uint16, error) {
+	p, err := c.CommandResponse(ctx, dst, Cluster, CommandIdentifyQuery, CommandIdentifyQueryResponse, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(p) < 2 {
+		return 0, zcl.ErrLength
+	}
+
+	return binary.LittleEndian.Uint16(p), nil
+}
@@ -0,0 +1,89 @@
+// Package level is a client of the ZCL Level Control cluster.
+package level
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Level Control cluster ID
+const Cluster uint16 = 0x0008
+
+// Attributes
+const (
+	AttrCurrentLevel  uint16 = 0x0000
+	AttrRemainingTime uint16 = 0x0001
+)
+
+// Commands, the with on/off variants also switch the On/Off cluster
+const (
+	CommandMoveToLevel          byte = 0x00
+	CommandMove                 byte = 0x01
+	CommandStep                 byte = 0x02
+	CommandStop                 byte = 0x03
+	CommandMoveToLevelWithOnOff byte = 0x04
+	CommandMoveWithOnOff        byte = 0x05
+	CommandStepWithOnOff        byte = 0x06
+	CommandStopWithOnOff        byte = 0x07
+)
+
+// Mode defines move and step directions
+type Mode byte
+
+// Move and step directions
+const (
+	Up   = Mode(0x00)
+	Down = Mode(0x01)
+)
+
+// withOnOff command variant offset
+const withOnOff byte = 0x04
+
+// command the command or its with on/off variant
+func command(cmd byte, onOff bool) byte {
+	if onOff {
+		return cmd + withOnOff
+	}
+
+	return cmd
+}
+
+// MoveToLevel moves the endpoint to level over transition tenths of a second
+func MoveToLevel(ctx context.Context, c *zcl.Client, dst zcl.Address, level byte, transition uint16, onOff bool) error {
+	_, err := c.Command(ctx, dst, Cluster, command(CommandMoveToLevel, onOff), []byte{level, byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// Move moves the endpoint's level continuously at rate units per second
+func Move(ctx context.Context, c *zcl.Client, dst zcl.Address, mode Mode, rate byte, onOff bool) error {
+	_, err := c.Command(ctx, dst, Cluster, command(CommandMove, onOff), []byte{byte(mode), rate})
+	return err
+}
+
+// Step steps the endpoint's level by size over transition tenths of a second
+func Step(ctx context.Context, c *zcl.Client, dst zcl.Address, mode Mode, size byte, transition uint16, onOff bool) error {
+	_, err := c.Command(ctx, dst, Cluster, command(CommandStep, onOff), []byte{byte(mode), size, byte(transition), byte(transition >> 8)})
+	return err
+}
+
+// Stop stops a move or step in progress
+func Stop(ctx context.Context, c *zcl.Client, dst zcl.Address, onOff bool) error {
+	_, err := c.Command(ctx, dst, Cluster, command(CommandStop, onOff), nil)
+	return err
+}
+
+// CurrentLevel reads the endpoint's current level
+func CurrentLevel(ctx context.Context, c *zcl.Client, dst zcl.Address) (byte, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, AttrCurrentLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	level, ok := v.(uint8)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return level, nil
+}
@@ -0,0 +1,85 @@
+// Package metering is a client of the ZCL Metering cluster.
+package metering
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Metering cluster ID
+const Cluster uint16 = 0x0702
+
+// Attributes
+const (
+	AttrCurrentSummationDelivered uint16 = 0x0000
+	AttrCurrentSummationReceived  uint16 = 0x0001
+	AttrStatus                    uint16 = 0x0200
+	AttrUnitOfMeasure             uint16 = 0x0300
+	AttrMultiplier                uint16 = 0x0301
+	AttrDivisor                   uint16 = 0x0302
+	AttrSummationFormatting       uint16 = 0x0303
+	AttrMeteringDeviceType        uint16 = 0x0306
+	AttrInstantaneousDemand       uint16 = 0x0400
+)
+
+// Units of measure
+const (
+	UnitKilowattHours   byte = 0x00
+	UnitCubicMeters     byte = 0x01
+	UnitCubicFeet       byte = 0x02
+	UnitCCF             byte = 0x03
+	UnitUSGallons       byte = 0x04
+	UnitImperialGallons byte = 0x05
+	UnitBTU             byte = 0x06
+	UnitLiters          byte = 0x07
+	UnitKPAGauge        byte = 0x08
+	UnitKPAAbsolute     byte = 0x09
+)
+
+// Reading metered values scaled by the meter's multiplier and divisor
+type Reading struct {
+	// Delivered current summation delivered
+	Delivered float64
+	// Demand instantaneous demand
+	Demand float64
+	Unit   byte
+}
+
+// Read reads the endpoint's summation delivered and instantaneous demand,
+// scaled by its multiplier and divisor
+func Read(ctx context.Context, c *zcl.Client, dst zcl.Address) (*Reading, error) {
+	records, err := c.ReadAttributes(ctx, dst, Cluster,
+		AttrCurrentSummationDelivered, AttrInstantaneousDemand, AttrUnitOfMeasure, AttrMultiplier, AttrDivisor)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[uint16]interface{})
+	for _, r := range records {
+		if r.Status != zcl.StatusSuccess {
+			return nil, &zcl.StatusError{Cluster: Cluster, Command: zcl.CommandReadAttributes, Status: r.Status}
+		}
+		values[r.ID] = r.Value
+	}
+
+	delivered, ok1 := values[AttrCurrentSummationDelivered].(uint64)
+	demand, ok2 := values[AttrInstantaneousDemand].(int32)
+	unit, ok3 := values[AttrUnitOfMeasure].(uint8)
+	multiplier, ok4 := values[AttrMultiplier].(uint32)
+	divisor, ok5 := values[AttrDivisor].(uint32)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return nil, zcl.ErrResponse
+	}
+
+	scale := 1.0
+	if divisor != 0 {
+		scale = float64(multiplier) / float64(divisor)
+	}
+
+	return &Reading{
+		Delivered: float64(delivered) * scale,
+		Demand:    float64(demand) * scale,
+		Unit:      unit,
+	}, nil
+}
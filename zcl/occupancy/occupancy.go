@@ -0,0 +1,58 @@
+// Package occupancy is a client of the ZCL Occupancy Sensing cluster.
+package occupancy
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Occupancy Sensing cluster ID
+const Cluster uint16 = 0x0406
+
+// Attributes
+const (
+	AttrOccupancy           uint16 = 0x0000
+	AttrOccupancySensorType uint16 = 0x0001
+)
+
+// Occupancy sensor types
+const (
+	SensorPIR              = byte(0x00)
+	SensorUltrasonic       = byte(0x01)
+	SensorPIRAndUltrasonic = byte(0x02)
+	SensorPhysicalContact  = byte(0x03)
+)
+
+// occupied Occupancy attribute bit
+const occupied byte = 0x01
+
+// Occupied reads whether the endpoint senses occupancy
+func Occupied(ctx context.Context, c *zcl.Client, dst zcl.Address) (bool, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, AttrOccupancy)
+	if err != nil {
+		return false, err
+	}
+
+	bitmap, ok := v.(uint8)
+	if !ok {
+		return false, zcl.ErrResponse
+	}
+
+	return bitmap&occupied != 0, nil
+}
+
+// SensorType reads the endpoint's occupancy sensor type
+func SensorType(ctx context.Context, c *zcl.Client, dst zcl.Address) (byte, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, AttrOccupancySensorType)
+	if err != nil {
+		return 0, err
+	}
+
+	t, ok := v.(uint8)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return t, nil
+}
@@ -0,0 +1,56 @@
+// Package onoff is a client of the ZCL On/Off cluster.
+package onoff
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster On/Off cluster ID
+const Cluster uint16 = 0x0006
+
+// Attributes
+const (
+	AttrOnOff uint16 = 0x0000
+)
+
+// Commands
+const (
+	CommandOff    byte = 0x00
+	CommandOn     byte = 0x01
+	CommandToggle byte = 0x02
+)
+
+// Off switches the endpoint off
+func Off(ctx context.Context, c *zcl.Client, dst zcl.Address) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandOff, nil)
+	return err
+}
+
+// On switches the endpoint on
+func On(ctx context.Context, c *zcl.Client, dst zcl.Address) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandOn, nil)
+	return err
+}
+
+// Toggle toggles the endpoint
+func Toggle(ctx context.Context, c *zcl.Client, dst zcl.Address) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandToggle, nil)
+	return err
+}
+
+// IsOn reads the endpoint's OnOff attribute
+func IsOn(ctx context.Context, c *zcl.Client, dst zcl.Address) (bool, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, AttrOnOff)
+	if err != nil {
+		return false, err
+	}
+
+	on, ok := v.(bool)
+	if !ok {
+		return false, zcl.ErrResponse
+	}
+
+	return on, nil
+}
@@ -0,0 +1,67 @@
+package onoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee/internal/radiotest"
+	"github.com/pauleyj/gobee/zcl"
+)
+
+func TestCommands(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name    string
+		send    func(ctx context.Context, c *zcl.Client, dst zcl.Address) error
+		command byte
+	}{
+		{"Off", Off, CommandOff},
+		{"On", On, CommandOn},
+		{"Toggle", Toggle, CommandToggle},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := radiotest.Cluster(t, Cluster, func(f *zcl.Frame) *zcl.Frame {
+				if f.Type != zcl.FrameCluster || f.Command != tt.command {
+					t.Fatalf("Expected cluster command 0x%02x, but got %+v", tt.command, f.Header)
+				}
+
+				return &zcl.Frame{
+					Header:  zcl.Header{Command: zcl.CommandDefaultResponse},
+					Payload: zcl.EncodeDefaultResponse(f.Command, zcl.StatusSuccess),
+				}
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if err := tt.send(ctx, c, radiotest.Destination); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestIsOn(t *testing.T) {
+	c := radiotest.Cluster(t, Cluster, func(f *zcl.Frame) *zcl.Frame {
+		payload, _ := zcl.EncodeReadAttributesResponse(zcl.ReadRecord{ID: AttrOnOff, Type: zcl.TypeBool, Value: true})
+		return &zcl.Frame{Header: zcl.Header{Command: zcl.CommandReadAttributesResponse}, Payload: payload}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	on, err := IsOn(ctx, c, radiotest.Destination)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !on {
+		t.Fatal("Expected on")
+	}
+}
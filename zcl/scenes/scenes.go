@@ -0,0 +1,182 @@
+// Package scenes is a client of the ZCL Scenes cluster.
+package scenes
+
+import (
+	"context"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Scenes cluster ID
+const Cluster uint16 = 0x0005
+
+// Attributes
+const (
+	AttrSceneCount   uint16 = 0x0000
+	AttrCurrentScene uint16 = 0x0001
+	AttrCurrentGroup uint16 = 0x0002
+	AttrSceneValid   uint16 = 0x0003
+	AttrNameSupport  uint16 = 0x0004
+)
+
+// Commands, responses share the ID of their request
+const (
+	CommandAddScene           byte = 0x00
+	CommandViewScene          byte = 0x01
+	CommandRemoveScene        byte = 0x02
+	CommandRemoveAllScenes    byte = 0x03
+	CommandStoreScene         byte = 0x04
+	CommandRecallScene        byte = 0x05
+	CommandGetSceneMembership byte = 0x06
+)
+
+// ExtensionField the attribute values of one cluster stored in a scene
+type ExtensionField struct {
+	Cluster uint16
+	Data    []byte
+}
+
+// Scene a scene of a group
+type Scene struct {
+	Group uint16
+	ID    byte
+	// Transition tenths of a second to move to the scene
+	Transition uint16
+	Name       string
+	Extensions []ExtensionField
+}
+
+// Bytes encodes the scene as an Add Scene payload
+func (s *Scene) Bytes() []byte {
+	p := []byte{byte(s.Group), byte(s.Group >> 8), s.ID, byte(s.Transition), byte(s.Transition >> 8), byte(len(s.Name))}
+	p = append(p, s.Name...)
+	for _, e := range s.Extensions {
+		p = append(p, byte(e.Cluster), byte(e.Cluster>>8), byte(len(e.Data)))
+		p = append(p, e.Data...)
+	}
+
+	return p
+}
+
+// parseScene decodes a View Scene response payload following the status
+func parseScene(p []byte) (*Scene, error) {
+	if len(p) < 6 || len(p) < 6+int(p[5]) {
+		return nil, zcl.ErrLength
+	}
+
+	s := &Scene{
+		Group:      uint16(p[0]) | uint16(p[1])<<8,
+		ID:         p[2],
+		Transition: uint16(p[3]) | uint16(p[4])<<8,
+		Name:       string(p[6 : 6+p[5]]),
+	}
+
+	for p = p[6+p[5]:]; len(p) > 0; {
+		if len(p) < 3 || len(p) < 3+int(p[2]) {
+			return nil, zcl.ErrLength
+		}
+		s.Extensions = append(s.Extensions, ExtensionField{
+			Cluster: uint16(p[0]) | uint16(p[1])<<8,
+			Data:    append([]byte(nil), p[3:3+p[2]]...),
+		})
+		p = p[3+p[2]:]
+	}
+
+	return s, nil
+}
+
+// AddScene adds scene s to the endpoint
+func AddScene(ctx context.Context, c *zcl.Client, dst zcl.Address, s *Scene) error {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandAddScene, CommandAddScene, s.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return status(CommandAddScene, r, 4)
+}
+
+// ViewScene returns scene id of group on the endpoint
+func ViewScene(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16, id byte) (*Scene, error) {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandViewScene, CommandViewScene, []byte{byte(group), byte(group >> 8), id})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := status(CommandViewScene, r, 4); err != nil {
+		return nil, err
+	}
+
+	return parseScene(r[1:])
+}
+
+// RemoveScene removes scene id of group from the endpoint
+func RemoveScene(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16, id byte) error {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandRemoveScene, CommandRemoveScene, []byte{byte(group), byte(group >> 8), id})
+	if err != nil {
+		return err
+	}
+
+	return status(CommandRemoveScene, r, 4)
+}
+
+// RemoveAllScenes removes all scenes of group from the endpoint
+func RemoveAllScenes(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16) error {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandRemoveAllScenes, CommandRemoveAllScenes, []byte{byte(group), byte(group >> 8)})
+	if err != nil {
+		return err
+	}
+
+	return status(CommandRemoveAllScenes, r, 3)
+}
+
+// StoreScene stores the endpoint's current state as scene id of group
+func StoreScene(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16, id byte) error {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandStoreScene, CommandStoreScene, []byte{byte(group), byte(group >> 8), id})
+	if err != nil {
+		return err
+	}
+
+	return status(CommandStoreScene, r, 4)
+}
+
+// RecallScene moves the endpoint to scene id of group
+func RecallScene(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16, id byte) error {
+	_, err := c.Command(ctx, dst, Cluster, CommandRecallScene, []byte{byte(group), byte(group >> 8), id})
+	return err
+}
+
+// GetSceneMembership returns the scene IDs of group on the endpoint and its
+// remaining scene table capacity
+func GetSceneMembership(ctx context.Context, c *zcl.Client, dst zcl.Address, group uint16) ([]byte, byte, error) {
+	r, err := c.CommandResponse(ctx, dst, Cluster, CommandGetSceneMembership, CommandGetSceneMembership, []byte{byte(group), byte(group >> 8)})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := status(CommandGetSceneMembership, r, 4); err != nil {
+		return nil, 0, err
+	}
+
+	if len(r) < 5 || len(r) < 5+int(r[4]) {
+		return nil, 0, zcl.ErrLength
+	}
+
+	return append([]byte(nil), r[5:5+r[4]]...), r[1], nil
+}
+
+// status checks a response of at least size bytes leading with a status
+func status(command byte, r []byte, size int) error {
+	if len(r) < 1 {
+		return zcl.ErrLength
+	}
+
+	if s := zcl.Status(r[0]); s != zcl.StatusSuccess {
+		return &zcl.StatusError{Cluster: Cluster, Command: command, Status: s}
+	}
+
+	if len(r) < size {
+		return zcl.ErrLength
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package scenes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScene_Bytes(t *testing.T) {
+	s := &Scene{
+		Group:      0x0001,
+		ID:         0x02,
+		Transition: 10,
+		Name:       "Evening",
+		Extensions: []ExtensionField{{Cluster: 0x0006, Data: []byte{0x00, 0x00, 0x01, 0x01}}},
+	}
+
+	p := s.Bytes()
+	expected := []byte{0x01, 0x00, 0x02, 0x0A, 0x00, 0x07, 'E', 'v', 'e', 'n', 'i', 'n', 'g', 0x06, 0x00, 0x04, 0x00, 0x00, 0x01, 0x01}
+	if !reflect.DeepEqual(p, expected) {
+		t.Fatalf("Expected % x, but got % x", expected, p)
+	}
+
+	parsed, err := parseScene(p)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(parsed, s) {
+		t.Fatalf("Expected %+v, but got %+v", s, parsed)
+	}
+}
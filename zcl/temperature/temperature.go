@@ -0,0 +1,64 @@
+// Package temperature is a client of the ZCL Temperature Measurement cluster.
+package temperature
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pauleyj/gobee/zcl"
+)
+
+// Cluster Temperature Measurement cluster ID
+const Cluster uint16 = 0x0402
+
+// Attributes, values in hundredths of a degree Celsius
+const (
+	AttrMeasuredValue    uint16 = 0x0000
+	AttrMinMeasuredValue uint16 = 0x0001
+	AttrMaxMeasuredValue uint16 = 0x0002
+	AttrTolerance        uint16 = 0x0003
+)
+
+// invalid measured value
+const invalid int16 = -0x8000
+
+// ErrInvalid the measurement is invalid
+var ErrInvalid = errors.New("invalid temperature measurement")
+
+// MeasuredValue reads the endpoint's measured temperature in degrees Celsius
+func MeasuredValue(ctx context.Context, c *zcl.Client, dst zcl.Address) (float64, error) {
+	return read(ctx, c, dst, AttrMeasuredValue)
+}
+
+// MinMeasuredValue reads the endpoint's minimum measurable temperature in degrees Celsius
+func MinMeasuredValue(ctx context.Context, c *zcl.Client, dst zcl.Address) (float64, error) {
+	return read(ctx, c, dst, AttrMinMeasuredValue)
+}
+
+// MaxMeasuredValue reads the endpoint's maximum measurable temperature in degrees Celsius
+func MaxMeasuredValue(ctx context.Context, c *zcl.Client, dst zcl.Address) (float64, error) {
+	return read(ctx, c, dst, AttrMaxMeasuredValue)
+}
+
+// Celsius converts a measured value attribute to degrees Celsius
+func Celsius(v int16) (float64, error) {
+	if v == invalid {
+		return 0, ErrInvalid
+	}
+
+	return float64(v) / 100, nil
+}
+
+func read(ctx context.Context, c *zcl.Client, dst zcl.Address, id uint16) (float64, error) {
+	v, err := c.ReadAttribute(ctx, dst, Cluster, id)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := v.(int16)
+	if !ok {
+		return 0, zcl.ErrResponse
+	}
+
+	return Celsius(n)
+}
@@ -0,0 +1,35 @@
+package temperature
+
+import (
+	"testing"
+)
+
+func TestCelsius(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name     string
+		value    int16
+		expected float64
+		err      error
+	}{
+		{"Positive", 2150, 21.5, nil},
+		{"Negative", -1025, -10.25, nil},
+		{"Invalid", -0x8000, 0, ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := Celsius(tt.value)
+			if err != tt.err {
+				t.Fatalf("Expected error %v, but got %v", tt.err, err)
+			}
+			if c != tt.expected {
+				t.Fatalf("Expected %v, but got %v", tt.expected, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,551 @@
+package zcl
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var (
+	// ErrDataType unknown or unsupported ZCL data type
+	ErrDataType = errors.New("invalid ZCL data type")
+	// ErrValue value is not of, or out of range for, the data type
+	ErrValue = errors.New("invalid value for ZCL data type")
+	// ErrLength ZCL payload too short
+	ErrLength = errors.New("invalid ZCL payload length")
+)
+
+// DataType defines ZCL data types
+type DataType byte
+
+// ZCL data types
+const (
+	TypeNoData          = DataType(0x00)
+	TypeData8           = DataType(0x08)
+	TypeData16          = DataType(0x09)
+	TypeData24          = DataType(0x0A)
+	TypeData32          = DataType(0x0B)
+	TypeData40          = DataType(0x0C)
+	TypeData48          = DataType(0x0D)
+	TypeData56          = DataType(0x0E)
+	TypeData64          = DataType(0x0F)
+	TypeBool            = DataType(0x10)
+	TypeBitmap8         = DataType(0x18)
+	TypeBitmap16        = DataType(0x19)
+	TypeBitmap24        = DataType(0x1A)
+	TypeBitmap32        = DataType(0x1B)
+	TypeBitmap40        = DataType(0x1C)
+	TypeBitmap48        = DataType(0x1D)
+	TypeBitmap56        = DataType(0x1E)
+	TypeBitmap64        = DataType(0x1F)
+	TypeUint8           = DataType(0x20)
+	TypeUint16          = DataType(0x21)
+	TypeUint24          = DataType(0x22)
+	TypeUint32          = DataType(0x23)
+	TypeUint40          = DataType(0x24)
+	TypeUint48          = DataType(0x25)
+	TypeUint56          = DataType(0x26)
+	TypeUint64          = DataType(0x27)
+	TypeInt8            = DataType(0x28)
+	TypeInt16           = DataType(0x29)
+	TypeInt24           = DataType(0x2A)
+	TypeInt32           = DataType(0x2B)
+	TypeInt40           = DataType(0x2C)
+	TypeInt48           = DataType(0x2D)
+	TypeInt56           = DataType(0x2E)
+	TypeInt64           = DataType(0x2F)
+	TypeEnum8           = DataType(0x30)
+	TypeEnum16          = DataType(0x31)
+	TypeSemiFloat       = DataType(0x38)
+	TypeFloat           = DataType(0x39)
+	TypeDouble          = DataType(0x3A)
+	TypeOctetString     = DataType(0x41)
+	TypeCharString      = DataType(0x42)
+	TypeLongOctetString = DataType(0x43)
+	TypeLongCharString  = DataType(0x44)
+	TypeArray           = DataType(0x48)
+	TypeStruct          = DataType(0x4C)
+	TypeSet             = DataType(0x50)
+	TypeBag             = DataType(0x51)
+	TypeTimeOfDay       = DataType(0xE0)
+	TypeDate            = DataType(0xE1)
+	TypeUTCTime         = DataType(0xE2)
+	TypeClusterID       = DataType(0xE8)
+	TypeAttributeID     = DataType(0xE9)
+	TypeBACnetOID       = DataType(0xEA)
+	TypeIEEEAddr        = DataType(0xF0)
+	TypeSecurityKey     = DataType(0xF1)
+	TypeUnknown         = DataType(0xFF)
+)
+
+// Array the value of array, set and bag data types, elements are all of Type
+type Array struct {
+	Type     DataType
+	Elements []interface{}
+}
+
+// Element a structure element
+type Element struct {
+	Type  DataType
+	Value interface{}
+}
+
+// Structure the value of the structure data type
+type Structure []Element
+
+// class groups data types encoded alike
+type class byte
+
+const (
+	classInvalid class = iota
+	classNone
+	classUnsigned
+	classSigned
+	classBool
+	classSemiFloat
+	classFloat
+	classDouble
+	classOctets
+	classChars
+	classArray
+	classStruct
+	classRaw
+)
+
+// classify the data type and its fixed size in bytes, 0 for variable sizes
+func classify(t DataType) (class, int) {
+	switch {
+	case t == TypeNoData:
+		return classNone, 0
+	case t >= TypeData8 && t <= TypeData64,
+		t >= TypeBitmap8 && t <= TypeBitmap64,
+		t >= TypeUint8 && t <= TypeUint64:
+		return classUnsigned, int(t&0x07) + 1
+	case t >= TypeInt8 && t <= TypeInt64:
+		return classSigned, int(t&0x07) + 1
+	}
+
+	switch t {
+	case TypeBool:
+		return classBool, 1
+	case TypeEnum8:
+		return classUnsigned, 1
+	case TypeEnum16, TypeClusterID, TypeAttributeID:
+		return classUnsigned, 2
+	case TypeUTCTime, TypeBACnetOID:
+		return classUnsigned, 4
+	case TypeIEEEAddr:
+		return classUnsigned, 8
+	case TypeSemiFloat:
+		return classSemiFloat, 2
+	case TypeFloat:
+		return classFloat, 4
+	case TypeDouble:
+		return classDouble, 8
+	case TypeOctetString, TypeLongOctetString:
+		return classOctets, 0
+	case TypeCharString, TypeLongCharString:
+		return classChars, 0
+	case TypeArray, TypeSet, TypeBag:
+		return classArray, 0
+	case TypeStruct:
+		return classStruct, 0
+	case TypeTimeOfDay, TypeDate:
+		return classRaw, 4
+	case TypeSecurityKey:
+		return classRaw, 16
+	}
+
+	return classInvalid, 0
+}
+
+// IsAnalog is the data type analog, analog attributes have a reportable change
+func (t DataType) IsAnalog() bool {
+	c, _ := classify(t)
+
+	switch {
+	case c == classSigned, c == classSemiFloat, c == classFloat, c == classDouble:
+		return true
+	case t >= TypeUint8 && t <= TypeUint64:
+		return true
+	case t == TypeTimeOfDay, t == TypeDate, t == TypeUTCTime:
+		return true
+	}
+
+	return false
+}
+
+// EncodeValue encodes v as data type t. Unsigned types (data, bitmap, uint,
+// enum, IDs, UTC time, IEEE address) and signed types accept any Go integer
+// in range, float types float32 or float64, octet strings []byte, character
+// strings string, time of day and date [4]byte, security keys [16]byte, array,
+// set and bag Array, and structures Structure.
+func EncodeValue(t DataType, v interface{}) ([]byte, error) {
+	c, size := classify(t)
+
+	switch c {
+	case classNone:
+		return nil, nil
+	case classUnsigned:
+		n, ok := toUint64(v)
+		if !ok || size < 8 && n>>(8*uint(size)) != 0 {
+			return nil, ErrValue
+		}
+		return putUint(n, size), nil
+	case classSigned:
+		n, ok := toInt64(v)
+		if !ok || size < 8 && (n < -1<<(8*uint(size)-1) || n >= 1<<(8*uint(size)-1)) {
+			return nil, ErrValue
+		}
+		return putUint(uint64(n), size), nil
+	case classBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, ErrValue
+		}
+		if b {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case classSemiFloat, classFloat, classDouble:
+		f, ok := toFloat64(v)
+		if !ok {
+			return nil, ErrValue
+		}
+		switch c {
+		case classSemiFloat:
+			return putUint(uint64(float16(f)), 2), nil
+		case classFloat:
+			return putUint(uint64(math.Float32bits(float32(f))), 4), nil
+		}
+		return putUint(math.Float64bits(f), 8), nil
+	case classOctets, classChars:
+		var p []byte
+		switch s := v.(type) {
+		case []byte:
+			if c != classOctets {
+				return nil, ErrValue
+			}
+			p = s
+		case string:
+			if c != classChars {
+				return nil, ErrValue
+			}
+			p = []byte(s)
+		default:
+			return nil, ErrValue
+		}
+		if t == TypeOctetString || t == TypeCharString {
+			if len(p) > 0xFE {
+				return nil, ErrValue
+			}
+			return append([]byte{byte(len(p))}, p...), nil
+		}
+		if len(p) > 0xFFFE {
+			return nil, ErrValue
+		}
+		return append(putUint(uint64(len(p)), 2), p...), nil
+	case classArray:
+		a, ok := v.(Array)
+		if !ok || len(a.Elements) > 0xFFFE {
+			return nil, ErrValue
+		}
+		p := append([]byte{byte(a.Type)}, putUint(uint64(len(a.Elements)), 2)...)
+		for _, e := range a.Elements {
+			b, err := EncodeValue(a.Type, e)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, b...)
+		}
+		return p, nil
+	case classStruct:
+		s, ok := v.(Structure)
+		if !ok || len(s) > 0xFFFE {
+			return nil, ErrValue
+		}
+		p := putUint(uint64(len(s)), 2)
+		for _, e := range s {
+			b, err := EncodeValue(e.Type, e.Value)
+			if err != nil {
+				return nil, err
+			}
+			p = append(append(p, byte(e.Type)), b...)
+		}
+		return p, nil
+	case classRaw:
+		switch r := v.(type) {
+		case [4]byte:
+			if size == 4 {
+				return r[:], nil
+			}
+		case [16]byte:
+			if size == 16 {
+				return r[:], nil
+			}
+		}
+		return nil, ErrValue
+	}
+
+	return nil, ErrDataType
+}
+
+// DecodeValue decodes a value of data type t from the start of p, returning
+// the value and the number of bytes consumed. Values decode to the Go type of
+// their size: uint8, uint16, uint32 (24 and 32-bit) or uint64 (40 to 64-bit)
+// for unsigned types and the corresponding int types for signed types,
+// float32 for semi-precision and single precision, float64 for double
+// precision, and the types EncodeValue accepts for the others.
+func DecodeValue(t DataType, p []byte) (interface{}, int, error) {
+	c, size := classify(t)
+
+	if size > 0 && len(p) < size {
+		return nil, 0, ErrLength
+	}
+
+	switch c {
+	case classNone:
+		return nil, 0, nil
+	case classUnsigned:
+		return sized(getUint(p, size), size, false), size, nil
+	case classSigned:
+		n := getUint(p, size)
+		if size < 8 && n&(1<<(8*uint(size)-1)) != 0 {
+			n |= math.MaxUint64 << (8 * uint(size))
+		}
+		return sized(n, size, true), size, nil
+	case classBool:
+		return p[0] != 0, 1, nil
+	case classSemiFloat:
+		return float32(halfToFloat64(uint16(getUint(p, 2)))), 2, nil
+	case classFloat:
+		return math.Float32frombits(uint32(getUint(p, 4))), 4, nil
+	case classDouble:
+		return math.Float64frombits(getUint(p, 8)), 8, nil
+	case classOctets, classChars:
+		prefix := 1
+		if t == TypeLongOctetString || t == TypeLongCharString {
+			prefix = 2
+		}
+		if len(p) < prefix {
+			return nil, 0, ErrLength
+		}
+		n := int(getUint(p, prefix))
+		// all ones length, the invalid string, has no characters
+		if prefix == 1 && n == 0xFF || prefix == 2 && n == 0xFFFF {
+			n = 0
+		}
+		if len(p) < prefix+n {
+			return nil, 0, ErrLength
+		}
+		if c == classChars {
+			return string(p[prefix : prefix+n]), prefix + n, nil
+		}
+		return append([]byte(nil), p[prefix:prefix+n]...), prefix + n, nil
+	case classArray:
+		if len(p) < 3 {
+			return nil, 0, ErrLength
+		}
+		a := Array{Type: DataType(p[0])}
+		n := int(getUint(p[1:], 2))
+		if n == 0xFFFF {
+			n = 0
+		}
+		offset := 3
+		for i := 0; i < n; i++ {
+			v, used, err := DecodeValue(a.Type, p[offset:])
+			if err != nil {
+				return nil, 0, err
+			}
+			a.Elements = append(a.Elements, v)
+			offset += used
+		}
+		return a, offset, nil
+	case classStruct:
+		if len(p) < 2 {
+			return nil, 0, ErrLength
+		}
+		n := int(getUint(p, 2))
+		if n == 0xFFFF {
+			n = 0
+		}
+		s := Structure{}
+		offset := 2
+		for i := 0; i < n; i++ {
+			if len(p) < offset+1 {
+				return nil, 0, ErrLength
+			}
+			e := Element{Type: DataType(p[offset])}
+			v, used, err := DecodeValue(e.Type, p[offset+1:])
+			if err != nil {
+				return nil, 0, err
+			}
+			e.Value = v
+			s = append(s, e)
+			offset += 1 + used
+		}
+		return s, offset, nil
+	case classRaw:
+		if size == 4 {
+			var r [4]byte
+			copy(r[:], p)
+			return r, 4, nil
+		}
+		var r [16]byte
+		copy(r[:], p)
+		return r, 16, nil
+	}
+
+	return nil, 0, ErrDataType
+}
+
+// putUint little-endian encodes the low size bytes of n
+func putUint(n uint64, size int) []byte {
+	p := make([]byte, 8)
+	binary.LittleEndian.PutUint64(p, n)
+
+	return p[:size]
+}
+
+// getUint little-endian decodes size bytes
+func getUint(p []byte, size int) uint64 {
+	var n uint64
+	for i := size - 1; i >= 0; i-- {
+		n = n<<8 | uint64(p[i])
+	}
+
+	return n
+}
+
+// sized converts n to the Go integer type of size bytes
+func sized(n uint64, size int, signed bool) interface{} {
+	switch {
+	case size == 1 && signed:
+		return int8(n)
+	case size == 1:
+		return uint8(n)
+	case size == 2 && signed:
+		return int16(n)
+	case size == 2:
+		return uint16(n)
+	case size <= 4 && signed:
+		return int32(n)
+	case size <= 4:
+		return uint32(n)
+	case signed:
+		return int64(n)
+	}
+
+	return n
+}
+
+// toUint64 converts Go integers to uint64, negative values fail
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint8:
+		return uint64(n), true
+	case uint16:
+		return uint64(n), true
+	case uint32:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	case uint:
+		return uint64(n), true
+	}
+
+	n, ok := toInt64(v)
+
+	return uint64(n), ok && n >= 0
+}
+
+// toInt64 converts Go integers to int64, unsigned values above math.MaxInt64 fail
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case uint:
+		return int64(n), uint64(n) <= math.MaxInt64
+	}
+
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch f := v.(type) {
+	case float32:
+		return float64(f), true
+	case float64:
+		return f, true
+	}
+
+	return 0, false
+}
+
+// float16 converts f to IEEE 754 half precision, rounding to nearest even
+func float16(f float64) uint16 {
+	var sign uint16
+	if math.Signbit(f) {
+		sign = 0x8000
+		f = -f
+	}
+
+	switch {
+	case math.IsNaN(f):
+		return 0x7E00
+	case f >= 65520:
+		return sign | 0x7C00
+	case f < math.Ldexp(1, -14):
+		// subnormal, rounding up to the smallest normal encodes as normal
+		return sign | uint16(math.RoundToEven(math.Ldexp(f, 24)))
+	}
+
+	m, e := math.Frexp(f)
+	exp := e - 1
+	frac := uint16(math.RoundToEven((2*m - 1) * 1024))
+	if frac == 1024 {
+		frac = 0
+		exp++
+	}
+
+	return sign | uint16(exp+15)<<10 | frac
+}
+
+// halfToFloat64 converts IEEE 754 half precision h
+func halfToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+
+	exp := int(h >> 10 & 0x1F)
+	frac := float64(h & 0x3FF)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1F:
+		if frac != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+
+	return sign * math.Ldexp(1+frac/1024, exp-15)
+}
@@ -0,0 +1,114 @@
+package zcl
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+type valueTest struct {
+	name     string
+	t        DataType
+	value    interface{}
+	expected []byte
+}
+
+var valueTests = []valueTest{
+	{"No Data", TypeNoData, nil, nil},
+	{"Bool", TypeBool, true, []byte{0x01}},
+	{"Bitmap8", TypeBitmap8, uint8(0xA5), []byte{0xA5}},
+	{"Data16", TypeData16, uint16(0x1234), []byte{0x34, 0x12}},
+	{"Uint24", TypeUint24, uint32(0x123456), []byte{0x56, 0x34, 0x12}},
+	{"Uint48", TypeUint48, uint64(0x123456789ABC), []byte{0xBC, 0x9A, 0x78, 0x56, 0x34, 0x12}},
+	{"Int8", TypeInt8, int8(-2), []byte{0xFE}},
+	{"Int16", TypeInt16, int16(2150), []byte{0x66, 0x08}},
+	{"Int24", TypeInt24, int32(-2), []byte{0xFE, 0xFF, 0xFF}},
+	{"Int64", TypeInt64, int64(-1), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	{"Enum8", TypeEnum8, uint8(0x02), []byte{0x02}},
+	{"Enum16", TypeEnum16, uint16(0x0102), []byte{0x02, 0x01}},
+	{"Semi Float", TypeSemiFloat, float32(1.5), []byte{0x00, 0x3E}},
+	{"Float", TypeFloat, float32(1.5), []byte{0x00, 0x00, 0xC0, 0x3F}},
+	{"Double", TypeDouble, float64(-2), []byte{0, 0, 0, 0, 0, 0, 0x00, 0xC0}},
+	{"Octet String", TypeOctetString, []byte{0x01, 0x02}, []byte{0x02, 0x01, 0x02}},
+	{"Char String", TypeCharString, "Digi", []byte{0x04, 'D', 'i', 'g', 'i'}},
+	{"Long Char String", TypeLongCharString, "ab", []byte{0x02, 0x00, 'a', 'b'}},
+	{"Array", TypeArray, Array{Type: TypeUint16, Elements: []interface{}{uint16(1), uint16(2)}},
+		[]byte{0x21, 0x02, 0x00, 0x01, 0x00, 0x02, 0x00}},
+	{"Struct", TypeStruct, Structure{{Type: TypeBool, Value: false}, {Type: TypeUint8, Value: uint8(7)}},
+		[]byte{0x02, 0x00, 0x10, 0x00, 0x20, 0x07}},
+	{"Time Of Day", TypeTimeOfDay, [4]byte{12, 30, 0, 0}, []byte{12, 30, 0, 0}},
+	{"UTC Time", TypeUTCTime, uint32(0x01020304), []byte{0x04, 0x03, 0x02, 0x01}},
+	{"Cluster ID", TypeClusterID, uint16(0x0402), []byte{0x02, 0x04}},
+	{"IEEE Address", TypeIEEEAddr, uint64(0x0013A20040522BAA), []byte{0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00}},
+}
+
+func TestValue_Encode_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Value Test Suite", func(t *testing.T) {
+		for _, tt := range valueTests {
+			tt := tt
+
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				p, err := EncodeValue(tt.t, tt.value)
+				if err != nil {
+					t.Fatalf("Expected no error, but got %v", err)
+				}
+				if !reflect.DeepEqual(p, tt.expected) {
+					t.Fatalf("Expected % x, but got % x", tt.expected, p)
+				}
+
+				v, n, err := DecodeValue(tt.t, append(p, 0xEE))
+				if err != nil {
+					t.Fatalf("Expected no error, but got %v", err)
+				}
+				if n != len(p) || !reflect.DeepEqual(v, tt.value) {
+					t.Fatalf("Expected %#v (%d bytes), but got %#v (%d bytes)", tt.value, len(p), v, n)
+				}
+			})
+		}
+	})
+}
+
+func TestValue_Encode_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     DataType
+		value interface{}
+		err   error
+	}{
+		{"Uint8 Overflow", TypeUint8, 256, ErrValue},
+		{"Uint Negative", TypeUint16, -1, ErrValue},
+		{"Int8 Overflow", TypeInt8, 128, ErrValue},
+		{"Bool Type", TypeBool, 1, ErrValue},
+		{"String Type", TypeCharString, []byte("a"), ErrValue},
+		{"Unknown Type", DataType(0x60), 1, ErrDataType},
+	}
+
+	for _, tt := range tests {
+		if _, err := EncodeValue(tt.t, tt.value); err != tt.err {
+			t.Fatalf("%s: expected error %v, but got %v", tt.name, tt.err, err)
+		}
+	}
+
+	if _, _, err := DecodeValue(TypeUint32, []byte{0x01, 0x02}); err != ErrLength {
+		t.Fatalf("Expected error %v, but got %v", ErrLength, err)
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	for _, f := range []float64{0, 1, -1, 0.5, 65504, math.Ldexp(1, -14), math.Ldexp(1, -24), 3.140625} {
+		if actual := halfToFloat64(float16(f)); actual != f {
+			t.Fatalf("Expected %v to round trip, but got %v", f, actual)
+		}
+	}
+
+	if h := float16(1e6); h != 0x7C00 {
+		t.Fatalf("Expected infinity, but got %#x", h)
+	}
+	if !math.IsNaN(halfToFloat64(float16(math.NaN()))) {
+		t.Fatalf("Expected NaN to round trip")
+	}
+}
@@ -0,0 +1,191 @@
+// Package zcl encodes and decodes Zigbee Cluster Library frames carried in
+// explicit addressing frames, the ZCL header, the general (profile-wide)
+// commands and the ZCL data types, and sends ZCL requests through the local
+// XBee correlated by sequence number.
+package zcl
+
+import (
+	"fmt"
+)
+
+// FrameType defines ZCL frame types
+type FrameType byte
+
+// ZCL frame types
+const (
+	// FrameGeneral profile-wide command, such as Read Attributes
+	FrameGeneral = FrameType(0x00)
+	// FrameCluster cluster specific command
+	FrameCluster = FrameType(0x01)
+)
+
+// Direction defines ZCL frame directions
+type Direction byte
+
+// ZCL frame directions
+const (
+	ClientToServer = Direction(0)
+	ServerToClient = Direction(1)
+)
+
+// frame control bits
+const (
+	frameTypeMask             byte = 0x03
+	manufacturerSpecificBit   byte = 0x04
+	directionBit              byte = 0x08
+	disableDefaultResponseBit byte = 0x10
+)
+
+// Header ZCL frame header
+type Header struct {
+	Type      FrameType
+	Direction Direction
+	// DisableDefaultResponse suppresses the Default Response to successful commands
+	DisableDefaultResponse bool
+	// ManufacturerSpecific the command is manufacturer specific to ManufacturerCode
+	ManufacturerSpecific bool
+	ManufacturerCode     uint16
+	Seq                  byte
+	Command              byte
+}
+
+// Frame a ZCL frame
+type Frame struct {
+	Header
+	Payload []byte
+}
+
+// Bytes encodes the frame
+func (f *Frame) Bytes() []byte {
+	fc := byte(f.Type) & frameTypeMask
+	if f.ManufacturerSpecific {
+		fc |= manufacturerSpecificBit
+	}
+	if f.Direction == ServerToClient {
+		fc |= directionBit
+	}
+	if f.DisableDefaultResponse {
+		fc |= disableDefaultResponseBit
+	}
+
+	p := []byte{fc}
+	if f.ManufacturerSpecific {
+		p = append(p, byte(f.ManufacturerCode), byte(f.ManufacturerCode>>8))
+	}
+	p = append(p, f.Seq, f.Command)
+
+	return append(p, f.Payload...)
+}
+
+// Parse decodes a ZCL frame
+func Parse(p []byte) (*Frame, error) {
+	if len(p) < 3 {
+		return nil, ErrLength
+	}
+
+	fc := p[0]
+	f := &Frame{Header: Header{
+		Type:                   FrameType(fc & frameTypeMask),
+		DisableDefaultResponse: fc&disableDefaultResponseBit != 0,
+		ManufacturerSpecific:   fc&manufacturerSpecificBit != 0,
+	}}
+	if fc&directionBit != 0 {
+		f.Direction = ServerToClient
+	}
+
+	p = p[1:]
+	if f.ManufacturerSpecific {
+		if len(p) < 4 {
+			return nil, ErrLength
+		}
+		f.ManufacturerCode = uint16(p[0]) | uint16(p[1])<<8
+		p = p[2:]
+	}
+
+	f.Seq = p[0]
+	f.Command = p[1]
+	f.Payload = append([]byte(nil), p[2:]...)
+
+	return f, nil
+}
+
+// Status ZCL status
+type Status byte
+
+// ZCL statuses
+const (
+	StatusSuccess                   = Status(0x00)
+	StatusFailure                   = Status(0x01)
+	StatusNotAuthorized             = Status(0x7E)
+	StatusMalformedCommand          = Status(0x80)
+	StatusUnsupportedClusterCommand = Status(0x81)
+	StatusUnsupportedGeneralCommand = Status(0x82)
+	StatusInvalidField              = Status(0x85)
+	StatusUnsupportedAttribute      = Status(0x86)
+	StatusInvalidValue              = Status(0x87)
+	StatusReadOnly                  = Status(0x88)
+	StatusInsufficientSpace         = Status(0x89)
+	StatusNotFound                  = Status(0x8B)
+	StatusUnreportableAttribute     = Status(0x8C)
+	StatusInvalidDataType           = Status(0x8D)
+	StatusInvalidSelector           = Status(0x8E)
+	StatusWriteOnly                 = Status(0x8F)
+	StatusTimeout                   = Status(0x94)
+	StatusAbort                     = Status(0x95)
+	StatusInvalidImage              = Status(0x96)
+	StatusWaitForData               = Status(0x97)
+	StatusNoImageAvailable          = Status(0x98)
+	StatusRequireMoreImage          = Status(0x99)
+	StatusNotificationPending       = Status(0x9A)
+	StatusHardwareFailure           = Status(0xC0)
+	StatusSoftwareFailure           = Status(0xC1)
+	StatusUnsupportedCluster        = Status(0xC3)
+)
+
+var statusNames = map[Status]string{
+	StatusSuccess:                   "success",
+	StatusFailure:                   "failure",
+	StatusNotAuthorized:             "not authorized",
+	StatusMalformedCommand:          "malformed command",
+	StatusUnsupportedClusterCommand: "unsupported cluster command",
+	StatusUnsupportedGeneralCommand: "unsupported general command",
+	StatusInvalidField:              "invalid field",
+	StatusUnsupportedAttribute:      "unsupported attribute",
+	StatusInvalidValue:              "invalid value",
+	StatusReadOnly:                  "read only",
+	StatusInsufficientSpace:         "insufficient space",
+	StatusNotFound:                  "not found",
+	StatusUnreportableAttribute:     "unreportable attribute",
+	StatusInvalidDataType:           "invalid data type",
+	StatusInvalidSelector:           "invalid selector",
+	StatusWriteOnly:                 "write only",
+	StatusTimeout:                   "timeout",
+	StatusAbort:                     "abort",
+	StatusInvalidImage:              "invalid image",
+	StatusWaitForData:               "wait for data",
+	StatusNoImageAvailable:          "no image available",
+	StatusRequireMoreImage:          "require more image",
+	StatusNotificationPending:       "notification pending",
+	StatusHardwareFailure:           "hardware failure",
+	StatusSoftwareFailure:           "software failure",
+	StatusUnsupportedCluster:        "unsupported cluster",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown (%#0.2x)", byte(s))
+}
+
+// StatusError a ZCL command failed with Status
+type StatusError struct {
+	Cluster uint16
+	Command byte
+	Status  Status
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("ZCL cluster %#0.4x command %#0.2x %s", e.Cluster, e.Command, e.Status)
+}
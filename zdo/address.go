@@ -0,0 +1,46 @@
+package zdo
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// requestTypeSingle address request type, the device's address only
+const requestTypeSingle byte = 0x00
+
+// IEEEAddr requests the 64-bit address of the device at addr16
+func (c *Client) IEEEAddr(ctx context.Context, addr16 uint16) (uint64, error) {
+	p, err := c.request(ctx, Addr64Unknown, addr16, IEEEAddrReq, append(addr16Bytes(addr16), requestTypeSingle, 0))
+	if err != nil {
+		return 0, err
+	}
+
+	addr64, _, err := parseAddr(p)
+
+	return addr64, err
+}
+
+// NWKAddr broadcasts a request for the 16-bit address of the device at addr64
+func (c *Client) NWKAddr(ctx context.Context, addr64 uint64) (uint16, error) {
+	payload := make([]byte, 10)
+	binary.LittleEndian.PutUint64(payload, addr64)
+	payload[8] = requestTypeSingle
+
+	p, err := c.request(ctx, 0xFFFF, BroadcastRxOnWhenIdle, NWKAddrReq, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	_, addr16, err := parseAddr(p)
+
+	return addr16, err
+}
+
+// parseAddr decodes the 64-bit and 16-bit address of IEEE_addr and NWK_addr responses
+func parseAddr(p []byte) (uint64, uint16, error) {
+	if len(p) < 10 {
+		return 0, 0, ErrLength
+	}
+
+	return binary.LittleEndian.Uint64(p), binary.LittleEndian.Uint16(p[8:]), nil
+}
@@ -0,0 +1,131 @@
+package zdo
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// AddrMode binding destination address mode
+type AddrMode byte
+
+// Binding destination address modes
+const (
+	AddrModeGroup  = AddrMode(0x01)
+	AddrModeAddr64 = AddrMode(0x03)
+)
+
+// Binding a source endpoint's cluster bound to a group, or to a destination
+// endpoint
+type Binding struct {
+	SrcAddr64   uint64
+	SrcEndpoint byte
+	ClusterID   uint16
+	DstAddrMode AddrMode
+	// DstGroup destination group, AddrModeGroup only
+	DstGroup uint16
+	// DstAddr64 and DstEndpoint destination endpoint, AddrModeAddr64 only
+	DstAddr64   uint64
+	DstEndpoint byte
+}
+
+// Bytes encodes the binding as carried by Bind_req
+func (b *Binding) Bytes() []byte {
+	p := make([]byte, 12, 21)
+	binary.LittleEndian.PutUint64(p, b.SrcAddr64)
+	p[8] = b.SrcEndpoint
+	binary.LittleEndian.PutUint16(p[9:], b.ClusterID)
+	p[11] = byte(b.DstAddrMode)
+
+	if b.DstAddrMode == AddrModeGroup {
+		return append(p, byte(b.DstGroup), byte(b.DstGroup>>8))
+	}
+
+	p = p[:21]
+	binary.LittleEndian.PutUint64(p[12:], b.DstAddr64)
+	p[20] = b.DstEndpoint
+
+	return p
+}
+
+// Bind creates binding b on the device at addr64/addr16 holding its source
+func (c *Client) Bind(ctx context.Context, addr64 uint64, addr16 uint16, b *Binding) error {
+	_, err := c.request(ctx, addr64, addr16, BindReq, b.Bytes())
+	return err
+}
+
+// Unbind removes binding b from the device at addr64/addr16 holding its source
+func (c *Client) Unbind(ctx context.Context, addr64 uint64, addr16 uint16, b *Binding) error {
+	_, err := c.request(ctx, addr64, addr16, UnbindReq, b.Bytes())
+	return err
+}
+
+// ParseBinding decodes a binding, returning the bytes used
+func ParseBinding(p []byte) (*Binding, int, error) {
+	if len(p) < 14 {
+		return nil, 0, ErrLength
+	}
+
+	b := &Binding{
+		SrcAddr64:   binary.LittleEndian.Uint64(p),
+		SrcEndpoint: p[8],
+		ClusterID:   binary.LittleEndian.Uint16(p[9:]),
+		DstAddrMode: AddrMode(p[11]),
+	}
+
+	if b.DstAddrMode == AddrModeGroup {
+		b.DstGroup = binary.LittleEndian.Uint16(p[12:])
+		return b, 14, nil
+	}
+
+	if len(p) < 21 {
+		return nil, 0, ErrLength
+	}
+	b.DstAddr64 = binary.LittleEndian.Uint64(p[12:])
+	b.DstEndpoint = p[20]
+
+	return b, 21, nil
+}
+
+// BindingTable a page of a device's binding table
+type BindingTable struct {
+	// Entries total binding table entries
+	Entries    byte
+	StartIndex byte
+	Bindings   []Binding
+}
+
+// BindingTable requests the binding table of the device at addr64/addr16 from
+// entry start
+func (c *Client) BindingTable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*BindingTable, error) {
+	p, err := c.request(ctx, addr64, addr16, MgmtBindReq, []byte{start})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBindingTable(p)
+}
+
+// ParseBindingTable decodes a Mgmt_Bind response following the status
+func ParseBindingTable(p []byte) (*BindingTable, error) {
+	if len(p) < 3 {
+		return nil, ErrLength
+	}
+
+	t := &BindingTable{
+		Entries:    p[0],
+		StartIndex: p[1],
+		Bindings:   make([]Binding, p[2]),
+	}
+
+	p = p[3:]
+	for i := range t.Bindings {
+		b, n, err := ParseBinding(p)
+		if err != nil {
+			return nil, err
+		}
+		t.Bindings[i] = *b
+		p = p[n:]
+	}
+
+	return t, nil
+}
@@ -0,0 +1,223 @@
+package zdo
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrLength ZDO payload too short
+var ErrLength = errors.New("invalid ZDO payload length")
+
+// LogicalType defines node descriptor logical types
+type LogicalType byte
+
+// Node descriptor logical types
+const (
+	LogicalCoordinator = LogicalType(0)
+	LogicalRouter      = LogicalType(1)
+	LogicalEndDevice   = LogicalType(2)
+)
+
+// nodeDescriptorLength node descriptor length
+const nodeDescriptorLength = 13
+
+// NodeDescriptor a device's node descriptor
+type NodeDescriptor struct {
+	LogicalType          LogicalType
+	ComplexDescriptor    bool
+	UserDescriptor       bool
+	APSFlags             byte
+	FrequencyBand        byte
+	MACCapabilities      byte
+	ManufacturerCode     uint16
+	MaxBufferSize        byte
+	MaxIncomingTransfer  uint16
+	ServerMask           uint16
+	MaxOutgoingTransfer  uint16
+	DescriptorCapability byte
+}
+
+// ParseNodeDescriptor decodes a node descriptor
+func ParseNodeDescriptor(p []byte) (*NodeDescriptor, error) {
+	if len(p) < nodeDescriptorLength {
+		return nil, ErrLength
+	}
+
+	return &NodeDescriptor{
+		LogicalType:          LogicalType(p[0] & 0x07),
+		ComplexDescriptor:    p[0]&0x08 != 0,
+		UserDescriptor:       p[0]&0x10 != 0,
+		APSFlags:             p[1] & 0x07,
+		FrequencyBand:        p[1] >> 3,
+		MACCapabilities:      p[2],
+		ManufacturerCode:     binary.LittleEndian.Uint16(p[3:]),
+		MaxBufferSize:        p[5],
+		MaxIncomingTransfer:  binary.LittleEndian.Uint16(p[6:]),
+		ServerMask:           binary.LittleEndian.Uint16(p[8:]),
+		MaxOutgoingTransfer:  binary.LittleEndian.Uint16(p[10:]),
+		DescriptorCapability: p[12],
+	}, nil
+}
+
+// Bytes encodes the node descriptor
+func (d *NodeDescriptor) Bytes() []byte {
+	p := make([]byte, nodeDescriptorLength)
+
+	p[0] = byte(d.LogicalType) & 0x07
+	if d.ComplexDescriptor {
+		p[0] |= 0x08
+	}
+	if d.UserDescriptor {
+		p[0] |= 0x10
+	}
+	p[1] = d.APSFlags&0x07 | d.FrequencyBand<<3
+	p[2] = d.MACCapabilities
+	binary.LittleEndian.PutUint16(p[3:], d.ManufacturerCode)
+	p[5] = d.MaxBufferSize
+	binary.LittleEndian.PutUint16(p[6:], d.MaxIncomingTransfer)
+	binary.LittleEndian.PutUint16(p[8:], d.ServerMask)
+	binary.LittleEndian.PutUint16(p[10:], d.MaxOutgoingTransfer)
+	p[12] = d.DescriptorCapability
+
+	return p
+}
+
+// SimpleDescriptor an endpoint's simple descriptor
+type SimpleDescriptor struct {
+	Endpoint       byte
+	ProfileID      uint16
+	DeviceID       uint16
+	DeviceVersion  byte
+	InputClusters  []uint16
+	OutputClusters []uint16
+}
+
+// ParseSimpleDescriptor decodes a simple descriptor
+func ParseSimpleDescriptor(p []byte) (*SimpleDescriptor, error) {
+	if len(p) < 7 {
+		return nil, ErrLength
+	}
+
+	d := &SimpleDescriptor{
+		Endpoint:      p[0],
+		ProfileID:     binary.LittleEndian.Uint16(p[1:]),
+		DeviceID:      binary.LittleEndian.Uint16(p[3:]),
+		DeviceVersion: p[5] & 0x0F,
+	}
+
+	var err error
+	if d.InputClusters, p, err = parseClusters(p[6:]); err != nil {
+		return nil, err
+	}
+	if d.OutputClusters, _, err = parseClusters(p); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Bytes encodes the simple descriptor
+func (d *SimpleDescriptor) Bytes() []byte {
+	p := []byte{d.Endpoint, 0, 0, 0, 0, d.DeviceVersion & 0x0F}
+	binary.LittleEndian.PutUint16(p[1:], d.ProfileID)
+	binary.LittleEndian.PutUint16(p[3:], d.DeviceID)
+
+	p = appendClusters(p, d.InputClusters)
+
+	return appendClusters(p, d.OutputClusters)
+}
+
+// parseClusters decodes a count prefixed cluster list, returning the remaining bytes
+func parseClusters(p []byte) ([]uint16, []byte, error) {
+	if len(p) < 1 || len(p) < 1+2*int(p[0]) {
+		return nil, nil, ErrLength
+	}
+
+	clusters := make([]uint16, p[0])
+	for i := range clusters {
+		clusters[i] = binary.LittleEndian.Uint16(p[1+2*i:])
+	}
+
+	return clusters, p[1+2*len(clusters):], nil
+}
+
+// appendClusters appends a count prefixed cluster list
+func appendClusters(p []byte, clusters []uint16) []byte {
+	p = append(p, byte(len(clusters)))
+	for _, c := range clusters {
+		p = append(p, byte(c), byte(c>>8))
+	}
+
+	return p
+}
+
+// addr16Bytes little-endian 16-bit address
+func addr16Bytes(addr16 uint16) []byte {
+	return []byte{byte(addr16), byte(addr16 >> 8)}
+}
+
+// NodeDescriptor requests the node descriptor of the device at addr16 from
+// the device at addr64/addr16
+func (c *Client) NodeDescriptor(ctx context.Context, addr64 uint64, addr16 uint16) (*NodeDescriptor, error) {
+	p, err := c.request(ctx, addr64, addr16, NodeDescReq, addr16Bytes(addr16))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p) < 2 {
+		return nil, ErrLength
+	}
+
+	return ParseNodeDescriptor(p[2:])
+}
+
+// ActiveEndpoints requests the active endpoints of the device at addr64/addr16
+func (c *Client) ActiveEndpoints(ctx context.Context, addr64 uint64, addr16 uint16) ([]byte, error) {
+	p, err := c.request(ctx, addr64, addr16, ActiveEPReq, addr16Bytes(addr16))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEndpoints(p)
+}
+
+// SimpleDescriptor requests the simple descriptor of endpoint on the device
+// at addr64/addr16
+func (c *Client) SimpleDescriptor(ctx context.Context, addr64 uint64, addr16 uint16, endpoint byte) (*SimpleDescriptor, error) {
+	p, err := c.request(ctx, addr64, addr16, SimpleDescReq, append(addr16Bytes(addr16), endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p) < 3 || len(p) < 3+int(p[2]) {
+		return nil, ErrLength
+	}
+
+	return ParseSimpleDescriptor(p[3 : 3+int(p[2])])
+}
+
+// MatchDescriptor requests the endpoints of the device at addr64/addr16
+// matching profile with any of the input or output clusters
+func (c *Client) MatchDescriptor(ctx context.Context, addr64 uint64, addr16 uint16, profile uint16, in, out []uint16) ([]byte, error) {
+	payload := append(addr16Bytes(addr16), byte(profile), byte(profile>>8))
+	payload = appendClusters(payload, in)
+	payload = appendClusters(payload, out)
+
+	p, err := c.request(ctx, addr64, addr16, MatchDescReq, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEndpoints(p)
+}
+
+// parseEndpoints decodes the address of interest and count prefixed endpoint
+// list of Active_EP and Match_Desc responses
+func parseEndpoints(p []byte) ([]byte, error) {
+	if len(p) < 3 || len(p) < 3+int(p[2]) {
+		return nil, ErrLength
+	}
+
+	return append([]byte(nil), p[3:3+int(p[2])]...), nil
+}
@@ -0,0 +1,202 @@
+package zdo
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// neighborLength Mgmt_Lqi neighbor table entry length
+const neighborLength = 22
+
+// routeLength Mgmt_Rtg routing table entry length
+const routeLength = 5
+
+// DeviceType defines neighbor device types
+type DeviceType byte
+
+// Neighbor device types
+const (
+	DeviceCoordinator = DeviceType(0)
+	DeviceRouter      = DeviceType(1)
+	DeviceEndDevice   = DeviceType(2)
+	DeviceUnknown     = DeviceType(3)
+)
+
+// Relationship defines neighbor relationships
+type Relationship byte
+
+// Neighbor relationships
+const (
+	RelationshipParent        = Relationship(0)
+	RelationshipChild         = Relationship(1)
+	RelationshipSibling       = Relationship(2)
+	RelationshipNone          = Relationship(3)
+	RelationshipPreviousChild = Relationship(4)
+)
+
+// Neighbor a neighbor table entry
+type Neighbor struct {
+	ExtendedPanID uint64
+	Addr64        uint64
+	Addr16        uint16
+	DeviceType    DeviceType
+	// RxOnWhenIdle 0 off, 1 on, 2 unknown
+	RxOnWhenIdle byte
+	Relationship Relationship
+	// PermitJoining 0 not accepting, 1 accepting, 2 unknown
+	PermitJoining byte
+	Depth         byte
+	LQI           byte
+}
+
+// NeighborTable a page of a device's neighbor table
+type NeighborTable struct {
+	// Entries total neighbor table entries
+	Entries    byte
+	StartIndex byte
+	Neighbors  []Neighbor
+}
+
+// RouteStatus defines routing table entry statuses
+type RouteStatus byte
+
+// Routing table entry statuses
+const (
+	RouteActive             = RouteStatus(0)
+	RouteDiscoveryUnderway  = RouteStatus(1)
+	RouteDiscoveryFailed    = RouteStatus(2)
+	RouteInactive           = RouteStatus(3)
+	RouteValidationUnderway = RouteStatus(4)
+)
+
+// Route a routing table entry
+type Route struct {
+	Destination         uint16
+	Status              RouteStatus
+	MemoryConstrained   bool
+	ManyToOne           bool
+	RouteRecordRequired bool
+	NextHop             uint16
+}
+
+// RoutingTable a page of a device's routing table
+type RoutingTable struct {
+	// Entries total routing table entries
+	Entries    byte
+	StartIndex byte
+	Routes     []Route
+}
+
+// LQITable requests the neighbor table of the device at addr64/addr16 from
+// entry start
+func (c *Client) LQITable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*NeighborTable, error) {
+	p, err := c.request(ctx, addr64, addr16, MgmtLqiReq, []byte{start})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNeighborTable(p)
+}
+
+// ParseNeighborTable decodes a Mgmt_Lqi response following the status
+func ParseNeighborTable(p []byte) (*NeighborTable, error) {
+	if len(p) < 3 || len(p) < 3+neighborLength*int(p[2]) {
+		return nil, ErrLength
+	}
+
+	t := &NeighborTable{
+		Entries:    p[0],
+		StartIndex: p[1],
+		Neighbors:  make([]Neighbor, p[2]),
+	}
+
+	for i := range t.Neighbors {
+		e := p[3+neighborLength*i:]
+		t.Neighbors[i] = Neighbor{
+			ExtendedPanID: binary.LittleEndian.Uint64(e),
+			Addr64:        binary.LittleEndian.Uint64(e[8:]),
+			Addr16:        binary.LittleEndian.Uint16(e[16:]),
+			DeviceType:    DeviceType(e[18] & 0x03),
+			RxOnWhenIdle:  e[18] >> 2 & 0x03,
+			Relationship:  Relationship(e[18] >> 4 & 0x07),
+			PermitJoining: e[19] & 0x03,
+			Depth:         e[20],
+			LQI:           e[21],
+		}
+	}
+
+	return t, nil
+}
+
+// RoutingTable requests the routing table of the device at addr64/addr16 from
+// entry start
+func (c *Client) RoutingTable(ctx context.Context, addr64 uint64, addr16 uint16, start byte) (*RoutingTable, error) {
+	p, err := c.request(ctx, addr64, addr16, MgmtRtgReq, []byte{start})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRoutingTable(p)
+}
+
+// ParseRoutingTable decodes a Mgmt_Rtg response following the status
+func ParseRoutingTable(p []byte) (*RoutingTable, error) {
+	if len(p) < 3 || len(p) < 3+routeLength*int(p[2]) {
+		return nil, ErrLength
+	}
+
+	t := &RoutingTable{
+		Entries:    p[0],
+		StartIndex: p[1],
+		Routes:     make([]Route, p[2]),
+	}
+
+	for i := range t.Routes {
+		e := p[3+routeLength*i:]
+		t.Routes[i] = Route{
+			Destination:         binary.LittleEndian.Uint16(e),
+			Status:              RouteStatus(e[2] & 0x07),
+			MemoryConstrained:   e[2]&0x08 != 0,
+			ManyToOne:           e[2]&0x10 != 0,
+			RouteRecordRequired: e[2]&0x20 != 0,
+			NextHop:             binary.LittleEndian.Uint16(e[3:]),
+		}
+	}
+
+	return t, nil
+}
+
+// Leave requests the device at addr64/addr16 to remove device, its own 64-bit
+// address or that of a child, from the network
+func (c *Client) Leave(ctx context.Context, addr64 uint64, addr16 uint16, device uint64, rejoin, removeChildren bool) error {
+	payload := make([]byte, 9)
+	binary.LittleEndian.PutUint64(payload, device)
+	if removeChildren {
+		payload[8] |= 0x40
+	}
+	if rejoin {
+		payload[8] |= 0x80
+	}
+
+	_, err := c.request(ctx, addr64, addr16, MgmtLeaveReq, payload)
+
+	return err
+}
+
+// PermitJoining requests the device at addr64/addr16 to permit joining for
+// duration seconds, 0 disables and 0xFF permits joining indefinitely.
+// Broadcasts, such as to BroadcastRouters, do not wait for responses.
+func (c *Client) PermitJoining(ctx context.Context, addr64 uint64, addr16 uint16, duration byte, tcSignificance bool) error {
+	payload := []byte{duration, 0}
+	if tcSignificance {
+		payload[1] = 1
+	}
+
+	if isBroadcast(addr64, addr16) {
+		return c.send(ctx, addr64, addr16, MgmtPermitJoiningReq, append([]byte{c.nextSeq()}, payload...))
+	}
+
+	_, err := c.request(ctx, addr64, addr16, MgmtPermitJoiningReq, payload)
+
+	return err
+}
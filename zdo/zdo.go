@@ -0,0 +1,201 @@
+// Package zdo is a Zigbee Device Object client. ZDO requests and responses
+// are carried in explicit addressing frames on profile 0, endpoint 0, with the
+// response cluster being the request cluster with the high bit set. Receiving
+// responses requires the local XBee's AO set to 1.
+package zdo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api/rx"
+	"github.com/pauleyj/gobee/api/tx"
+)
+
+// ZDO addressing
+const (
+	// Profile ZDO profile ID
+	Profile uint16 = 0x0000
+	// Endpoint ZDO endpoint
+	Endpoint byte = 0x00
+	// ResponseCluster bit set in the cluster ID of responses
+	ResponseCluster uint16 = 0x8000
+)
+
+// Request cluster IDs
+const (
+	NWKAddrReq           uint16 = 0x0000
+	IEEEAddrReq          uint16 = 0x0001
+	NodeDescReq          uint16 = 0x0002
+	SimpleDescReq        uint16 = 0x0004
+	ActiveEPReq          uint16 = 0x0005
+	MatchDescReq         uint16 = 0x0006
+	BindReq              uint16 = 0x0021
+	UnbindReq            uint16 = 0x0022
+	MgmtLqiReq           uint16 = 0x0031
+	MgmtRtgReq           uint16 = 0x0032
+	MgmtBindReq          uint16 = 0x0033
+	MgmtLeaveReq         uint16 = 0x0034
+	MgmtPermitJoiningReq uint16 = 0x0036
+)
+
+// Broadcast 16-bit addresses
+const (
+	// BroadcastAll all devices
+	BroadcastAll uint16 = 0xFFFF
+	// BroadcastRxOnWhenIdle all devices with receiver on when idle
+	BroadcastRxOnWhenIdle uint16 = 0xFFFD
+	// BroadcastRouters coordinator and routers
+	BroadcastRouters uint16 = 0xFFFC
+)
+
+// Addr64Unknown 64-bit address used when addressing by 16-bit address only
+const Addr64Unknown uint64 = 0xFFFFFFFFFFFFFFFF
+
+// Status ZDO response status
+type Status byte
+
+// ZDO response statuses
+const (
+	StatusSuccess           = Status(0x00)
+	StatusInvRequestType    = Status(0x80)
+	StatusDeviceNotFound    = Status(0x81)
+	StatusInvalidEP         = Status(0x82)
+	StatusNotActive         = Status(0x83)
+	StatusNotSupported      = Status(0x84)
+	StatusTimeout           = Status(0x85)
+	StatusNoMatch           = Status(0x86)
+	StatusNoEntry           = Status(0x88)
+	StatusNoDescriptor      = Status(0x89)
+	StatusInsufficientSpace = Status(0x8A)
+	StatusNotPermitted      = Status(0x8B)
+	StatusTableFull         = Status(0x8C)
+	StatusNotAuthorized     = Status(0x8D)
+)
+
+var statusNames = map[Status]string{
+	StatusSuccess:           "success",
+	StatusInvRequestType:    "invalid request type",
+	StatusDeviceNotFound:    "device not found",
+	StatusInvalidEP:         "invalid endpoint",
+	StatusNotActive:         "not active",
+	StatusNotSupported:      "not supported",
+	StatusTimeout:           "timeout",
+	StatusNoMatch:           "no match",
+	StatusNoEntry:           "no entry",
+	StatusNoDescriptor:      "no descriptor",
+	StatusInsufficientSpace: "insufficient space",
+	StatusNotPermitted:      "not permitted",
+	StatusTableFull:         "table full",
+	StatusNotAuthorized:     "not authorized",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown (%#0.2x)", byte(s))
+}
+
+// StatusError a ZDO response reported a status other than success
+type StatusError struct {
+	Cluster uint16
+	Status  Status
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("ZDO cluster %#0.4x %s", e.Cluster, e.Status)
+}
+
+// Client sends ZDO requests through the local XBee and correlates responses
+// by sequence number
+type Client struct {
+	xbee *gobee.XBee
+
+	mu  sync.Mutex
+	seq byte
+}
+
+// New constructs a ZDO Client
+func New(xbee *gobee.XBee) *Client {
+	return &Client{xbee: xbee}
+}
+
+// nextSeq next ZDO transaction sequence number
+func (c *Client) nextSeq() byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+
+	return c.seq
+}
+
+// isBroadcast is addr64/addr16 a broadcast address
+func isBroadcast(addr64 uint64, addr16 uint16) bool {
+	return addr64 == 0xFFFF || addr16 >= 0xFFF8 && addr16 != 0xFFFE
+}
+
+// request sends the ZDO request on cluster to the device at addr64/addr16 and
+// returns the response payload following the sequence number and status.
+// Unicasts are answered by the addressed device, broadcasts by any device.
+func (c *Client) request(ctx context.Context, addr64 uint64, addr16 uint16, cluster uint16, payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	seq := c.nextSeq()
+	broadcast := isBroadcast(addr64, addr16)
+
+	responses := c.xbee.Subscribe(ctx, func(f rx.Frame) bool {
+		r, ok := f.(*rx.ZBExplicit)
+		if !ok || r.ProfileID() != Profile || r.ClusterID() != cluster|ResponseCluster {
+			return false
+		}
+		if len(r.Data()) < 2 || r.Data()[0] != seq {
+			return false
+		}
+		if broadcast {
+			return true
+		}
+		if addr64 != Addr64Unknown {
+			return r.Addr64() == addr64
+		}
+		return r.Addr16() == addr16
+	})
+
+	err := c.send(ctx, addr64, addr16, cluster, append([]byte{seq}, payload...))
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case f, ok := <-responses:
+		if !ok {
+			return nil, ctx.Err()
+		}
+
+		data := f.(*rx.ZBExplicit).Data()
+		if status := Status(data[1]); status != StatusSuccess {
+			return nil, &StatusError{Cluster: cluster, Status: status}
+		}
+
+		return data[2:], nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// send transmits a ZDO frame without waiting for a response
+func (c *Client) send(ctx context.Context, addr64 uint64, addr16 uint16, cluster uint16, data []byte) error {
+	return c.xbee.SendExplicit(ctx,
+		tx.Addr64(addr64),
+		tx.Addr16(addr16),
+		tx.SrcEP(Endpoint),
+		tx.DstEP(Endpoint),
+		tx.ClusterID(cluster),
+		tx.ProfileID(Profile),
+		tx.Data(data))
+}
@@ -0,0 +1,391 @@
+package zdo
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pauleyj/gobee"
+	"github.com/pauleyj/gobee/api"
+	"github.com/pauleyj/gobee/api/rx"
+)
+
+var (
+	testAddr64 = uint64(0x0013A20040522BAA)
+	testAddr16 = uint16(0x1234)
+)
+
+// radio a fake XBee answering transmitted frame data with reply
+type radio struct {
+	mu    sync.Mutex
+	xbee  *gobee.XBee
+	reply func(p []byte) [][]byte
+}
+
+type nopReceiver struct{}
+
+func (r *nopReceiver) Receive(f rx.Frame) error {
+	return nil
+}
+
+func newRadio(reply func(p []byte) [][]byte) *gobee.XBee {
+	r := &radio{reply: reply}
+	r.xbee = gobee.New(r, &nopReceiver{}, gobee.APIEscapeMode(api.EscapeModeInactive))
+
+	return r.xbee
+}
+
+func (r *radio) Transmit(p []byte) (int, error) {
+	frames := r.reply(p[3 : len(p)-1])
+	go r.send(frames...)
+
+	return len(p), nil
+}
+
+func (r *radio) send(frames ...[]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range frames {
+		sum := byte(0)
+		for _, b := range f {
+			sum += b
+		}
+
+		r.xbee.RX(0x7E)
+		r.xbee.RX(byte(len(f) >> 8))
+		r.xbee.RX(byte(len(f)))
+		for _, b := range f {
+			r.xbee.RX(b)
+		}
+		r.xbee.RX(0xFF - sum)
+	}
+}
+
+// zdoRequest an explicit addressing frame as seen by the fake radio
+type zdoRequest struct {
+	id      byte
+	addr64  uint64
+	addr16  uint16
+	cluster uint16
+	seq     byte
+	payload []byte
+}
+
+func parseRequest(t *testing.T, p []byte) zdoRequest {
+	if p[0] != 0x11 || p[12] != 0 || p[13] != 0 || p[16] != 0 || p[17] != 0 {
+		t.Fatalf("Expected ZDO explicit frame, but got % x", p)
+	}
+
+	var addr64 uint64
+	for _, b := range p[2:10] {
+		addr64 = addr64<<8 | uint64(b)
+	}
+
+	return zdoRequest{
+		id:      p[1],
+		addr64:  addr64,
+		addr16:  uint16(p[10])<<8 | uint16(p[11]),
+		cluster: uint16(p[14])<<8 | uint16(p[15]),
+		seq:     p[20],
+		payload: p[21:],
+	}
+}
+
+// replies transmit status and ZDO response from testAddr64/testAddr16 to r
+func replies(r zdoRequest, status Status, payload ...byte) [][]byte {
+	response := []byte{0x91,
+		byte(testAddr64 >> 56), byte(testAddr64 >> 48), byte(testAddr64 >> 40), byte(testAddr64 >> 32),
+		byte(testAddr64 >> 24), byte(testAddr64 >> 16), byte(testAddr64 >> 8), byte(testAddr64),
+		byte(testAddr16 >> 8), byte(testAddr16),
+		0x00, 0x00, byte(r.cluster>>8) | 0x80, byte(r.cluster), 0x00, 0x00, 0x01,
+		r.seq, byte(status)}
+
+	return [][]byte{
+		{0x8B, r.id, 0x12, 0x34, 0x00, 0x00, 0x00},
+		append(response, payload...),
+	}
+}
+
+func zdoContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Second)
+}
+
+func TestClient_ActiveEndpoints(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != ActiveEPReq || r.addr64 != testAddr64 || !reflect.DeepEqual(r.payload, []byte{0x34, 0x12}) {
+			t.Fatalf("Expected Active_EP_req, but got %+v", r)
+		}
+		return replies(r, StatusSuccess, 0x34, 0x12, 0x02, 0x01, 0xE8)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	eps, err := New(xbee).ActiveEndpoints(ctx, testAddr64, testAddr16)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(eps, []byte{0x01, 0xE8}) {
+		t.Fatalf("Expected endpoints [01 e8], but got % x", eps)
+	}
+}
+
+func TestClient_SimpleDescriptor(t *testing.T) {
+	expected := &SimpleDescriptor{
+		Endpoint:       0x01,
+		ProfileID:      0x0104,
+		DeviceID:       0x0302,
+		DeviceVersion:  1,
+		InputClusters:  []uint16{0x0000, 0x0402},
+		OutputClusters: []uint16{},
+	}
+
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != SimpleDescReq || !reflect.DeepEqual(r.payload, []byte{0x34, 0x12, 0x01}) {
+			t.Fatalf("Expected Simple_Desc_req, but got %+v", r)
+		}
+		d := expected.Bytes()
+		return replies(r, StatusSuccess, append([]byte{0x34, 0x12, byte(len(d))}, d...)...)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	d, err := New(xbee).SimpleDescriptor(ctx, testAddr64, testAddr16, 0x01)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, d)
+	}
+}
+
+func TestClient_NodeDescriptor(t *testing.T) {
+	expected := &NodeDescriptor{
+		LogicalType:         LogicalRouter,
+		FrequencyBand:       0x08,
+		MACCapabilities:     0x8E,
+		ManufacturerCode:    0x101E,
+		MaxBufferSize:       0x52,
+		MaxIncomingTransfer: 0x0080,
+		ServerMask:          0x0000,
+		MaxOutgoingTransfer: 0x0080,
+	}
+
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		return replies(r, StatusSuccess, append([]byte{0x34, 0x12}, expected.Bytes()...)...)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	d, err := New(xbee).NodeDescriptor(ctx, testAddr64, testAddr16)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, d)
+	}
+}
+
+func TestClient_IEEEAddr(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != IEEEAddrReq || r.addr64 != Addr64Unknown || r.addr16 != testAddr16 {
+			t.Fatalf("Expected IEEE_addr_req to 0x1234, but got %+v", r)
+		}
+		return replies(r, StatusSuccess, 0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x34, 0x12)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	addr64, err := New(xbee).IEEEAddr(ctx, testAddr16)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if addr64 != testAddr64 {
+		t.Fatalf("Expected %#x, but got %#x", testAddr64, addr64)
+	}
+}
+
+func TestClient_LQITable(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != MgmtLqiReq || !reflect.DeepEqual(r.payload, []byte{0x02}) {
+			t.Fatalf("Expected Mgmt_Lqi_req from 2, but got %+v", r)
+		}
+		return replies(r, StatusSuccess,
+			0x03, 0x02, 0x01,
+			0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0xBB, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00,
+			0x78, 0x56, 0x25, 0x02, 0x01, 0xFF)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	table, err := New(xbee).LQITable(ctx, testAddr64, testAddr16, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := &NeighborTable{
+		Entries:    3,
+		StartIndex: 2,
+		Neighbors: []Neighbor{{
+			ExtendedPanID: 1,
+			Addr64:        0x0013A20040522BBB,
+			Addr16:        0x5678,
+			DeviceType:    DeviceRouter,
+			RxOnWhenIdle:  1,
+			Relationship:  RelationshipSibling,
+			PermitJoining: 2,
+			Depth:         1,
+			LQI:           0xFF,
+		}},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, table)
+	}
+}
+
+func TestClient_RoutingTable(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		return replies(parseRequest(t, p), StatusSuccess, 0x01, 0x00, 0x01, 0x78, 0x56, 0x10, 0x34, 0x12)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	table, err := New(xbee).RoutingTable(ctx, testAddr64, testAddr16, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := []Route{{Destination: 0x5678, Status: RouteActive, ManyToOne: true, NextHop: 0x1234}}
+	if table.Entries != 1 || !reflect.DeepEqual(table.Routes, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, table)
+	}
+}
+
+func TestClient_Leave_Status(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != MgmtLeaveReq || r.payload[8] != 0x80 {
+			t.Fatalf("Expected Mgmt_Leave_req with rejoin, but got %+v", r)
+		}
+		return replies(r, StatusNotSupported)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	err := New(xbee).Leave(ctx, testAddr64, testAddr16, testAddr64, true, false)
+	if e, ok := err.(*StatusError); !ok || e.Status != StatusNotSupported {
+		t.Fatalf("Expected not supported status error, but got %v", err)
+	}
+}
+
+func TestClient_PermitJoining_Broadcast(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != MgmtPermitJoiningReq || !reflect.DeepEqual(r.payload, []byte{0x3C, 0x00}) {
+			t.Fatalf("Expected Mgmt_Permit_Joining_req, but got %+v", r)
+		}
+		return replies(r, StatusSuccess)[:1]
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	if err := New(xbee).PermitJoining(ctx, 0xFFFF, BroadcastRouters, 60, false); err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+}
+
+func TestClient_Bind(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		name     string
+		binding  Binding
+		expected []byte
+	}{
+		{
+			"Endpoint",
+			Binding{SrcAddr64: testAddr64, SrcEndpoint: 0x01, ClusterID: 0x0402, DstAddrMode: AddrModeAddr64, DstAddr64: 0x0013A20040A1B2C3, DstEndpoint: 0xE8},
+			[]byte{0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x01, 0x02, 0x04, 0x03, 0xC3, 0xB2, 0xA1, 0x40, 0x00, 0xA2, 0x13, 0x00, 0xE8},
+		},
+		{
+			"Group",
+			Binding{SrcAddr64: testAddr64, SrcEndpoint: 0x01, ClusterID: 0x0006, DstAddrMode: AddrModeGroup, DstGroup: 0x0010},
+			[]byte{0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x01, 0x06, 0x00, 0x01, 0x10, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			xbee := newRadio(func(p []byte) [][]byte {
+				r := parseRequest(t, p)
+				if r.cluster != BindReq || !reflect.DeepEqual(r.payload, tt.expected) {
+					t.Fatalf("Expected Bind_req % x, but got %+v", tt.expected, r)
+				}
+				return replies(r, StatusSuccess)
+			})
+
+			ctx, cancel := zdoContext()
+			defer cancel()
+
+			if err := New(xbee).Bind(ctx, testAddr64, testAddr16, &tt.binding); err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_BindingTable(t *testing.T) {
+	xbee := newRadio(func(p []byte) [][]byte {
+		r := parseRequest(t, p)
+		if r.cluster != MgmtBindReq || !reflect.DeepEqual(r.payload, []byte{0x00}) {
+			t.Fatalf("Expected Mgmt_Bind_req, but got %+v", r)
+		}
+		return replies(r, StatusSuccess, 0x02, 0x00, 0x02,
+			0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x01, 0x06, 0x00, 0x01, 0x10, 0x00,
+			0xAA, 0x2B, 0x52, 0x40, 0x00, 0xA2, 0x13, 0x00, 0x01, 0x02, 0x04, 0x03, 0xC3, 0xB2, 0xA1, 0x40, 0x00, 0xA2, 0x13, 0x00, 0xE8)
+	})
+
+	ctx, cancel := zdoContext()
+	defer cancel()
+
+	table, err := New(xbee).BindingTable(ctx, testAddr64, testAddr16, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+
+	expected := &BindingTable{
+		Entries: 2,
+		Bindings: []Binding{
+			{SrcAddr64: testAddr64, SrcEndpoint: 0x01, ClusterID: 0x0006, DstAddrMode: AddrModeGroup, DstGroup: 0x0010},
+			{SrcAddr64: testAddr64, SrcEndpoint: 0x01, ClusterID: 0x0402, DstAddrMode: AddrModeAddr64, DstAddr64: 0x0013A20040A1B2C3, DstEndpoint: 0xE8},
+		},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, table)
+	}
+}
+
+func TestParseBindingTable_Short(t *testing.T) {
+	if _, err := ParseBindingTable([]byte{0x01, 0x00, 0x01, 0xAA}); err != ErrLength {
+		t.Fatalf("Expected ErrLength, but got %v", err)
+	}
+}